@@ -0,0 +1,68 @@
+package bridge
+
+import (
+	"net"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/event"
+	"github.com/flashbots/vpnham/monitor"
+	"github.com/flashbots/vpnham/transponder/bfd"
+)
+
+func newBFDSession(bridgeName string, ifs *config.TunnelInterface) (*bfd.Session, error) {
+	localIP, localPort, err := ifs.Addr.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	peerIP, peerPort, err := ifs.ProbeAddr.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return bfd.New(bridgeName, ifs.Name, bfd.Config{
+		LocalAddr: &net.UDPAddr{IP: localIP, Port: localPort},
+		PeerAddr:  &net.UDPAddr{IP: peerIP, Port: peerPort},
+
+		DesiredMinTxInterval:  ifs.BFD.DesiredMinTxInterval,
+		RequiredMinRxInterval: ifs.BFD.RequiredMinRxInterval,
+		DetectMultiplier:      uint8(ifs.BFD.DetectMultiplier),
+	})
+}
+
+// watchBFDSession makes the bfd session report its state changes right away
+// (instead of waiting for them to be sampled by the probe loop).
+func (s *Server) watchBFDSession(ifsName string, session *bfd.Session) {
+	session.OnStateChange = func(from, to bfd.State, diag bfd.Diag) {
+		s.events <- &event.TunnelInterfaceBFDStateChanged{ // emit event
+			TunnelInterface: ifsName,
+			Timestamp:       time.Now(),
+
+			From: from.String(),
+			To:   to.String(),
+			Diag: diag.String(),
+		}
+	}
+}
+
+// bfdStatus returns the status of the tunnel interface as reported by its bfd
+// session (if it has one).  The session does its own failure detection, so
+// its state is used as-is (without the thresholds of the monitor).
+//
+// Must be called while holding the status lock.
+func (s *Server) bfdStatus(ifsName string) (monitor.Status, bool) {
+	session, ok := s.probers[ifsName].(*bfd.Session)
+	if !ok {
+		return monitor.Pending, false
+	}
+
+	switch session.State() {
+	case bfd.StateUp:
+		return monitor.Up, true
+	case bfd.StateInit:
+		return monitor.Pending, true
+	default:
+		return monitor.Down, true
+	}
+}
@@ -0,0 +1,65 @@
+package bridge
+
+import (
+	"context"
+	"sync"
+
+	"github.com/flashbots/vpnham/event"
+)
+
+// calls keeps track of the requests to the external services (the witness,
+// the lease backend) that are made outside of the event loop and of the
+// status locks.  Their outcomes are emitted back into the event loop.
+type calls struct {
+	mx       sync.Mutex
+	pending  map[string]context.CancelFunc
+	inflight sync.WaitGroup
+	stopped  bool
+}
+
+// callOut makes the call in the background (unless the call with the same
+// name is in flight already), and emits the event it produces (if any) into
+// the event loop.
+func (s *Server) callOut(ctx context.Context, name string, call func(ctx context.Context) event.Event) {
+	s.calls.mx.Lock()
+	defer s.calls.mx.Unlock()
+
+	if s.calls.stopped {
+		return
+	}
+	if _, inflight := s.calls.pending[name]; inflight {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.calls.pending[name] = cancel
+	s.calls.inflight.Add(1)
+
+	go func() {
+		defer s.calls.inflight.Done()
+		defer cancel()
+
+		e := call(ctx)
+
+		s.calls.mx.Lock()
+		delete(s.calls.pending, name)
+		s.calls.mx.Unlock()
+
+		if e != nil {
+			s.events <- e // emit event
+		}
+	}()
+}
+
+// stopCalls cancels the calls that are in flight (and waits for them to
+// finish).
+func (s *Server) stopCalls() {
+	s.calls.mx.Lock()
+	s.calls.stopped = true
+	for _, cancel := range s.calls.pending {
+		cancel()
+	}
+	s.calls.mx.Unlock()
+
+	s.calls.inflight.Wait()
+}
@@ -0,0 +1,67 @@
+package bridge
+
+import (
+	"context"
+	"time"
+
+	"github.com/flashbots/vpnham/event"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// registerTunnelFlap penalises the tunnel interface for going down (and
+// suppresses it if the penalty crosses the threshold).
+//
+// Must be called while holding the status lock.
+func (s *Server) registerTunnelFlap(ctx context.Context, ifsName string, ts time.Time, sc trace.SpanContext) {
+	d, dampened := s.dampeners[ifsName]
+	if !dampened {
+		return
+	}
+
+	ifs := s.status.Interfaces[ifsName]
+	suppressed := d.Flap(ts)
+	ifs.Penalty = d.Penalty()
+	if !suppressed {
+		return
+	}
+
+	ifs.Suppressed = true
+	s.emit(&event.TunnelInterfaceSuppressed{ // emit event
+		BridgeInterface: s.cfg.BridgeInterface,
+		BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
+		TunnelInterface: ifsName,
+		Timestamp:       ts,
+		Penalty:         ifs.Penalty,
+		SpanContext:     sc,
+	})
+}
+
+// evaluateTunnelDampening decays the flap penalties of the tunnels and
+// unsuppresses the ones that stopped flapping.
+func (s *Server) evaluateTunnelDampening(ctx context.Context, ts time.Time, _ chan<- error) {
+	if len(s.dampeners) == 0 {
+		return
+	}
+
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+
+	for ifsName, d := range s.dampeners {
+		ifs := s.status.Interfaces[ifsName]
+		unsuppressed := d.Update(ts)
+		ifs.Penalty = d.Penalty()
+		if !unsuppressed {
+			continue
+		}
+
+		ifs.Suppressed = false
+		s.emit(&event.TunnelInterfaceUnsuppressed{ // emit event
+			BridgeInterface: s.cfg.BridgeInterface,
+			BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
+			TunnelInterface: ifsName,
+			Timestamp:       ts,
+			Penalty:         ifs.Penalty,
+			SpanContext:     s.startRootSpan(ctx, "tunnel_interface_unsuppressed", ifsName),
+		})
+	}
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"reflect"
 
+	"github.com/flashbots/vpnham/config"
 	"github.com/flashbots/vpnham/event"
 	"github.com/flashbots/vpnham/logutils"
 	"github.com/flashbots/vpnham/metrics"
 	"github.com/flashbots/vpnham/monitor"
+	"github.com/flashbots/vpnham/tracing"
 	"go.opentelemetry.io/otel/attribute"
 	otelapi "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -19,140 +22,284 @@ func (s *Server) runEventLoop(ctx context.Context, failureSink chan<- error) {
 	go func() {
 		l.Info("VPN HA-monitor bridge event loop is starting...")
 
-		for e := range s.events {
-			var ( // use fresh originals on every iteration
-				ctx = ctx
-				l   = l
-			)
-
-			if te, ok := e.(event.TunnelInterfaceEvent); ok {
-				l = l.With(
-					zap.String("tunnel_interface", te.EvtTunnelInterface()),
-				)
-				ctx = logutils.ContextWithLogger(ctx, l)
+		for {
+			select {
+			case e, ok := <-s.events:
+				if !ok {
+					l.Info("VPN HA-monitor bridge event loop is stopped")
+					return
+				}
+				s.handleEvent(ctx, e, failureSink)
+			case <-s.derived.ready:
 			}
 
-			switch e := e.(type) {
-
-			// bridge
-
-			case *event.BridgeActivated:
-				s.eventBridgeActivated(ctx, e, failureSink)
-			case *event.BridgeDeactivated:
-				s.eventBridgeDeactivated(ctx, e, failureSink)
-			case *event.BridgeReactivated:
-				s.eventBridgeReactivated(ctx, e, failureSink)
-			case *event.BridgeWentDown:
-				s.eventBridgeWentDown(ctx, e, failureSink)
-			case *event.BridgeWentUp:
-				s.eventBridgeWentUp(ctx, e, failureSink)
-
-			// connectivity
-
-			case *event.ConnectivityLost:
-				s.eventConnectivityLost(ctx, e, failureSink)
-			case *event.ConnectivityRestored:
-				s.eventConnectivityRestored(ctx, e, failureSink)
-
-			// partner
-
-			case *event.PartnerActivated:
-				s.eventPartnerActivated(ctx, e, failureSink)
-			case *event.PartnerChangedName:
-				s.eventPartnerChangedName(ctx, e, failureSink)
-			case *event.PartnerDeactivated:
-				s.eventPartnerDeactivated(ctx, e, failureSink)
-			case *event.PartnerPollFailure:
-				s.eventPartnerPollFailure(ctx, e, failureSink)
-			case *event.PartnerPollSuccess:
-				s.eventPartnerPollSuccess(ctx, e, failureSink)
-			case *event.PartnerWentDown:
-				s.eventPartnerWentDown(ctx, e, failureSink)
-			case *event.PartnerWentUp:
-				s.eventPartnerWentUp(ctx, e, failureSink)
-
-			// tunnel
-
-			case *event.TunnelInterfaceActivated:
-				s.eventTunnelInterfaceActivated(ctx, e, failureSink)
-			case *event.TunnelInterfaceDeactivated:
-				s.eventTunnelInterfaceDeactivated(ctx, e, failureSink)
-			case *event.TunnelInterfaceReactivated:
-				s.eventTunnelInterfaceReactivated(ctx, e, failureSink)
-			case *event.TunnelInterfaceWentDown:
-				s.eventTunnelInterfaceWentDown(ctx, e, failureSink)
-			case *event.TunnelInterfaceWentUp:
-				s.eventTunnelInterfaceWentUp(ctx, e, failureSink)
-			case *event.TunnelProbeReturnFailure:
-				s.eventTunnelProbeReturnFailure(ctx, e, failureSink)
-			case *event.TunnelProbeReturnSuccess:
-				s.eventTunnelProbeReturnSuccess(ctx, e, failureSink)
-			case *event.TunnelProbeSendFailure:
-				s.eventTunnelProbeSendFailure(ctx, e, failureSink)
-			case *event.TunnelProbeSendSuccess:
-				s.eventTunnelProbeSendSuccess(ctx, e, failureSink)
-
-			// catch-all
-
-			default:
-				l.Error("Unexpected event",
-					zap.String("kind", e.EvtKind()),
-					zap.String("type", reflect.TypeOf(e).String()),
-				)
-				metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
-					attribute.String(metrics.LabelBridge, s.cfg.Name),
-					attribute.String(metrics.LabelErrorScope, metrics.ScopeInternalLogic),
-				))
+			// the derived events go first, so that the loop catches up with
+			// its own state before it takes in more of the external ones
+			for e := s.dequeue(); e != nil; e = s.dequeue() {
+				s.handleEvent(ctx, e, failureSink)
 			}
+			s.endRootSpans(false)
 		}
-
-		l.Info("VPN HA-monitor bridge event loop is stopped")
 	}()
 }
 
+func (s *Server) handleEvent(ctx context.Context, e event.Event, failureSink chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	if te, ok := e.(event.TunnelInterfaceEvent); ok {
+		l = l.With(
+			zap.String("tunnel_interface", te.EvtTunnelInterface()),
+		)
+		ctx = logutils.ContextWithLogger(ctx, l)
+	}
+
+	var span trace.Span
+	if te, ok := e.(event.TracedEvent); ok && te.EvtSpanContext().IsValid() {
+		ctx, span = tracing.Start(
+			trace.ContextWithSpanContext(ctx, te.EvtSpanContext()),
+			"event/"+e.EvtKind(),
+			trace.WithAttributes(attribute.String(metrics.LabelBridge, s.cfg.Name)),
+		)
+	}
+
+	switch e := e.(type) {
+
+	// bridge
+
+	case *event.BridgeActivated:
+		s.eventBridgeActivated(ctx, e, failureSink)
+	case *event.BridgeDeactivated:
+		s.eventBridgeDeactivated(ctx, e, failureSink)
+	case *event.BridgeLeaseAcquired:
+		s.eventBridgeLeaseAcquired(ctx, e, failureSink)
+	case *event.BridgeLeaseLost:
+		s.eventBridgeLeaseLost(ctx, e, failureSink)
+	case *event.BridgePreemptionDue:
+		s.eventBridgePreemptionDue(ctx, e, failureSink)
+	case *event.BridgeReactivated:
+		s.eventBridgeReactivated(ctx, e, failureSink)
+	case *event.BridgeWentDown:
+		s.eventBridgeWentDown(ctx, e, failureSink)
+	case *event.BridgeWentUp:
+		s.eventBridgeWentUp(ctx, e, failureSink)
+
+	// connectivity
+
+	case *event.ConnectivityLost:
+		s.eventConnectivityLost(ctx, e, failureSink)
+	case *event.ConnectivityRestored:
+		s.eventConnectivityRestored(ctx, e, failureSink)
+
+	// partner
+
+	case *event.PartnerActivated:
+		s.eventPartnerActivated(ctx, e, failureSink)
+	case *event.PartnerChangedName:
+		s.eventPartnerChangedName(ctx, e, failureSink)
+	case *event.PartnerDeactivated:
+		s.eventPartnerDeactivated(ctx, e, failureSink)
+	case *event.PartnerPollFailure:
+		s.eventPartnerPollFailure(ctx, e, failureSink)
+	case *event.PartnerPollSuccess:
+		s.eventPartnerPollSuccess(ctx, e, failureSink)
+	case *event.PartnerWentDown:
+		s.eventPartnerWentDown(ctx, e, failureSink)
+	case *event.PartnerWentUp:
+		s.eventPartnerWentUp(ctx, e, failureSink)
+
+	// tunnel
+
+	case *event.TunnelInterfaceActivated:
+		s.eventTunnelInterfaceActivated(ctx, e, failureSink)
+	case *event.TunnelInterfaceBFDStateChanged:
+		s.eventTunnelInterfaceBFDStateChanged(ctx, e, failureSink)
+	case *event.TunnelInterfaceDeactivated:
+		s.eventTunnelInterfaceDeactivated(ctx, e, failureSink)
+	case *event.TunnelInterfaceDegraded:
+		s.eventTunnelInterfaceDegraded(ctx, e, failureSink)
+	case *event.TunnelInterfaceLinkDown:
+		s.eventTunnelInterfaceLinkDown(ctx, e, failureSink)
+	case *event.TunnelInterfaceLinkUp:
+		s.eventTunnelInterfaceLinkUp(ctx, e, failureSink)
+	case *event.TunnelInterfacePMTUChecked:
+		s.eventTunnelInterfacePMTUChecked(ctx, e, failureSink)
+	case *event.TunnelInterfacePreemptionDue:
+		s.eventTunnelInterfacePreemptionDue(ctx, e, failureSink)
+	case *event.TunnelInterfaceReactivated:
+		s.eventTunnelInterfaceReactivated(ctx, e, failureSink)
+	case *event.TunnelInterfaceSuppressed:
+		s.eventTunnelInterfaceSuppressed(ctx, e, failureSink)
+	case *event.TunnelInterfaceUnsuppressed:
+		s.eventTunnelInterfaceUnsuppressed(ctx, e, failureSink)
+	case *event.TunnelInterfaceWentDown:
+		s.eventTunnelInterfaceWentDown(ctx, e, failureSink)
+	case *event.TunnelInterfaceWentUp:
+		s.eventTunnelInterfaceWentUp(ctx, e, failureSink)
+	case *event.TunnelProbeReturnFailure:
+		s.eventTunnelProbeReturnFailure(ctx, e, failureSink)
+	case *event.TunnelProbeReturnSuccess:
+		s.eventTunnelProbeReturnSuccess(ctx, e, failureSink)
+	case *event.TunnelProbeSendFailure:
+		s.eventTunnelProbeSendFailure(ctx, e, failureSink)
+	case *event.TunnelProbeSendSuccess:
+		s.eventTunnelProbeSendSuccess(ctx, e, failureSink)
+
+	// witness
+
+	case *event.WitnessVerdict:
+		s.eventWitnessVerdict(ctx, e, failureSink)
+
+	// catch-all
+
+	default:
+		l.Error("Unexpected event",
+			zap.String("kind", e.EvtKind()),
+			zap.String("type", reflect.TypeOf(e).String()),
+		)
+		metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelErrorScope, metrics.ScopeInternalLogic),
+		))
+	}
+
+	if span != nil {
+		span.End()
+		s.rootSpanHandled(e.(event.TracedEvent).EvtSpanContext())
+	}
+
+	if err := s.journal.RecordEvent(e); err != nil {
+		l.Error("Failed to record event into the history",
+			zap.Error(err),
+			zap.String("kind", e.EvtKind()),
+		)
+		metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelErrorScope, metrics.ScopeHistory),
+		))
+	}
+}
+
+// eventsBufferSize accounts for all producers that can emit into the events
+// channel at the same time:  the outcomes of the probes (to the peer of the
+// tunnel and to the additional targets), the link watchers, the bfd session,
+// and the path mtu checks for every tunnel interface;  the polls of every
+// partner path;  the timed events together with the tick;  and the calls to
+// the witness and to the lease backend.
+func eventsBufferSize(cfg *config.Bridge) int {
+	size := 0
+	for _, ifs := range cfg.TunnelInterfaces {
+		// send and return of every probe, 3 watchers, bfd, and pmtu
+		size += 2*ifs.ProbeTargetsCount() + 3 + 2
+	}
+
+	// the polls of the partner paths, the preemption timers, and the tick
+	size += cfg.PartnerPathsCount() + cfg.TunnelInterfacesCount() + 2
+
+	// the witness report and verdict, and the lease
+	size += 3
+
+	return 2 * size // some slack for the fast probing
+}
+
 func (s *Server) stopEventLoop(_ context.Context) {
+	s.stopTimers()
+	s.stopCalls()
 	close(s.events)
+	s.endRootSpans(true)
 }
 
 // detectTunnelUpDownEvents derives tunnel up/down events from tunnel probe events
-func (s *Server) detectTunnelUpDownEvents(e event.TunnelInterfaceEvent, updateMonitor func(*monitor.Monitor)) {
+func (s *Server) detectTunnelUpDownEvents(ctx context.Context, e event.TunnelInterfaceEvent, target string, updateMonitor func(*monitor.Monitor)) {
 	s.mxStatus.Lock()
 	defer s.mxStatus.Unlock()
 
 	ifs := s.status.Interfaces[e.EvtTunnelInterface()]
-	mon := s.monitors[e.EvtTunnelInterface()]
+	mon := s.targetMonitor(e.EvtTunnelInterface(), target)
+	if mon == nil {
+		return
+	}
 
 	updateMonitor(mon)
 
-	switch mon.Status() {
+	switch s.tunnelMonitorStatus(e.EvtTunnelInterface()) {
 	case monitor.Down:
 		if ifs.Up {
 			ifs.Up = false
 			ifs.UpSince = e.EvtTimestamp()
-			s.events <- &event.TunnelInterfaceWentDown{ // emit event
+			sc := s.startRootSpan(ctx, "tunnel_interface_went_down", e.EvtTunnelInterface())
+			s.emit(&event.TunnelInterfaceWentDown{ // emit event
 				BridgeInterface: s.cfg.BridgeInterface,
 				BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
 				TunnelInterface: e.EvtTunnelInterface(),
 				Timestamp:       e.EvtTimestamp(),
-			}
+				Trigger:         e,
+				Window:          mon.Window(),
+				SpanContext:     sc,
+			})
+			s.registerTunnelFlap(ctx, e.EvtTunnelInterface(), e.EvtTimestamp(), sc)
 		}
 
 	case monitor.Up:
 		if !ifs.Up {
 			ifs.Up = true
 			ifs.UpSince = e.EvtTimestamp()
-			s.events <- &event.TunnelInterfaceWentUp{ // emit event
+			s.emit(&event.TunnelInterfaceWentUp{ // emit event
 				BridgeInterface: s.cfg.BridgeInterface,
 				BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
 				TunnelInterface: e.EvtTunnelInterface(),
 				Timestamp:       e.EvtTimestamp(),
-			}
+				Trigger:         e,
+				Window:          mon.Window(),
+			})
 		}
 	}
 }
 
+// targetMonitor returns the monitor of the tunnel's probe target (the empty
+// target name stands for the default one).
+//
+// Must be called while holding the status lock.
+func (s *Server) targetMonitor(ifsName, target string) *monitor.Monitor {
+	if target == "" {
+		return s.monitors[ifsName]
+	}
+	for _, t := range s.targets[ifsName] {
+		if t.name == target {
+			return t.monitor
+		}
+	}
+	return nil
+}
+
+// tunnelMonitorStatus aggregates the statuses of the monitors of all probe
+// targets of the tunnel interface.
+//
+// Must be called while holding the status lock.
+func (s *Server) tunnelMonitorStatus(ifsName string) monitor.Status {
+	if s.mtuDown(ifsName) || s.status.Interfaces[ifsName].LinkDown {
+		return monitor.Down
+	}
+
+	own := s.monitors[ifsName].Status()
+	if status, ok := s.bfdStatus(ifsName); ok {
+		own = status
+	}
+
+	targets := s.targets[ifsName]
+	if len(targets) == 0 {
+		return own
+	}
+
+	statuses := make([]monitor.Status, 0, len(targets)+1)
+	statuses = append(statuses, own)
+	for _, t := range targets {
+		statuses = append(statuses, t.monitor.Status())
+	}
+	return monitor.Aggregate(statuses, s.cfg.TunnelInterfaces[ifsName].ProbeTargetsQuorum)
+}
+
 // deriveBridgeEvents derives bridge events from tunnel-interface events
-func (s *Server) deriveBridgeEvents(e event.TunnelInterfaceEvent) {
+func (s *Server) deriveBridgeEvents(ctx context.Context, e event.TunnelInterfaceEvent) {
 	s.mxStatus.Lock()
 	defer s.mxStatus.Unlock()
 
@@ -166,24 +313,26 @@ func (s *Server) deriveBridgeEvents(e event.TunnelInterfaceEvent) {
 	}
 
 	if up {
-		s.events <- &event.BridgeWentUp{ // emit event
+		s.emit(&event.BridgeWentUp{ // emit event
 			BridgeInterface: s.cfg.BridgeInterface,
 			BridgePerCIDR:   s.cfg.PeerCIDR,
 			Timestamp:       e.EvtTimestamp(),
-		}
+		})
 	} else {
-		s.events <- &event.BridgeWentDown{ // emit event
+		s.emit(&event.BridgeWentDown{ // emit event
 			BridgeInterface: s.cfg.BridgeInterface,
 			BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
 			Timestamp:       e.EvtTimestamp(),
-		}
+			SpanContext:     trace.SpanContextFromContext(ctx),
+		})
 	}
 
 	s.status.Up = up
+	s.status.UpSince = e.EvtTimestamp()
 }
 
 // derivePartnerUpDownEvents derives partner up/down events from partner poll events
-func (s *Server) derivePartnerUpDownEvents(e event.PartnerPollEvent, updateMonitor func(*monitor.Monitor)) {
+func (s *Server) derivePartnerUpDownEvents(ctx context.Context, e event.PartnerPollEvent, updateMonitor func(*monitor.Monitor)) {
 	s.mxPartnerStatus.Lock()
 	defer s.mxPartnerStatus.Unlock()
 
@@ -191,30 +340,41 @@ func (s *Server) derivePartnerUpDownEvents(e event.PartnerPollEvent, updateMonit
 	if s.partnerStatus == nil {
 		if firstStatus := e.PartnerStatus(); firstStatus != nil {
 			firstContact = true
-			s.partnerStatus = firstStatus
+			partnerStatus := *firstStatus // don't share the pointer with the event
+			s.partnerStatus = &partnerStatus
 		} else {
 			// old status is nil, new status is also nil => nothing to do here
 			return
 		}
 	}
 
-	updateMonitor(s.partnerMonitor)
+	path := s.partnerPathByName(e.PartnerPath())
+	if path == nil {
+		return
+	}
+
+	updateMonitor(path.monitor)
 
-	switch s.partnerMonitor.Status() {
+	switch s.partnerMonitorStatus() {
 	case monitor.Down:
 		if s.partnerStatus != nil && s.partnerStatus.Up {
 			s.partnerStatus.Up = false
-			s.events <- &event.PartnerWentDown{ // emit event
-				Timestamp: e.EvtTimestamp(),
-			}
+			s.emit(&event.PartnerWentDown{ // emit event
+				Timestamp:   e.EvtTimestamp(),
+				Trigger:     e,
+				Window:      path.monitor.Window(),
+				SpanContext: s.startRootSpan(ctx, "partner_went_down", ""),
+			})
 		}
 
 	case monitor.Up:
 		if firstContact || (s.partnerStatus == nil || !s.partnerStatus.Up) {
 			s.partnerStatus.Up = true
-			s.events <- &event.PartnerWentUp{ // emit events
+			s.emit(&event.PartnerWentUp{ // emit events
 				Timestamp: e.EvtTimestamp(),
-			}
+				Trigger:   e,
+				Window:    path.monitor.Window(),
+			})
 		}
 	}
 
@@ -224,23 +384,24 @@ func (s *Server) derivePartnerUpDownEvents(e event.PartnerPollEvent, updateMonit
 		s.partnerStatus.Interfaces = newPartnerStatus.Interfaces
 
 		if s.partnerStatus.Name != newPartnerStatus.Name {
-			s.events <- &event.PartnerChangedName{ // emit event
+			s.emit(&event.PartnerChangedName{ // emit event
 				OldName:   s.partnerStatus.Name,
 				NewName:   newPartnerStatus.Name,
 				Timestamp: e.EvtTimestamp(),
-			}
+			})
 			s.partnerStatus.Name = newPartnerStatus.Name
 		}
 
 		if s.partnerStatus.Active != newPartnerStatus.Active {
 			if newPartnerStatus.Active {
-				s.events <- &event.PartnerActivated{ // emit event
+				s.emit(&event.PartnerActivated{ // emit event
 					Timestamp: e.EvtTimestamp(),
-				}
+				})
 			} else {
-				s.events <- &event.PartnerDeactivated{ // emit event
-					Timestamp: e.EvtTimestamp(),
-				}
+				s.emit(&event.PartnerDeactivated{ // emit event
+					Timestamp:   e.EvtTimestamp(),
+					SpanContext: trace.SpanContextFromContext(ctx),
+				})
 			}
 			s.partnerStatus.Active = newPartnerStatus.Active
 			s.partnerStatus.ActiveSince = newPartnerStatus.ActiveSince
@@ -7,6 +7,7 @@ import (
 	"github.com/flashbots/vpnham/event"
 	"github.com/flashbots/vpnham/logutils"
 	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -20,20 +21,24 @@ func (s *Server) eventBridgeWentDown(ctx context.Context, e *event.BridgeWentDow
 	s.mxPartnerStatus.Lock()
 	defer s.mxPartnerStatus.Unlock()
 
+	s.status.Preempting = false
+
 	if s.status.Active {
 		s.status.Active = false
 		s.status.ActiveSince = e.Timestamp
-		s.events <- &event.BridgeDeactivated{ // emit event
+		s.emit(&event.BridgeDeactivated{ // emit event
 			BridgeInterface: s.cfg.BridgeInterface,
 			BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
 			Timestamp:       e.Timestamp,
-		}
+			SpanContext:     trace.SpanContextFromContext(ctx),
+		})
 	}
 
 	if s.partnerStatus == nil || !s.partnerStatus.Up {
-		s.events <- &event.ConnectivityLost{ // emit event
-			Timestamp: e.Timestamp,
-		}
+		s.emit(&event.ConnectivityLost{ // emit event
+			Timestamp:   e.Timestamp,
+			SpanContext: trace.SpanContextFromContext(ctx),
+		})
 	}
 }
 
@@ -50,39 +55,114 @@ func (s *Server) eventBridgeWentUp(ctx context.Context, e *event.BridgeWentUp, _
 	if !s.status.Active {
 		switch s.cfg.Role {
 		case types.RoleActive:
-			s.status.Active = true
-			s.status.ActiveSince = e.Timestamp
-			s.events <- &event.BridgeActivated{ // emit event
-				BridgeInterface: s.cfg.BridgeInterface,
-				BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
-				Timestamp:       e.Timestamp,
+			if s.partnerStatus != nil && s.partnerStatus.Up && s.partnerStatus.Active {
+				// the partner took over while we were down => only reclaim
+				// the active status once we are up for long enough (if at all)
+				if !s.cfg.DisablePreemption {
+					s.scheduleBridgePreemption(ctx)
+				}
+				break
 			}
+			s.activateSelf(ctx, e.Timestamp)
 
 		case types.RoleStandby:
 			if s.partnerStatus == nil || !s.partnerStatus.Up {
-				s.status.Active = true
-				s.status.ActiveSince = e.Timestamp
-				s.events <- &event.BridgeActivated{ // emit event
-					BridgeInterface: s.cfg.BridgeInterface,
-					BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
-					Timestamp:       e.Timestamp,
-				}
+				// the partner might be up but cut off from us => ask the
+				// witness (if configured) before going active
+				s.promoteSelf(ctx, e.Timestamp)
 			}
 		}
 	}
 
 	if s.partnerStatus == nil || !s.partnerStatus.Up {
-		s.events <- &event.ConnectivityRestored{ // emit event
+		s.emit(&event.ConnectivityRestored{ // emit event
 			Timestamp: e.Timestamp,
-		}
+		})
 	}
 }
 
+func (s *Server) eventBridgePreemptionDue(ctx context.Context, e *event.BridgePreemptionDue, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+
+	// things might have changed since the preemption was scheduled
+
+	if s.status.Active || !s.status.Up || e.Timestamp.Sub(s.status.UpSince) < s.cfg.PreemptDelay {
+		return
+	}
+
+	l.Info("Bridge reclaiming the active status...")
+
+	if s.lease != nil {
+		// the partner releases the lease once it sees that we are preempting,
+		// and the activation is retried on the ticks until we get it
+		s.status.Preempting = true
+	}
+
+	s.activateSelf(ctx, e.Timestamp)
+}
+
+func (s *Server) eventBridgeLeaseAcquired(ctx context.Context, e *event.BridgeLeaseAcquired, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+	s.mxPartnerStatus.Lock()
+	defer s.mxPartnerStatus.Unlock()
+
+	// things might have changed while the lease was being acquired
+
+	if s.status.Active || !s.lease.Held() {
+		return
+	}
+
+	if s.cfg.Role == types.RoleStandby &&
+		s.partnerStatus != nil && s.partnerStatus.Up && s.partnerStatus.Active {
+		return // the lease is released on the next tick
+	}
+
+	l.Info("Bridge acquired the lease, activating...")
+
+	s.activateSelf(ctx, e.Timestamp)
+}
+
+func (s *Server) eventBridgeLeaseLost(ctx context.Context, e *event.BridgeLeaseLost, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+
+	// the lease might have been re-acquired since it was lost
+
+	if !s.status.Active || s.lease.Held() {
+		return
+	}
+
+	l.Warn("Bridge lost the lease, deactivating...")
+
+	s.status.Active = false
+	s.status.ActiveSince = e.Timestamp
+	s.emit(&event.BridgeDeactivated{ // emit event
+		BridgeInterface: s.cfg.BridgeInterface,
+		BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
+		Timestamp:       e.Timestamp,
+		SpanContext:     trace.SpanContextFromContext(ctx),
+	})
+}
+
 func (s *Server) eventBridgeDeactivated(ctx context.Context, _ *event.BridgeDeactivated, _ chan<- error) {
 	l := logutils.LoggerFromContext(ctx)
 
 	l.Info("Bridge deactivated")
 
+	s.refreshProbeBridge()
+
+	if s.lease != nil && s.lease.Held() {
+		s.releaseLease(ctx) // don't keep the partner waiting until the next tick
+	}
+
 	if r := s.cfg.Reconcile.BridgeActivate.Reapply; r.Enabled() {
 		reapply := s.reapply.bridgeActivate
 		reapply.Count = 0
@@ -95,6 +175,8 @@ func (s *Server) eventBridgeActivated(ctx context.Context, e *event.BridgeActiva
 
 	l.Info("Bridge activating...")
 
+	s.refreshProbeBridge()
+
 	s.reconciler.BridgeActivate(ctx, e, failureSink)
 
 	if r := s.cfg.Reconcile.BridgeActivate.Reapply; r.Enabled() {
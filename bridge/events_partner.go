@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/flashbots/vpnham/event"
 	"github.com/flashbots/vpnham/logutils"
 	"github.com/flashbots/vpnham/monitor"
 	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -17,9 +19,9 @@ var (
 	errParterChangedName = errors.New("partner's bridge name has changed")
 )
 
-func (s *Server) eventPartnerPollFailure(_ context.Context, e *event.PartnerPollFailure, _ chan<- error) {
-	s.derivePartnerUpDownEvents(e, func(m *monitor.Monitor) {
-		m.RegisterStatus(e.Sequence, monitor.Down)
+func (s *Server) eventPartnerPollFailure(ctx context.Context, e *event.PartnerPollFailure, _ chan<- error) {
+	s.derivePartnerUpDownEvents(ctx, e, func(m *monitor.Monitor) {
+		m.RegisterStatus(e.Sequence, monitor.Down, e.Timestamp)
 	})
 }
 
@@ -29,7 +31,7 @@ func (s *Server) eventPartnerChangedName(_ context.Context, e *event.PartnerChan
 	)
 }
 
-func (s *Server) eventPartnerPollSuccess(_ context.Context, e *event.PartnerPollSuccess, failureSink chan<- error) {
+func (s *Server) eventPartnerPollSuccess(ctx context.Context, e *event.PartnerPollSuccess, failureSink chan<- error) {
 	if e.Status.Name != s.cfg.Name {
 		failureSink <- fmt.Errorf("%w: expected %s, got %s",
 			errPartnerBridgeNameIsDifferent, s.cfg.Name, e.Status.Role,
@@ -44,11 +46,11 @@ func (s *Server) eventPartnerPollSuccess(_ context.Context, e *event.PartnerPoll
 		return
 	}
 
-	s.derivePartnerUpDownEvents(e, func(m *monitor.Monitor) {
+	s.derivePartnerUpDownEvents(ctx, e, func(m *monitor.Monitor) {
 		if e.Status.Up {
-			m.RegisterStatus(e.Sequence, monitor.Up)
+			m.RegisterStatus(e.Sequence, monitor.Up, e.Timestamp)
 		} else {
-			m.RegisterStatus(e.Sequence, monitor.Down)
+			m.RegisterStatus(e.Sequence, monitor.Down, e.Timestamp)
 		}
 	})
 }
@@ -66,20 +68,13 @@ func (s *Server) eventPartnerWentDown(ctx context.Context, e *event.PartnerWentD
 	if s.partnerStatus.Active {
 		s.partnerStatus.Active = false
 		s.partnerStatus.ActiveSince = e.EvtTimestamp()
-		s.events <- &event.PartnerDeactivated{ // emit event
-			Timestamp: e.EvtTimestamp(),
-		}
+		s.emit(&event.PartnerDeactivated{ // emit event
+			Timestamp:   e.EvtTimestamp(),
+			SpanContext: trace.SpanContextFromContext(ctx),
+		})
 	}
 
-	if !s.status.Active {
-		s.status.Active = true
-		s.status.ActiveSince = e.Timestamp
-		s.events <- &event.BridgeActivated{ // emit event
-			BridgeInterface: s.cfg.BridgeInterface,
-			BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
-			Timestamp:       e.Timestamp,
-		}
-	}
+	s.promoteSelf(ctx, e.Timestamp)
 }
 
 func (s *Server) eventPartnerWentUp(ctx context.Context, e *event.PartnerWentUp, _ chan<- error) {
@@ -89,14 +84,10 @@ func (s *Server) eventPartnerWentUp(ctx context.Context, e *event.PartnerWentUp,
 
 	s.mxStatus.Lock()
 	defer s.mxStatus.Unlock()
+	s.mxPartnerStatus.Lock()
+	defer s.mxPartnerStatus.Unlock()
 
-	if s.status.Active && s.cfg.Role != types.RoleActive {
-		s.status.Active = false
-		s.status.ActiveSince = e.Timestamp
-		s.events <- &event.BridgeDeactivated{
-			Timestamp: e.Timestamp,
-		}
-	}
+	s.yieldToPartner(ctx, e.Timestamp)
 }
 
 func (s *Server) eventPartnerDeactivated(ctx context.Context, _ *event.PartnerDeactivated, _ chan<- error) {
@@ -105,12 +96,43 @@ func (s *Server) eventPartnerDeactivated(ctx context.Context, _ *event.PartnerDe
 	l.Info("Partner deactivated")
 }
 
-func (s *Server) eventPartnerActivated(ctx context.Context, _ *event.PartnerActivated, _ chan<- error) {
+func (s *Server) eventPartnerActivated(ctx context.Context, e *event.PartnerActivated, _ chan<- error) {
 	l := logutils.LoggerFromContext(ctx)
 
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+	s.mxPartnerStatus.Lock()
+	defer s.mxPartnerStatus.Unlock()
+
 	logFields := []zapcore.Field{}
 	if s.partnerStatus != nil {
 		logFields = append(logFields, zap.String("partner_name", s.partnerStatus.Name))
 	}
 	l.Info("Partner activated", logFields...)
+
+	s.yieldToPartner(ctx, e.Timestamp)
+}
+
+// yieldToPartner deactivates the standby bridge once its partner is up and
+// active (not earlier, so that there is no gap while the partner is holding
+// off its preemption).
+//
+// Must be called while holding both status locks.
+func (s *Server) yieldToPartner(ctx context.Context, ts time.Time) {
+	if !s.status.Active || s.cfg.Role == types.RoleActive {
+		return
+	}
+
+	if s.partnerStatus == nil || !s.partnerStatus.Up || !s.partnerStatus.Active {
+		return
+	}
+
+	s.status.Active = false
+	s.status.ActiveSince = ts
+	s.emit(&event.BridgeDeactivated{ // emit event
+		BridgeInterface: s.cfg.BridgeInterface,
+		BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
+		Timestamp:       ts,
+		SpanContext:     trace.SpanContextFromContext(ctx),
+	})
 }
@@ -3,9 +3,11 @@ package bridge
 import (
 	"context"
 
+	"github.com/flashbots/vpnham/config"
 	"github.com/flashbots/vpnham/event"
 	"github.com/flashbots/vpnham/logutils"
-	"github.com/flashbots/vpnham/types"
+	"github.com/flashbots/vpnham/monitor"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -14,7 +16,7 @@ func (s *Server) eventTunnelInterfaceWentDown(ctx context.Context, e *event.Tunn
 
 	l.Info("Tunnel interface going down...")
 
-	s.deriveBridgeEvents(e)
+	s.deriveBridgeEvents(ctx, e)
 
 	//
 	// if this tunnel was `active`` when ti went `down`, try to find another
@@ -33,29 +35,44 @@ func (s *Server) eventTunnelInterfaceWentDown(ctx context.Context, e *event.Tunn
 
 	ifs.Active = false
 	ifs.ActiveSince = e.Timestamp
-	s.events <- &event.TunnelInterfaceDeactivated{ // emit event
+	s.emit(&event.TunnelInterfaceDeactivated{ // emit event
 		BridgeInterface: s.cfg.BridgeInterface,
 		BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
 		TunnelInterface: e.EvtTunnelInterface(),
 		Timestamp:       e.Timestamp,
-	}
+		SpanContext:     trace.SpanContextFromContext(ctx),
+	})
 
 	// then activate another tunnel
 
-	for promotedIfsName, promotedIfs := range s.status.Interfaces {
-		if promotedIfsName == e.EvtTunnelInterface() || !promotedIfs.Up {
-			continue
-		}
-		promotedIfs.Active = true
-		promotedIfs.ActiveSince = e.Timestamp
-		s.events <- &event.TunnelInterfaceActivated{ // emit event
-			BridgeInterface: s.cfg.BridgeInterface,
-			BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
-			TunnelInterface: promotedIfsName,
-			Timestamp:       e.Timestamp,
-		}
+	promotedIfsName := s.selector.Failover(s.tunnels(), e.EvtTunnelInterface())
+	if promotedIfsName == "" {
 		return
 	}
+	s.activateTunnelInterface(ctx, promotedIfsName, e.Timestamp)
+}
+
+func (s *Server) eventTunnelInterfaceDegraded(ctx context.Context, e *event.TunnelInterfaceDegraded, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	l.Info("Tunnel interface degraded; switching over to a better one...",
+		zap.Float64("score", e.Score),
+		zap.String("promoted_tunnel_interface", e.Promoted),
+		zap.Float64("promoted_score", e.PromotedScore),
+	)
+
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+
+	// things might have changed since the degradation was detected
+
+	ifs := s.status.Interfaces[e.EvtTunnelInterface()]
+	promotedIfs := s.status.Interfaces[e.Promoted]
+	if !ifs.Active || promotedIfs.Active || !promotedIfs.Up {
+		return
+	}
+
+	s.activateTunnelInterface(ctx, e.Promoted, e.Timestamp)
 }
 
 func (s *Server) eventTunnelInterfaceWentUp(ctx context.Context, e *event.TunnelInterfaceWentUp, _ chan<- error) {
@@ -63,76 +80,194 @@ func (s *Server) eventTunnelInterfaceWentUp(ctx context.Context, e *event.Tunnel
 
 	l.Info("Tunnel interface going up...")
 
-	s.deriveBridgeEvents(e)
+	s.deriveBridgeEvents(ctx, e)
 
 	//
 	// when going up:
 	//
-	//   - if this tunnel is configured `active`, overtake the active status
+	//   - if there's no other active tunnel around, elect self to be `active`
 	//
-	//   - otherwise, only elect self to be `active` if there's no other active
-	//     tunnel around
+	//   - otherwise, overtake the active status if this tunnel has higher
+	//     priority (unless there's preemption delay configured, or the
+	//     quality score is not known yet;  in which case the preemption is
+	//     re-evaluated later on with a timed event)
 	//
 
 	s.mxStatus.Lock()
 	defer s.mxStatus.Unlock()
 
-	ifs := s.status.Interfaces[e.EvtTunnelInterface()]
-	cfg := s.cfg.TunnelInterfaces[e.EvtTunnelInterface()]
-	switch cfg.Role {
-	case types.RoleActive:
-		if !ifs.Active {
-			// first deactivate other tunnel (if needed)
-
-			for demotedIfsName, demotedIfs := range s.status.Interfaces {
-				if demotedIfsName == e.EvtTunnelInterface() || !demotedIfs.Active {
-					continue
-				}
-				demotedIfs.Active = false
-				demotedIfs.ActiveSince = e.Timestamp
-				s.events <- &event.TunnelInterfaceDeactivated{ // emit event
-					BridgeInterface: s.cfg.BridgeInterface,
-					BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
-					TunnelInterface: demotedIfsName,
-					Timestamp:       e.Timestamp,
-				}
-			}
-
-			// then activate self
-
-			ifs.Active = true
-			ifs.ActiveSince = e.Timestamp
-			s.events <- &event.TunnelInterfaceActivated{ // emit event
-				BridgeInterface: s.cfg.BridgeInterface,
-				BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
-				TunnelInterface: e.EvtTunnelInterface(),
-				Timestamp:       e.Timestamp,
-			}
+	s.preemptTunnelInterface(ctx, e.EvtTunnelInterface(), e.Timestamp)
+}
 
-		}
+func (s *Server) eventTunnelInterfacePreemptionDue(ctx context.Context, e *event.TunnelInterfacePreemptionDue, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+
+	// things might have changed since the preemption was scheduled (e.g. the
+	// tunnel could flap, in which case another preemption was scheduled)
 
-	case types.RoleStandby:
-		anotherActiveIfsExists := false
-		for anotherIfsName, anotherIfs := range s.status.Interfaces {
-			if anotherIfsName == e.EvtTunnelInterface() {
-				continue
-			}
-			if anotherIfs.Active {
-				anotherActiveIfsExists = true
-				break
-			}
+	tunnels := s.tunnels()
+	if s.selector.Preemption(tunnels, e.Timestamp) != e.EvtTunnelInterface() {
+		if s.preemptionHeldBack(tunnels, e.EvtTunnelInterface(), e.Timestamp) {
+			// the tunnel still outranks the active one, but does not score
+			// good enough (yet)
+			s.scheduleTunnelPreemption(ctx, e.EvtTunnelInterface())
 		}
-		if !anotherActiveIfsExists {
-			ifs.Active = true
-			ifs.ActiveSince = e.Timestamp
-			s.events <- &event.TunnelInterfaceActivated{ // emit event
-				BridgeInterface: s.cfg.BridgeInterface,
-				BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
-				TunnelInterface: e.EvtTunnelInterface(),
-				Timestamp:       e.Timestamp,
-			}
+		return
+	}
+
+	l.Info("Tunnel interface preempting the active one...")
+
+	s.activateTunnelInterface(ctx, e.EvtTunnelInterface(), e.Timestamp)
+}
+
+func (s *Server) eventTunnelInterfaceSuppressed(ctx context.Context, e *event.TunnelInterfaceSuppressed, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	l.Warn("Tunnel interface is flapping; suppressing...",
+		zap.Float64("penalty", e.Penalty),
+	)
+}
+
+func (s *Server) eventTunnelInterfaceUnsuppressed(ctx context.Context, e *event.TunnelInterfaceUnsuppressed, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	l.Info("Tunnel interface stopped flapping; unsuppressing...",
+		zap.Float64("penalty", e.Penalty),
+	)
+
+	//
+	// the tunnel is eligible for activation again, therefore treat it as if
+	// it just went up (if it is up)
+	//
+
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+
+	if !s.status.Interfaces[e.EvtTunnelInterface()].Up {
+		return
+	}
+
+	s.preemptTunnelInterface(ctx, e.EvtTunnelInterface(), e.Timestamp)
+}
+
+func (s *Server) eventTunnelInterfacePMTUChecked(ctx context.Context, e *event.TunnelInterfacePMTUChecked, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+
+	ifsName := e.EvtTunnelInterface()
+	ifs := s.status.Interfaces[ifsName]
+	cfg := s.cfg.TunnelInterfaces[ifsName].PMTU
+
+	if ifs.PMTU != e.PMTU {
+		l.Info("Tunnel interface path mtu changed",
+			zap.Int("pmtu_was", ifs.PMTU),
+			zap.Int("pmtu", e.PMTU),
+		)
+	}
+	ifs.PMTU = e.PMTU
+	s.pmtu[ifsName].checked = true
+
+	degraded := cfg.MinMTU > 0 && e.PMTU < cfg.MinMTU
+	if degraded == ifs.MTUDegraded {
+		return
+	}
+	ifs.MTUDegraded = degraded
+
+	if !degraded {
+		l.Info("Tunnel interface path mtu is back above the minimum",
+			zap.Int("pmtu", e.PMTU),
+			zap.Int("min_mtu", cfg.MinMTU),
+		)
+		if cfg.Action == config.PMTUActionDegrade && ifs.Up {
+			// eligible for activation again
+			s.preemptTunnelInterface(ctx, ifsName, e.Timestamp)
 		}
+		// with `down` action the tunnel goes up on the next probe
+		return
 	}
+
+	l.Warn("Tunnel interface path mtu is below the minimum",
+		zap.Int("pmtu", e.PMTU),
+		zap.Int("min_mtu", cfg.MinMTU),
+		zap.String("action", cfg.Action),
+	)
+	if cfg.Action != config.PMTUActionDegrade || !ifs.Active {
+		// with `down` action the tunnel goes down on the next probe
+		return
+	}
+
+	promoted := s.selector.Failover(s.tunnels(), ifsName)
+	if promoted == "" || s.mtuDegraded(promoted) || s.status.Interfaces[promoted].Suppressed {
+		return
+	}
+
+	s.emit(&event.TunnelInterfaceDegraded{ // emit event
+		BridgeInterface: s.cfg.BridgeInterface,
+		BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
+		TunnelInterface: ifsName,
+		Timestamp:       e.Timestamp,
+
+		Score:         ifs.Score,
+		Promoted:      promoted,
+		PromotedScore: s.status.Interfaces[promoted].Score,
+
+		SpanContext: trace.SpanContextFromContext(ctx),
+	})
+}
+
+func (s *Server) eventTunnelInterfaceLinkDown(ctx context.Context, e *event.TunnelInterfaceLinkDown, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	l.Warn("Tunnel interface link went down",
+		zap.String("source", e.Source),
+		zap.String("reason", e.Reason),
+	)
+
+	func() {
+		s.mxStatus.Lock()
+		defer s.mxStatus.Unlock()
+
+		s.registerLinkState(e.EvtTunnelInterface(), e.Source, false)
+	}()
+
+	// no need to wait for the probes to fail
+	s.detectTunnelUpDownEvents(ctx, e, "", func(*monitor.Monitor) {})
+}
+
+func (s *Server) eventTunnelInterfaceLinkUp(ctx context.Context, e *event.TunnelInterfaceLinkUp, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	l.Info("Tunnel interface link went up",
+		zap.String("source", e.Source),
+	)
+
+	func() {
+		s.mxStatus.Lock()
+		defer s.mxStatus.Unlock()
+
+		s.registerLinkState(e.EvtTunnelInterface(), e.Source, true)
+	}()
+
+	// the tunnel goes up as soon as the probes say so
+	s.detectTunnelUpDownEvents(ctx, e, "", func(*monitor.Monitor) {})
+}
+
+func (s *Server) eventTunnelInterfaceBFDStateChanged(ctx context.Context, e *event.TunnelInterfaceBFDStateChanged, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	l.Debug("Tunnel interface bfd session changed state",
+		zap.String("from", e.From),
+		zap.String("to", e.To),
+		zap.String("diag", e.Diag),
+	)
+
+	// no need to wait for the probe loop to sample the session
+	s.detectTunnelUpDownEvents(ctx, e, "", func(*monitor.Monitor) {})
 }
 
 func (s *Server) eventTunnelInterfaceDeactivated(ctx context.Context, e *event.TunnelInterfaceDeactivated, failureSink chan<- error) {
@@ -140,6 +275,8 @@ func (s *Server) eventTunnelInterfaceDeactivated(ctx context.Context, e *event.T
 
 	l.Info("Tunnel interface deactivating...")
 
+	s.refreshProbeBridge()
+
 	s.reconciler.InterfaceDeactivate(ctx, e, failureSink)
 }
 
@@ -148,6 +285,8 @@ func (s *Server) eventTunnelInterfaceActivated(ctx context.Context, e *event.Tun
 
 	l.Info("Tunnel interface activating...")
 
+	s.refreshProbeBridge()
+
 	s.reconciler.InterfaceActivate(ctx, e, failureSink)
 
 	if r := s.cfg.Reconcile.InterfaceActivate.Reapply; r.Enabled() {
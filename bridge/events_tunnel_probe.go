@@ -3,67 +3,151 @@ package bridge
 import (
 	"context"
 
+	"github.com/flashbots/vpnham/config"
 	"github.com/flashbots/vpnham/event"
 	"github.com/flashbots/vpnham/metrics"
 	"github.com/flashbots/vpnham/monitor"
+	"github.com/flashbots/vpnham/types"
 	"go.opentelemetry.io/otel/attribute"
 	otelapi "go.opentelemetry.io/otel/metric"
 )
 
 func (s *Server) eventTunnelProbeSendSuccess(ctx context.Context, e *event.TunnelProbeSendSuccess, _ chan<- error) {
-	s.detectTunnelUpDownEvents(e, func(m *monitor.Monitor) {
-		m.RegisterStatus(e.ProbeSequence, monitor.Pending)
+	s.detectTunnelUpDownEvents(ctx, e, e.ProbeTarget, func(m *monitor.Monitor) {
+		m.RegisterStatus(e.ProbeSequence, monitor.Pending, e.Timestamp)
 	})
 
 	metrics.ProbesSent.Add(ctx, 1, otelapi.WithAttributes(
 		attribute.String(metrics.LabelBridge, s.cfg.Name),
 		attribute.String(metrics.LabelTunnel, e.TunnelInterface),
+		attribute.String(metrics.LabelProbeTarget, probeTargetLabel(e.ProbeTarget)),
 	))
 }
 
 func (s *Server) eventTunnelProbeSendFailure(ctx context.Context, e *event.TunnelProbeSendFailure, _ chan<- error) {
-	s.detectTunnelUpDownEvents(e, func(m *monitor.Monitor) {
-		m.RegisterStatus(e.ProbeSequence, monitor.Down)
+	s.detectTunnelUpDownEvents(ctx, e, e.ProbeTarget, func(m *monitor.Monitor) {
+		m.RegisterStatus(e.ProbeSequence, monitor.Down, e.Timestamp)
 	})
 
+	s.adaptProbeInterval(ctx, e.TunnelInterface, true)
+
 	metrics.ProbesFailed.Add(ctx, 1, otelapi.WithAttributes(
 		attribute.String(metrics.LabelBridge, s.cfg.Name),
 		attribute.String(metrics.LabelTunnel, e.TunnelInterface),
+		attribute.String(metrics.LabelProbeTarget, probeTargetLabel(e.ProbeTarget)),
 	))
 }
 
 func (s *Server) eventTunnelProbeReturnSuccess(ctx context.Context, e *event.TunnelProbeReturnSuccess, _ chan<- error) {
-	s.detectTunnelUpDownEvents(e, func(m *monitor.Monitor) {
-		m.RegisterStatus(e.ProbeSequence, monitor.Up)
+	s.detectTunnelUpDownEvents(ctx, e, e.ProbeTarget, func(m *monitor.Monitor) {
+		m.RegisterStatus(e.ProbeSequence, monitor.Up, e.Timestamp)
 	})
 
+	s.adaptProbeInterval(ctx, e.TunnelInterface, false)
+
 	metrics.ProbesReturned.Add(ctx, 1, otelapi.WithAttributes(
 		attribute.String(metrics.LabelBridge, s.cfg.Name),
 		attribute.String(metrics.LabelTunnel, e.TunnelInterface),
+		attribute.String(metrics.LabelProbeTarget, probeTargetLabel(e.ProbeTarget)),
 	))
 
-	metrics.ProbesLatencyForward.Record(ctx, float64(e.LatencyForward.Microseconds()), otelapi.WithAttributes(
-		attribute.String(metrics.LabelBridge, s.cfg.Name),
-		attribute.String(metrics.LabelTunnel, e.TunnelInterface),
-		attribute.String(metrics.LabelProbeDst, e.Location),
-		attribute.String(metrics.LabelProbeSrc, s.cfg.ProbeLocation.String()),
-	))
+	if e.ProbeType == config.TunnelInterfaceProbeTypeUDP {
+		// only vpnham probes carry the timestamps of the peer
+		metrics.ProbesLatencyForward.Record(ctx, float64(e.LatencyForward.Microseconds()), otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelTunnel, e.TunnelInterface),
+			attribute.String(metrics.LabelProbeDst, e.Location),
+			attribute.String(metrics.LabelProbeSrc, s.cfg.ProbeLocation.String()),
+		))
 
-	metrics.ProbesLatencyReturn.Record(ctx, float64(e.LatencyReturn.Microseconds()), otelapi.WithAttributes(
-		attribute.String(metrics.LabelBridge, s.cfg.Name),
-		attribute.String(metrics.LabelTunnel, e.TunnelInterface),
-		attribute.String(metrics.LabelProbeDst, s.cfg.ProbeLocation.String()),
-		attribute.String(metrics.LabelProbeSrc, e.Location),
-	))
+		metrics.ProbesLatencyReturn.Record(ctx, float64(e.LatencyReturn.Microseconds()), otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelTunnel, e.TunnelInterface),
+			attribute.String(metrics.LabelProbeDst, s.cfg.ProbeLocation.String()),
+			attribute.String(metrics.LabelProbeSrc, e.Location),
+		))
+
+		metrics.ProbesClockOffset.Record(ctx, float64(e.ClockOffset.Microseconds()), otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelTunnel, e.TunnelInterface),
+			attribute.String(metrics.LabelProbeDst, e.Location),
+			attribute.String(metrics.LabelProbeSrc, s.cfg.ProbeLocation.String()),
+		))
+	}
+
+	if e.RTT > 0 {
+		metrics.ProbesRTT.Record(ctx, float64(e.RTT.Microseconds()), otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelTunnel, e.TunnelInterface),
+			attribute.String(metrics.LabelProbeTarget, probeTargetLabel(e.ProbeTarget)),
+			attribute.String(metrics.LabelProbeDst, e.Location),
+			attribute.String(metrics.LabelProbeSrc, s.cfg.ProbeLocation.String()),
+		))
+	}
+
+	if e.OutOfOrder {
+		metrics.ProbesOutOfOrder.Add(ctx, 1, otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelTunnel, e.TunnelInterface),
+		))
+	}
+
+	s.recordProbeStats(ctx, e.TunnelInterface, e.ProbeTarget)
 }
 
 func (s *Server) eventTunnelProbeReturnFailure(ctx context.Context, e *event.TunnelProbeReturnFailure, _ chan<- error) {
-	s.detectTunnelUpDownEvents(e, func(m *monitor.Monitor) {
-		m.RegisterStatus(e.ProbeSequence, monitor.Down)
+	s.detectTunnelUpDownEvents(ctx, e, e.ProbeTarget, func(m *monitor.Monitor) {
+		m.RegisterStatus(e.ProbeSequence, monitor.Down, e.Timestamp)
 	})
 
+	s.adaptProbeInterval(ctx, e.TunnelInterface, true)
+
 	metrics.ProbesFailed.Add(ctx, 1, otelapi.WithAttributes(
 		attribute.String(metrics.LabelBridge, s.cfg.Name),
 		attribute.String(metrics.LabelTunnel, e.TunnelInterface),
+		attribute.String(metrics.LabelProbeTarget, probeTargetLabel(e.ProbeTarget)),
 	))
+
+	s.recordProbeStats(ctx, e.TunnelInterface, e.ProbeTarget)
+}
+
+func (s *Server) recordProbeStats(ctx context.Context, ifsName, target string) {
+	peer := s.targetPeer(ifsName, target)
+	if peer == nil {
+		return
+	}
+	stats := peer.Stats()
+
+	metrics.ProbesLossRatio.Record(ctx, stats.LossRatio, otelapi.WithAttributes(
+		attribute.String(metrics.LabelBridge, s.cfg.Name),
+		attribute.String(metrics.LabelTunnel, ifsName),
+		attribute.String(metrics.LabelProbeTarget, probeTargetLabel(target)),
+	))
+
+	metrics.ProbesJitter.Record(ctx, float64(stats.Jitter.Microseconds()), otelapi.WithAttributes(
+		attribute.String(metrics.LabelBridge, s.cfg.Name),
+		attribute.String(metrics.LabelTunnel, ifsName),
+		attribute.String(metrics.LabelProbeTarget, probeTargetLabel(target)),
+	))
+}
+
+// targetPeer returns the peer of the tunnel's probe target (the empty target
+// name stands for the default one).
+func (s *Server) targetPeer(ifsName, target string) *types.Peer {
+	if target == "" {
+		return s.peers[ifsName]
+	}
+	for _, t := range s.targets[ifsName] {
+		if t.name == target {
+			return t.peer
+		}
+	}
+	return nil
+}
+
+func probeTargetLabel(target string) string {
+	if target == "" {
+		return config.DefaultProbeTargetName
+	}
+	return target
 }
@@ -0,0 +1,37 @@
+package bridge
+
+import (
+	"context"
+
+	"github.com/flashbots/vpnham/event"
+	"github.com/flashbots/vpnham/logutils"
+	"go.uber.org/zap"
+)
+
+func (s *Server) eventWitnessVerdict(ctx context.Context, e *event.WitnessVerdict, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	if !e.Allowed {
+		l.Warn("Witness still sees the partner; holding off the self-promotion",
+			zap.String("lease_holder", e.Holder),
+			zap.Time("lease_expires", e.Expires),
+		)
+		return
+	}
+
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+	s.mxPartnerStatus.Lock()
+	defer s.mxPartnerStatus.Unlock()
+
+	// things might have changed while the witness was being asked
+
+	if s.status.Active {
+		return
+	}
+	if s.partnerStatus != nil && s.partnerStatus.Up && s.partnerStatus.Active {
+		return
+	}
+
+	s.activateSelf(ctx, e.Timestamp)
+}
@@ -7,10 +7,13 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"slices"
 	"time"
 
+	"github.com/flashbots/vpnham/journal"
 	"github.com/flashbots/vpnham/logutils"
 	"github.com/flashbots/vpnham/metrics"
+	"github.com/flashbots/vpnham/topology"
 	"github.com/flashbots/vpnham/transponder"
 	"github.com/flashbots/vpnham/types"
 	"go.opentelemetry.io/otel/attribute"
@@ -19,6 +22,7 @@ import (
 )
 
 var (
+	errBridgeProbeTypeIsNotSupported    = errors.New("probe type is not supported")
 	errBridgeReturnProbeDstUUIDMismatch = errors.New("return probe has destination uuid mismatch")
 	errBridgeReturnProbeSrcUUIDMismatch = errors.New("return probe has source uuid mismatch")
 )
@@ -55,9 +59,131 @@ func (s *Server) handleStatus(
 	s.mxStatus.Lock()
 	defer s.mxStatus.Unlock()
 
+	s.status.Topology = s.topology()
+
+	var res any = s.status
+	if s.signer != nil {
+		doc, err := s.signedStatus(r.URL.Query().Get("nonce"))
+		if err != nil {
+			l.Error("Failed to sign the status",
+				zap.Error(err),
+			)
+			metrics.Errors.Add(r.Context(), 1, otelapi.WithAttributes(
+				attribute.String(metrics.LabelBridge, s.cfg.Name),
+				attribute.String(metrics.LabelErrorScope, metrics.ScopeStatusListener),
+			))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		res = doc
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Header().Set("content-type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		l.Error("Failed to encode and send response body",
+			zap.Error(err),
+		)
+		metrics.Errors.Add(r.Context(), 1, otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelErrorScope, metrics.ScopeStatusListener),
+		))
+		return
+	}
+}
+
+func (s *Server) handleHistory(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	l := logutils.LoggerFromRequest(r)
+
+	defer r.Body.Close()
+	if _, err := io.ReadAll(r.Body); err != nil {
+		l.Error("Failed to read request body",
+			zap.Error(err),
+		)
+		metrics.Errors.Add(r.Context(), 1, otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelErrorScope, metrics.ScopeStatusListener),
+		))
+	}
+
+	if r.Method != http.MethodGet {
+		l.Error("Unexpected history request method",
+			zap.String("method", r.Method),
+		)
+		metrics.Errors.Add(r.Context(), 1, otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelErrorScope, metrics.ScopeStatusListener),
+		))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	entries := s.journal.Entries()
+
+	if since := r.URL.Query().Get("since"); since != "" {
+		ts, err := time.Parse(time.RFC3339Nano, since)
+		if err != nil {
+			l.Warn("Invalid history request parameter",
+				zap.Error(err),
+				zap.String("since", since),
+			)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		entries = slices.DeleteFunc(entries, func(entry *journal.Entry) bool {
+			return entry.Timestamp.Before(ts)
+		})
+	}
+
+	w.Header().Set("content-type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		l.Error("Failed to encode and send response body",
+			zap.Error(err),
+		)
+		metrics.Errors.Add(r.Context(), 1, otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelErrorScope, metrics.ScopeStatusListener),
+		))
+		return
+	}
+}
+
+func (s *Server) handleJobs(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	l := logutils.LoggerFromRequest(r)
+
+	defer r.Body.Close()
+	if _, err := io.ReadAll(r.Body); err != nil {
+		l.Error("Failed to read request body",
+			zap.Error(err),
+		)
+		metrics.Errors.Add(r.Context(), 1, otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelErrorScope, metrics.ScopeStatusListener),
+		))
+	}
+
+	if r.Method != http.MethodGet {
+		l.Error("Unexpected jobs request method",
+			zap.String("method", r.Method),
+		)
+		metrics.Errors.Add(r.Context(), 1, otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelErrorScope, metrics.ScopeStatusListener),
+		))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	w.Header().Set("content-type", "application/json")
-	if err := json.NewEncoder(w).Encode(s.status); err != nil {
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(s.reconciler.PendingJobs()); err != nil {
 		l.Error("Failed to encode and send response body",
 			zap.Error(err),
 		)
@@ -69,6 +195,61 @@ func (s *Server) handleStatus(
 	}
 }
 
+func (s *Server) handleTopology(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	l := logutils.LoggerFromRequest(r)
+
+	defer r.Body.Close()
+	if _, err := io.ReadAll(r.Body); err != nil {
+		l.Error("Failed to read request body",
+			zap.Error(err),
+		)
+		metrics.Errors.Add(r.Context(), 1, otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelErrorScope, metrics.ScopeStatusListener),
+		))
+	}
+
+	if r.Method != http.MethodGet {
+		l.Error("Unexpected topology request method",
+			zap.String("method", r.Method),
+		)
+		metrics.Errors.Add(r.Context(), 1, otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelErrorScope, metrics.ScopeStatusListener),
+		))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mxStatus.Lock()
+	t := s.topology()
+	s.mxStatus.Unlock()
+
+	body, contentType, err := topology.Render(t, r.URL.Query().Get("format"))
+	if err != nil {
+		l.Warn("Invalid topology request parameter",
+			zap.Error(err),
+		)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("content-type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		l.Error("Failed to send response body",
+			zap.Error(err),
+		)
+		metrics.Errors.Add(r.Context(), 1, otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelErrorScope, metrics.ScopeStatusListener),
+		))
+	}
+}
+
 func (s *Server) handleProbe(
 	ctx context.Context,
 	tp *transponder.Transponder,
@@ -89,6 +270,10 @@ func (s *Server) handleProbe(
 	case probe.DstTimestamp.IsZero():
 		s.respondToProbe(ctx, tp, from, probe)
 
+	// handle our own returned path mtu probes
+	case probe.SrcUUID == s.uuid && s.isPMTUProbe(tp.InterfaceName(), probe):
+		s.processReturnedPMTUProbe(ctx, tp.InterfaceName(), probe)
+
 	// handle our own returned probes
 	case probe.SrcUUID == s.uuid:
 		s.processReturnedProbe(ctx, tp, from, probe)
@@ -110,8 +295,11 @@ func (s *Server) handleTick(
 		zap.Time("tick", ts),
 	)
 
-	s.sendProbes(ctx, failureSink)
-	s.detectMissedProbes(ctx, failureSink)
+	s.evaluateTunnelQuality(ctx, ts, failureSink)
+	s.evaluateTunnelDampening(ctx, ts, failureSink)
+	s.evaluateFarEnds(ctx, ts, failureSink)
 	s.pollPartnerBridge(ctx, failureSink)
+	s.consultWitness(ctx, ts, failureSink)
+	s.maintainLease(ctx, ts, failureSink)
 	s.reapplyUpdates(ctx, failureSink)
 }
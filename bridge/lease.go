@@ -0,0 +1,178 @@
+package bridge
+
+import (
+	"context"
+	"time"
+
+	"github.com/flashbots/vpnham/event"
+	"github.com/flashbots/vpnham/logutils"
+	"github.com/flashbots/vpnham/metrics"
+	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/attribute"
+	otelapi "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	callLease        = "lease"
+	callLeaseRelease = "lease_release"
+)
+
+// maintainLease renews the lease while we are active, releases it once we
+// are not (or once the partner is due to preempt us), and retries the
+// activation that the lease did not allow earlier.
+func (s *Server) maintainLease(ctx context.Context, ts time.Time, _ chan<- error) {
+	if s.lease == nil {
+		return
+	}
+
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+	s.mxPartnerStatus.Lock()
+	defer s.mxPartnerStatus.Unlock()
+
+	held := s.lease.Held()
+	partnerPreempting := s.partnerStatus != nil && s.partnerStatus.Up && s.partnerStatus.Preempting
+
+	switch {
+	case s.status.Active && held && partnerPreempting:
+		// let the partner take the lease (we stay active until it does)
+		s.releaseLease(ctx)
+
+	case s.status.Active && held:
+		s.renewLease(ctx)
+
+	case s.status.Active && !partnerPreempting:
+		// the partner did not take over after all => take the lease back
+		s.acquireLease(ctx)
+
+	case !s.status.Active && held:
+		s.releaseLease(ctx)
+
+	case !s.status.Active && s.status.Up && s.status.Preempting:
+		s.activateSelf(ctx, ts)
+
+	case !s.status.Active && s.status.Up:
+		// nobody is active (as far as we can tell) => try to take over
+		if s.partnerStatus == nil || !s.partnerStatus.Up ||
+			(!s.partnerStatus.Active && s.cfg.Role == types.RoleActive) {
+			s.promoteSelf(ctx, ts)
+		}
+	}
+}
+
+// acquireLease tries to acquire the lease in the background, and emits the
+// lease acquired event if we got it.
+func (s *Server) acquireLease(ctx context.Context) {
+	s.callOut(ctx, callLease, func(ctx context.Context) event.Event {
+		l := logutils.LoggerFromContext(ctx)
+
+		acquired, err := s.lease.Acquire(ctx, time.Now())
+		if err != nil {
+			l.Warn("Failed to acquire the lease; holding off the activation",
+				zap.Error(err),
+			)
+			metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
+				attribute.String(metrics.LabelBridge, s.cfg.Name),
+				attribute.String(metrics.LabelErrorScope, metrics.ScopeLease),
+			))
+			return nil
+		}
+
+		if !acquired {
+			l.Info("Lease is held by somebody else; holding off the activation",
+				zap.String("lease_key", s.cfg.Lease.Key),
+			)
+			return nil
+		}
+
+		return &event.BridgeLeaseAcquired{
+			BridgeInterface: s.cfg.BridgeInterface,
+			BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
+			Timestamp:       time.Now(),
+			SpanContext:     trace.SpanContextFromContext(ctx),
+		}
+	})
+}
+
+// renewLease extends the lease that we are holding in the background, and
+// emits the lease lost event if it's gone (or if we could not renew it for so
+// long that it might expire before the next attempt).
+func (s *Server) renewLease(ctx context.Context) {
+	s.callOut(ctx, callLease, func(ctx context.Context) event.Event {
+		l := logutils.LoggerFromContext(ctx)
+
+		lost, err := s.lease.Renew(ctx, time.Now())
+		if err != nil {
+			l.Warn("Failed to renew the lease",
+				zap.Error(err),
+			)
+			metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
+				attribute.String(metrics.LabelBridge, s.cfg.Name),
+				attribute.String(metrics.LabelErrorScope, metrics.ScopeLease),
+			))
+		}
+
+		if !lost {
+			return nil
+		}
+
+		l.Warn("Lost the lease",
+			zap.String("lease_key", s.cfg.Lease.Key),
+		)
+
+		s.releaseLeaseNow(ctx) // best effort, so that the backend starts afresh
+
+		return &event.BridgeLeaseLost{
+			BridgeInterface: s.cfg.BridgeInterface,
+			BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
+			Timestamp:       time.Now(),
+			SpanContext:     trace.SpanContextFromContext(ctx),
+		}
+	})
+}
+
+// releaseLease gives the lease up in the background (once the acquisition or
+// the renewal that is in flight, if any, is done).
+func (s *Server) releaseLease(ctx context.Context) {
+	s.callOut(ctx, callLeaseRelease, func(ctx context.Context) event.Event {
+		s.releaseLeaseNow(ctx)
+		return nil
+	})
+}
+
+// releaseLeaseNow gives the lease up (outside of the event loop).
+func (s *Server) releaseLeaseNow(ctx context.Context) {
+	l := logutils.LoggerFromContext(ctx)
+
+	if err := s.lease.Release(ctx); err != nil {
+		l.Warn("Failed to release the lease",
+			zap.Error(err),
+		)
+		metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelErrorScope, metrics.ScopeLease),
+		))
+	}
+}
+
+// closeLease releases the lease (so that the partner can take over without
+// waiting for it to expire) and closes the backend.
+//
+// Must be called after the event loop is stopped.
+func (s *Server) closeLease(ctx context.Context) {
+	if s.lease == nil {
+		return
+	}
+
+	l := logutils.LoggerFromContext(ctx)
+
+	s.releaseLeaseNow(ctx)
+
+	if err := s.lease.Close(); err != nil {
+		l.Error("VPN HA-monitor bridge lease backend close failed",
+			zap.Error(err),
+		)
+	}
+}
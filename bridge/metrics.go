@@ -5,6 +5,7 @@ import (
 
 	"github.com/flashbots/vpnham/logutils"
 	"github.com/flashbots/vpnham/metrics"
+	"github.com/flashbots/vpnham/monitor"
 	"go.opentelemetry.io/otel/attribute"
 	otelapi "go.opentelemetry.io/otel/metric"
 )
@@ -40,6 +41,19 @@ func (s *Server) ObserveMetrics(ctx context.Context, observer otelapi.Observer)
 		))
 	}
 
+	for _, path := range s.partnerPaths {
+		{ // partner_path_up
+			var val int64 = 0
+			if path.monitor.Status() == monitor.Up {
+				val = 1
+			}
+			observer.ObserveInt64(metrics.PartnerPathUp, val, otelapi.WithAttributes(
+				attribute.String(metrics.LabelBridge, s.cfg.Name),
+				attribute.String(metrics.LabelPartnerPath, path.name),
+			))
+		}
+	}
+
 	for ifsName, ifs := range s.status.Interfaces {
 		{ // tunnel_interface_active
 			var val int64 = 0
@@ -62,6 +76,35 @@ func (s *Server) ObserveMetrics(ctx context.Context, observer otelapi.Observer)
 				attribute.String(metrics.LabelTunnel, ifsName),
 			))
 		}
+
+		if _, dampened := s.dampeners[ifsName]; dampened {
+			{ // tunnel_interface_penalty
+				observer.ObserveFloat64(metrics.TunnelInterfacePenalty, ifs.Penalty, otelapi.WithAttributes(
+					attribute.String(metrics.LabelBridge, s.cfg.Name),
+					attribute.String(metrics.LabelTunnel, ifsName),
+				))
+			}
+
+			{ // tunnel_interface_suppressed
+				var val int64 = 0
+				if ifs.Suppressed {
+					val = 1
+				}
+				observer.ObserveInt64(metrics.TunnelInterfaceSuppressed, val, otelapi.WithAttributes(
+					attribute.String(metrics.LabelBridge, s.cfg.Name),
+					attribute.String(metrics.LabelTunnel, ifsName),
+				))
+			}
+		}
+
+		if c, enabled := s.pmtu[ifsName]; enabled && c.checked {
+			{ // tunnel_interface_pmtu
+				observer.ObserveInt64(metrics.TunnelInterfacePMTU, int64(ifs.PMTU), otelapi.WithAttributes(
+					attribute.String(metrics.LabelBridge, s.cfg.Name),
+					attribute.String(metrics.LabelTunnel, ifsName),
+				))
+			}
+		}
 	}
 
 	return nil
@@ -0,0 +1,96 @@
+package bridge
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/monitor"
+	"github.com/flashbots/vpnham/tlsutils"
+	"github.com/flashbots/vpnham/types"
+	"github.com/flashbots/vpnham/utils"
+)
+
+// partnerPath is one of the (independent) paths over which the partner's
+// status is polled.
+type partnerPath struct {
+	name    string
+	http    *http.Client
+	partner *types.Partner
+	monitor *monitor.Monitor
+
+	statusSequence uint64 // last accepted sequence of the partner's signed status
+}
+
+func newPartnerPath(
+	cfg *config.Bridge,
+	name, partnerURL, pollingInterface string,
+	m *monitor.Monitor,
+	tls *tlsutils.Reloader,
+) (*partnerPath, error) {
+	partner, err := types.NewPartner(partnerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   cfg.PartnerStatusTimeout,
+		KeepAlive: 2 * cfg.PartnerStatusTimeout,
+	}
+	if pollingInterface != "" {
+		ipv4s, ipv6s, err := utils.GetInterfaceIPs(pollingInterface)
+		if err != nil {
+			return nil, err
+		}
+		if len(ipv4s) > 0 {
+			dialer.LocalAddr = &net.TCPAddr{IP: net.ParseIP(ipv4s[0])}
+		} else if len(ipv6s) > 0 {
+			dialer.LocalAddr = &net.TCPAddr{IP: net.ParseIP(ipv6s[0])}
+		}
+	}
+	transport := &http.Transport{
+		DialContext:     dialer.DialContext,
+		IdleConnTimeout: 4 * cfg.PartnerStatusTimeout,
+		MaxIdleConns:    2,
+	}
+	if tls != nil {
+		serverName := cfg.StatusTLS.ServerName
+		if serverName == "" {
+			serverName = partner.URL().Hostname()
+		}
+		transport.TLSClientConfig = tls.ClientConfig(serverName)
+	}
+
+	return &partnerPath{
+		name: name,
+		http: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.PartnerStatusTimeout,
+		},
+		partner: partner,
+		monitor: m,
+	}, nil
+}
+
+// partnerPathByName returns the partner path with the name (or nil).
+func (s *Server) partnerPathByName(name string) *partnerPath {
+	for _, p := range s.partnerPaths {
+		if p.name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// partnerMonitorStatus derives the partner's status from the monitors of all
+// the paths:  the partner is up while it's reachable over any of them, and is
+// down only when all of them agree.
+//
+// Must be called while holding the partner status lock.
+func (s *Server) partnerMonitorStatus() monitor.Status {
+	statuses := make([]monitor.Status, 0, len(s.partnerPaths))
+	for _, p := range s.partnerPaths {
+		statuses = append(statuses, p.monitor.Status())
+	}
+	return monitor.Aggregate(statuses, 1)
+}
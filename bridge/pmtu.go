@@ -0,0 +1,182 @@
+package bridge
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/event"
+	"github.com/flashbots/vpnham/logutils"
+	"github.com/flashbots/vpnham/pmtu"
+	"github.com/flashbots/vpnham/types"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// pmtuChecker keeps track of the path mtu checks of the tunnel interface.  The
+// padded probes of the check go through the tunnel's transponder (with their
+// own destination uuid and sequence space, so that they don't mess with the
+// regular probes).
+type pmtuChecker struct {
+	uuid     uuid.UUID
+	sequence atomic.Uint64
+	replies  chan uint64
+
+	checked bool // at least one check is complete (guarded by the status lock)
+}
+
+func newPMTUChecker() (*pmtuChecker, error) {
+	_uuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pmtuChecker{
+		uuid:    _uuid,
+		replies: make(chan uint64, 1),
+	}, nil
+}
+
+// runPMTUCheckLoop periodically checks the path mtu of the tunnel interface.
+func (s *Server) runPMTUCheckLoop(ctx context.Context, ifsName string, _ chan<- error) {
+	ticker := time.NewTicker(s.cfg.TunnelInterfaces[ifsName].PMTU.Interval)
+
+	s.probing.loops.Add(1)
+	go func() {
+		defer s.probing.loops.Done()
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.probing.done:
+				return
+			case <-ticker.C:
+				s.checkPMTU(ctx, ifsName)
+			}
+		}
+	}()
+}
+
+func (s *Server) checkPMTU(ctx context.Context, ifsName string) {
+	ifs := s.cfg.TunnelInterfaces[ifsName]
+	peer := s.peers[ifsName]
+
+	// the mtu is about the whole ip packet, and the probe is udp payload
+	overhead := 28
+	if peer.ProbeAddr().IP.To4() == nil {
+		overhead = 48
+	}
+
+	// the check starts the trace of the degradation (if it detects one)
+	sc := s.startRootSpan(ctx, "tunnel_interface_pmtu_check", ifsName)
+
+	search := pmtu.NewSearch(overhead+types.ProbeSize(), ifs.PMTU.MaxMTU)
+	for size, ok := search.Next(); ok; size, ok = search.Next() {
+		passed, stopped := s.sendPMTUProbe(ctx, ifsName, size-overhead)
+		if stopped {
+			return
+		}
+		search.Register(size, passed)
+	}
+
+	select {
+	case <-s.probing.done:
+		// the event loop might be gone already
+	case s.events <- &event.TunnelInterfacePMTUChecked{ // emit event
+		BridgeInterface: s.cfg.BridgeInterface,
+		BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
+		TunnelInterface: ifsName,
+		Timestamp:       time.Now(),
+		PMTU:            search.Result(),
+		SpanContext:     sc,
+	}:
+	}
+}
+
+// sendPMTUProbe sends the probe of the given size through the tunnel and waits
+// for it to come back.
+func (s *Server) sendPMTUProbe(ctx context.Context, ifsName string, size int) (passed, stopped bool) {
+	l := logutils.LoggerFromContext(ctx)
+
+	c := s.pmtu[ifsName]
+	peer := s.peers[ifsName]
+
+	probe := &types.Probe{
+		Sequence:     c.sequence.Add(1),
+		SrcUUID:      s.uuid,
+		SrcLocation:  s.cfg.ProbeLocation,
+		SrcTimestamp: time.Now(),
+		DstUUID:      c.uuid,
+		Padding:      size - types.ProbeSize(),
+	}
+
+	// drop the late reply to the previous probe (if any), otherwise it would
+	// take the only slot of the channel away from the reply to this one
+	select {
+	case <-c.replies:
+	default:
+	}
+
+	sent := make(chan error, 1)
+	s.transponders[ifsName].SendProbe(probe, peer.ProbeAddr(), func(err error) {
+		sent <- err
+	})
+	if err := <-sent; err != nil {
+		l.Debug("Failed to send path mtu probe",
+			zap.Error(err),
+			zap.String("tunnel_interface", ifsName),
+			zap.Int("size", size),
+		)
+		return false, false
+	}
+
+	timeout := time.NewTimer(s.cfg.TunnelInterfaces[ifsName].PMTU.Timeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case <-s.probing.done:
+			return false, true
+		case <-timeout.C:
+			return false, false
+		case sequence := <-c.replies:
+			if sequence == probe.Sequence {
+				return true, false
+			}
+		}
+	}
+}
+
+func (s *Server) processReturnedPMTUProbe(_ context.Context, ifsName string, probe *types.Probe) {
+	select {
+	case s.pmtu[ifsName].replies <- probe.Sequence:
+	default:
+		// late reply (the check moved on already)
+	}
+}
+
+// isPMTUProbe returns true if the probe is the path mtu probe of the tunnel
+// interface.
+func (s *Server) isPMTUProbe(ifsName string, probe *types.Probe) bool {
+	c, enabled := s.pmtu[ifsName]
+	return enabled && probe.DstUUID == c.uuid
+}
+
+// mtuDegraded returns true if the tunnel interface's path mtu is below the
+// configured minimum, and the tunnel should be avoided because of that.
+//
+// Must be called while holding the status lock.
+func (s *Server) mtuDegraded(ifsName string) bool {
+	return s.status.Interfaces[ifsName].MTUDegraded &&
+		s.cfg.TunnelInterfaces[ifsName].PMTU.Action == config.PMTUActionDegrade
+}
+
+// mtuDown returns true if the tunnel interface's path mtu is below the
+// configured minimum, and the tunnel should be deemed down because of that.
+//
+// Must be called while holding the status lock.
+func (s *Server) mtuDown(ifsName string) bool {
+	return s.status.Interfaces[ifsName].MTUDegraded &&
+		s.cfg.TunnelInterfaces[ifsName].PMTU.Action == config.PMTUActionDown
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/flashbots/vpnham/event"
@@ -22,26 +23,75 @@ var (
 	errPartnerRoleIsIdentical       = errors.New("partner has the role identical to ours")
 )
 
+// pollPartnerBridge polls the partner over all of the paths (independently
+// of each other).
 func (s *Server) pollPartnerBridge(ctx context.Context, _ chan<- error) {
-	l := logutils.LoggerFromContext(ctx)
+	var wg sync.WaitGroup
+	for _, path := range s.partnerPaths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.pollPartnerPath(ctx, path)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *Server) pollPartnerPath(ctx context.Context, path *partnerPath) {
+	l := logutils.LoggerFromContext(ctx).With(
+		zap.String("partner_path", path.name),
+	)
+
+	sequence := path.partner.NextSequence()
+
+	attrs := otelapi.WithAttributes(
+		attribute.String(metrics.LabelBridge, s.cfg.Name),
+		attribute.String(metrics.LabelPartnerPath, path.name),
+	)
+	metrics.PartnerPollsSent.Add(ctx, 1, attrs)
+
+	fail := func() {
+		metrics.PartnerPollsFailed.Add(ctx, 1, attrs)
+		s.events <- &event.PartnerPollFailure{ // emit event
+			Path:      path.name,
+			Sequence:  sequence,
+			Timestamp: time.Now(),
+		}
+	}
 
-	sequence := s.partner.NextSequence()
+	url := path.partner.URL().JoinPath(pathStatus)
+
+	nonce := ""
+	if s.signer != nil {
+		var err error
+		if nonce, err = newNonce(); err != nil {
+			l.Error("Failed to generate partner status nonce",
+				zap.Error(err),
+			)
+			metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
+				attribute.String(metrics.LabelBridge, s.cfg.Name),
+				attribute.String(metrics.LabelErrorScope, metrics.ScopePartnerPolling),
+			))
+			fail()
+			return
+		}
+		query := url.Query()
+		query.Set("nonce", nonce)
+		url.RawQuery = query.Encode()
+	}
 
 	req := &http.Request{
 		Method: http.MethodGet,
-		URL:    s.partner.URL().JoinPath(pathStatus),
+		URL:    url,
 		Header: map[string][]string{"accept": {"application/json"}},
 	}
 
-	res, err := s.http.Do(req)
+	res, err := path.http.Do(req)
 	if err != nil {
 		l.Warn("Failed to query partner bridge status",
 			zap.Error(err),
 		)
-		s.events <- &event.PartnerPollFailure{ // emit event
-			Sequence:  sequence,
-			Timestamp: time.Now(),
-		}
+		fail()
 		return
 	}
 	defer res.Body.Close()
@@ -55,15 +105,17 @@ func (s *Server) pollPartnerBridge(ctx context.Context, _ chan<- error) {
 			attribute.String(metrics.LabelBridge, s.cfg.Name),
 			attribute.String(metrics.LabelErrorScope, metrics.ScopePartnerPolling),
 		))
-		s.events <- &event.PartnerPollFailure{ // emit event
-			Sequence:  sequence,
-			Timestamp: time.Now(),
-		}
+		fail()
 		return
 	}
 
 	partnerStatus := &types.BridgeStatus{}
-	if err := json.Unmarshal(b, partnerStatus); err != nil {
+	if s.signer != nil {
+		partnerStatus, err = s.verifyPartnerStatus(path, b, nonce)
+	} else {
+		err = json.Unmarshal(b, partnerStatus)
+	}
+	if err != nil {
 		l.Error("Failed to parse partner bridge status",
 			zap.Error(err),
 		)
@@ -71,15 +123,13 @@ func (s *Server) pollPartnerBridge(ctx context.Context, _ chan<- error) {
 			attribute.String(metrics.LabelBridge, s.cfg.Name),
 			attribute.String(metrics.LabelErrorScope, metrics.ScopePartnerPolling),
 		))
-		s.events <- &event.PartnerPollFailure{ // emit event
-			Sequence:  sequence,
-			Timestamp: time.Now(),
-		}
+		fail()
 		return
 	}
 
 	s.events <- &event.PartnerPollSuccess{ // emit event
 		Status:    partnerStatus,
+		Path:      path.name,
 		Sequence:  sequence,
 		Timestamp: time.Now(),
 	}
@@ -0,0 +1,41 @@
+package bridge
+
+import (
+	"context"
+	"time"
+
+	"github.com/flashbots/vpnham/event"
+)
+
+// scheduleBridgePreemption emits the bridge preemption event once the preempt
+// delay elapses.
+func (s *Server) scheduleBridgePreemption(_ context.Context) {
+	s.scheduleEvent(s.cfg.PreemptDelay, func(ts time.Time) event.Event {
+		return &event.BridgePreemptionDue{
+			BridgeInterface: s.cfg.BridgeInterface,
+			BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
+			Timestamp:       ts,
+			SpanContext:     s.startRootSpan(context.Background(), "bridge_preemption_due", ""),
+		}
+	})
+}
+
+// scheduleTunnelPreemption emits the tunnel preemption event once the preempt
+// delay elapses.
+func (s *Server) scheduleTunnelPreemption(_ context.Context, ifsName string) {
+	delay := s.cfg.TunnelSelection.PreemptDelay
+	if s.cfg.TunnelSelection.QualityBased() {
+		// let the quality score of the tunnel to be evaluated first
+		delay = max(delay, 2*max(s.cfg.ProbeInterval, s.cfg.TunnelInterfaces[ifsName].ProbeInterval))
+	}
+
+	s.scheduleEvent(delay, func(ts time.Time) event.Event {
+		return &event.TunnelInterfacePreemptionDue{
+			BridgeInterface: s.cfg.BridgeInterface,
+			BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
+			TunnelInterface: ifsName,
+			Timestamp:       ts,
+			SpanContext:     s.startRootSpan(context.Background(), "tunnel_interface_preemption_due", ifsName),
+		}
+	})
+}
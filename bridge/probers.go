@@ -0,0 +1,124 @@
+package bridge
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/monitor"
+	"github.com/flashbots/vpnham/transponder"
+	"github.com/flashbots/vpnham/transponder/icmp"
+	"github.com/flashbots/vpnham/transponder/tcp"
+	"github.com/flashbots/vpnham/types"
+)
+
+// probeTarget is the additional target that is probed through the tunnel (on
+// top of the tunnel's peer at its probe_addr).  It has its own sequence space
+// and its own monitor.
+type probeTarget struct {
+	name      string
+	probeType string
+
+	monitor *monitor.Monitor
+	peer    *types.Peer
+	prober  transponder.Prober
+}
+
+// newProber returns the prober for the tunnel interface (or nil if the tunnel
+// is probed by the transponder with vpnham probes).
+func newProber(bridgeName string, ifs *config.TunnelInterface) (transponder.Prober, error) {
+	switch ifs.ProbeType {
+	case config.TunnelInterfaceProbeTypeBFD:
+		return newBFDSession(bridgeName, ifs)
+	case config.TunnelInterfaceProbeTypeICMP:
+		return newICMPProber(bridgeName, ifs)
+	case config.TunnelInterfaceProbeTypeTCP:
+		return newTCPProber(bridgeName, ifs)
+	default:
+		return nil, nil
+	}
+}
+
+func transponderSocketOptions(ifs *config.TunnelInterface) transponder.SocketOptions {
+	opts := transponder.SocketOptions{
+		Mark: ifs.Socket.Mark,
+		DSCP: ifs.Socket.DSCP,
+		TTL:  ifs.Socket.TTL,
+	}
+	if ifs.Socket.BindToDevice {
+		opts.BindToDevice = ifs.Name
+	}
+	return opts
+}
+
+func newProbeTarget(bridgeName string, ifs *config.TunnelInterface, t *config.ProbeTarget) (*probeTarget, error) {
+	m, err := ifs.Monitor.New(ifs.ThresholdDown, ifs.ThresholdUp)
+	if err != nil {
+		return nil, err
+	}
+
+	peer, err := types.NewPeer(ifs.Name, t.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// same as the tunnel's own prober, only pointed at the target
+	cfg := *ifs
+	cfg.ProbeAddr = t.Addr
+	cfg.ProbeType = t.ProbeType
+
+	var prober transponder.Prober
+	switch t.ProbeType {
+	case config.TunnelInterfaceProbeTypeICMP:
+		prober, err = newICMPProber(bridgeName, &cfg)
+	case config.TunnelInterfaceProbeTypeTCP:
+		prober, err = newTCPProber(bridgeName, &cfg)
+	default:
+		err = fmt.Errorf("%w: %s",
+			errBridgeProbeTypeIsNotSupported, t.ProbeType,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &probeTarget{
+		name:      t.Name,
+		probeType: t.ProbeType,
+
+		monitor: m,
+		peer:    peer,
+		prober:  prober,
+	}, nil
+}
+
+func newICMPProber(bridgeName string, ifs *config.TunnelInterface) (*icmp.Prober, error) {
+	localIP, _, err := ifs.Addr.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	peerIP, _, err := ifs.ProbeAddr.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return icmp.New(bridgeName, ifs.Name, localIP, peerIP)
+}
+
+func newTCPProber(bridgeName string, ifs *config.TunnelInterface) (*tcp.Prober, error) {
+	localIP, _, err := ifs.Addr.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	peerIP, peerPort, err := ifs.ProbeAddr.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return tcp.New(bridgeName, ifs.Name,
+		&net.TCPAddr{IP: localIP},
+		&net.TCPAddr{IP: peerIP, Port: peerPort},
+	)
+}
@@ -6,9 +6,11 @@ import (
 	"net"
 	"time"
 
+	"github.com/flashbots/vpnham/config"
 	"github.com/flashbots/vpnham/event"
 	"github.com/flashbots/vpnham/logutils"
 	"github.com/flashbots/vpnham/metrics"
+	"github.com/flashbots/vpnham/monitor"
 	"github.com/flashbots/vpnham/transponder"
 	"github.com/flashbots/vpnham/types"
 	"go.opentelemetry.io/otel/attribute"
@@ -16,71 +18,225 @@ import (
 	"go.uber.org/zap"
 )
 
-func (s *Server) sendProbes(ctx context.Context, _ chan<- error) {
+// runProbeLoop keeps probing the peer of the tunnel interface at the tunnel's
+// probe interval (or at the fast one, while the tunnel is suspected to fail).
+func (s *Server) runProbeLoop(ctx context.Context, ifsName string, failureSink chan<- error) {
+	timer := time.NewTimer(s.probeInterval(ifsName))
+	kick := s.probing.kick[ifsName]
+
+	s.probing.loops.Add(1)
+	go func() {
+		defer s.probing.loops.Done()
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-s.probing.done:
+				return
+			case <-kick:
+				if !timer.Stop() {
+					<-timer.C
+				}
+			case <-timer.C:
+				if prober, ok := s.probers[ifsName]; ok {
+					s.probeVia(ctx, ifsName, prober, failureSink)
+				} else {
+					s.sendProbe(ctx, ifsName, failureSink)
+					s.detectMissedProbes(ctx, ifsName, failureSink)
+				}
+				s.probeTargets(ctx, ifsName, failureSink)
+			}
+			timer.Reset(s.probeInterval(ifsName))
+		}
+	}()
+}
+
+// stopProbeLoops stops the probe (and the pmtu check) loops, and waits for the
+// ones that are in the middle of probing.
+func (s *Server) stopProbeLoops(_ context.Context) {
+	close(s.probing.done)
+	s.probing.loops.Wait()
+}
+
+func (s *Server) probeInterval(ifsName string) time.Duration {
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+
+	ifs := s.cfg.TunnelInterfaces[ifsName]
+	if s.status.Interfaces[ifsName].FastProbing {
+		return ifs.FastProbeInterval
+	}
+	return ifs.ProbeInterval
+}
+
+// adaptProbeInterval switches the tunnel interface to fast probing as soon as
+// a probe is missed, and back to the normal rate once the tunnel is healthy.
+func (s *Server) adaptProbeInterval(ctx context.Context, ifsName string, missed bool) {
+	if s.cfg.TunnelInterfaces[ifsName].FastProbeInterval == 0 {
+		return
+	}
+
 	l := logutils.LoggerFromContext(ctx)
 
-	for ifsName, peer := range s.peers {
-		l.Debug("Sending probe to a peer...",
-			zap.String("tunnel_interface", peer.InterfaceName()),
-		)
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
 
-		probe := &types.Probe{
-			Sequence:     peer.NextSequence(),
-			SrcUUID:      s.uuid,
-			SrcLocation:  s.cfg.ProbeLocation,
-			SrcTimestamp: time.Now(),
-			DstUUID:      peer.UUID(),
+	ifs := s.status.Interfaces[ifsName]
+	switch {
+	case missed && !ifs.FastProbing:
+		ifs.FastProbing = true
+		l.Info("Tunnel interface missed a probe; switching to fast probing...")
+		select {
+		case s.probing.kick[ifsName] <- struct{}{}:
+		default:
 		}
 
-		s.transponders[ifsName].SendProbe(probe, peer.ProbeAddr(), func(err error) {
-			if err != nil {
-				l.Error("Failed to send a probe",
-					zap.Error(err),
-					zap.String("tunnel_interface", peer.InterfaceName()),
-					zap.Uint64("sequence", probe.Sequence),
-				)
-				metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
-					attribute.String(metrics.LabelBridge, s.cfg.Name),
-					attribute.String(metrics.LabelErrorScope, metrics.ScopePeerProbing),
-				))
-				s.events <- &event.TunnelProbeSendFailure{ // emit event
-					TunnelInterface: ifsName,
-					ProbeSequence:   probe.Sequence,
-					Timestamp:       probe.SrcTimestamp,
-				}
-				return
-			}
+	case !missed && ifs.FastProbing && ifs.Up && s.tunnelMonitorStatus(ifsName) == monitor.Up:
+		ifs.FastProbing = false
+		l.Info("Tunnel interface is healthy; switching back to normal probing...")
+	}
+}
+
+func (s *Server) sendProbe(ctx context.Context, ifsName string, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	peer := s.peers[ifsName]
+
+	l.Debug("Sending probe to a peer...",
+		zap.String("tunnel_interface", peer.InterfaceName()),
+	)
+
+	probe := &types.Probe{
+		Sequence:     peer.NextSequence(),
+		SrcUUID:      s.uuid,
+		SrcLocation:  s.cfg.ProbeLocation,
+		SrcTimestamp: time.Now(),
+		DstUUID:      peer.UUID(),
+		Padding:      types.ProbeBridgeSize(), // room for the responder's bridge state
+	}
 
-			l.Debug("Sent a probe",
+	s.transponders[ifsName].SendProbe(probe, peer.ProbeAddr(), func(err error) {
+		if err != nil {
+			l.Error("Failed to send a probe",
+				zap.Error(err),
 				zap.String("tunnel_interface", peer.InterfaceName()),
 				zap.Uint64("sequence", probe.Sequence),
 			)
-			s.events <- &event.TunnelProbeSendSuccess{ // emit event
+			metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
+				attribute.String(metrics.LabelBridge, s.cfg.Name),
+				attribute.String(metrics.LabelErrorScope, metrics.ScopePeerProbing),
+			))
+			s.events <- &event.TunnelProbeSendFailure{ // emit event
 				TunnelInterface: ifsName,
 				ProbeSequence:   probe.Sequence,
 				Timestamp:       probe.SrcTimestamp,
 			}
-		})
+			return
+		}
+
+		l.Debug("Sent a probe",
+			zap.String("tunnel_interface", peer.InterfaceName()),
+			zap.Uint64("sequence", probe.Sequence),
+		)
+		s.events <- &event.TunnelProbeSendSuccess{ // emit event
+			TunnelInterface: ifsName,
+			ProbeSequence:   probe.Sequence,
+			Timestamp:       probe.SrcTimestamp,
+		}
+	})
+}
+
+// probeVia probes the peer of the tunnel interface with the prober (instead of
+// vpnham probes), and feeds the outcome into the same events pipeline.
+func (s *Server) probeVia(ctx context.Context, ifsName string, prober transponder.Prober, _ chan<- error) {
+	probeType := s.cfg.TunnelInterfaces[ifsName].ProbeType
+
+	s.probeWith(ctx, ifsName, "", probeType, s.peers[ifsName], prober)
+}
+
+// probeTargets probes the additional targets behind the tunnel interface.
+func (s *Server) probeTargets(ctx context.Context, ifsName string, _ chan<- error) {
+	for _, t := range s.targets[ifsName] {
+		s.probeWith(ctx, ifsName, t.name, t.probeType, t.peer, t.prober)
 	}
 }
 
-func (s *Server) detectMissedProbes(ctx context.Context, _ chan<- error) {
+func (s *Server) probeWith(
+	ctx context.Context,
+	ifsName, target, probeType string,
+	peer *types.Peer,
+	prober transponder.Prober,
+) {
 	l := logutils.LoggerFromContext(ctx)
 
-	for ifsName, peer := range s.peers {
-		for missed := peer.Acknowledgement() + 1; missed < peer.Sequence(); missed++ {
-			l.Debug("Missed a probe (gap in acknowledgement)",
-				zap.String("tunnel_interface", peer.InterfaceName()),
-				zap.Uint64("sequence", missed),
+	sequence := peer.NextSequence()
+	ts := time.Now()
+
+	s.events <- &event.TunnelProbeSendSuccess{ // emit event
+		TunnelInterface: ifsName,
+		ProbeSequence:   sequence,
+		ProbeTarget:     target,
+		Timestamp:       ts,
+	}
+
+	// the probe is deemed lost if there's no reply before the next one is due
+	ctx, cancel := context.WithTimeout(ctx, s.probeInterval(ifsName))
+
+	prober.Probe(ctx, sequence, func(rtt time.Duration, err error) {
+		cancel()
+		peer.SetAcknowledgement(sequence)
+
+		if err != nil {
+			l.Debug("Probe failed",
+				zap.Error(err),
+				zap.String("probe_type", probeType),
+				zap.String("tunnel_interface", ifsName),
+				zap.String("probe_target", target),
+				zap.Uint64("sequence", sequence),
 			)
 			s.events <- &event.TunnelProbeReturnFailure{ // emit event
 				TunnelInterface: ifsName,
+				ProbeSequence:   sequence,
+				ProbeTarget:     target,
 				Timestamp:       time.Now(),
-				ProbeSequence:   missed,
 			}
+			return
+		}
+
+		ret := peer.RegisterProbeResult(sequence, rtt)
+		if ret.Duplicate {
+			return
+		}
+		s.events <- &event.TunnelProbeReturnSuccess{ // emit event
+			TunnelInterface: ifsName,
+			ProbeSequence:   sequence,
+			ProbeTarget:     target,
+			Timestamp:       time.Now(),
+			ProbeType:       probeType,
+
+			RTT:        rtt,
+			OutOfOrder: ret.OutOfOrder,
+		}
+	})
+}
+
+func (s *Server) detectMissedProbes(ctx context.Context, ifsName string, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	peer := s.peers[ifsName]
+
+	for missed := peer.Acknowledgement() + 1; missed < peer.Sequence(); missed++ {
+		l.Debug("Missed a probe (gap in acknowledgement)",
+			zap.String("tunnel_interface", peer.InterfaceName()),
+			zap.Uint64("sequence", missed),
+		)
+		s.events <- &event.TunnelProbeReturnFailure{ // emit event
+			TunnelInterface: ifsName,
+			Timestamp:       time.Now(),
+			ProbeSequence:   missed,
 		}
-		peer.SetAcknowledgement(peer.Sequence() - 1)
 	}
+	peer.SetAcknowledgement(peer.Sequence() - 1)
 }
 
 func (s *Server) respondToProbe(
@@ -95,6 +251,7 @@ func (s *Server) respondToProbe(
 	// in by the sender (for the sender's bookkeeping)
 	probe.DstLocation = s.cfg.ProbeLocation
 	probe.DstTimestamp = time.Now()
+	probe.DstBridge = s.probeBridge.Load()
 
 	tp.SendProbe(probe, from, func(err error) {
 		if err == nil {
@@ -149,6 +306,19 @@ func (s *Server) processReturnedProbe(
 		))
 		return
 	}
+	// account for the probe stats
+	ret := peer.RegisterReturnedProbe(probe, ts)
+	if ret.Duplicate {
+		l.Debug("Duplicate probe",
+			zap.String("tunnel_interface", peer.InterfaceName()),
+			zap.Uint64("sequence", probe.Sequence),
+		)
+		metrics.ProbesDuplicate.Add(ctx, 1, otelapi.WithAttributes(
+			attribute.String(metrics.LabelBridge, s.cfg.Name),
+			attribute.String(metrics.LabelTunnel, tp.InterfaceName()),
+		))
+		return
+	}
 	// detect missed probes
 	for missed := peer.Acknowledgement() + 1; missed < probe.Sequence; missed++ {
 		l.Debug("Missed a probe (later probe came in)",
@@ -162,13 +332,21 @@ func (s *Server) processReturnedProbe(
 		}
 	}
 	peer.SetAcknowledgement(probe.Sequence)
+	if probe.DstBridge != nil {
+		peer.SetFarEnd(probe.Sequence, probe.DstLocation, probe.DstBridge, ts)
+	}
 	s.events <- &event.TunnelProbeReturnSuccess{ // emit event
 		TunnelInterface: tp.InterfaceName(),
+		ProbeType:       config.TunnelInterfaceProbeTypeUDP,
 		LatencyForward:  probe.DstTimestamp.Sub(probe.SrcTimestamp),
 		LatencyReturn:   ts.Sub(probe.DstTimestamp),
 		Location:        probe.DstLocation.String(),
 		ProbeSequence:   probe.Sequence,
 		Timestamp:       ts,
+
+		RTT:         ret.RTT,
+		ClockOffset: ret.ClockOffset,
+		OutOfOrder:  ret.OutOfOrder,
 	}
 }
 
@@ -0,0 +1,99 @@
+package bridge
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/flashbots/vpnham/event"
+	"github.com/flashbots/vpnham/logutils"
+	"github.com/flashbots/vpnham/quality"
+	"github.com/flashbots/vpnham/selector"
+	"go.uber.org/zap"
+)
+
+// evaluateTunnelQuality refreshes the quality scores of the tunnels and (if
+// the quality-based selection is configured) checks whether the active tunnel
+// should give way to a better one.
+func (s *Server) evaluateTunnelQuality(ctx context.Context, ts time.Time, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	var degraded *event.TunnelInterfaceDegraded
+
+	func() {
+		s.mxStatus.Lock()
+		defer s.mxStatus.Unlock()
+
+		active := ""
+		scores := make(map[string]float64, len(s.status.Interfaces))
+		for ifsName, ifs := range s.status.Interfaces {
+			stats := s.peers[ifsName].Stats()
+
+			ifs.LossRatio = stats.LossRatio
+			ifs.RTTUs = stats.SmoothedRTT.Microseconds()
+			ifs.JitterUs = stats.Jitter.Microseconds()
+
+			if ifs.Up {
+				ifs.Score = quality.Score(stats, s.cfg.TunnelSelection.Weights)
+				if !(ifs.Suppressed || s.mtuDegraded(ifsName)) || ifs.Active {
+					scores[ifsName] = ifs.Score
+				}
+			} else {
+				ifs.Score = quality.MinScore
+			}
+
+			if ifs.Active {
+				active = ifsName
+			}
+		}
+
+		if !s.cfg.TunnelSelection.QualityBased() || active == "" {
+			return
+		}
+
+		if s.selector.PreferActiveFarEnd {
+			// never trade the tunnel towards the active far end for the one
+			// that is not
+			tunnels := s.tunnels()
+			idx := slices.IndexFunc(tunnels, func(t selector.Tunnel) bool {
+				return t.Name == active
+			})
+			for _, t := range tunnels {
+				if tunnels[idx].FarEndActive && !t.FarEndActive {
+					delete(scores, t.Name)
+				}
+			}
+		}
+
+		promoted := s.quality.Evaluate(active, scores, ts)
+		if promoted == "" {
+			return
+		}
+
+		degraded = &event.TunnelInterfaceDegraded{
+			BridgeInterface: s.cfg.BridgeInterface,
+			BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
+			TunnelInterface: active,
+			Timestamp:       ts,
+
+			Score:         scores[active],
+			Promoted:      promoted,
+			PromotedScore: scores[promoted],
+		}
+	}()
+
+	if degraded == nil {
+		return
+	}
+
+	l.Info("Tunnel interface quality degraded",
+		zap.String("tunnel_interface", degraded.TunnelInterface),
+		zap.Float64("score", degraded.Score),
+		zap.String("promoted_tunnel_interface", degraded.Promoted),
+		zap.Float64("promoted_score", degraded.PromotedScore),
+	)
+
+	degraded.SpanContext = s.startRootSpan(ctx, "tunnel_interface_degraded", degraded.TunnelInterface)
+
+	s.events <- degraded // emit event
+}
@@ -0,0 +1,43 @@
+package bridge
+
+import (
+	"sync"
+
+	"github.com/flashbots/vpnham/event"
+)
+
+// queue keeps the events that are derived while holding the status locks (or
+// by the event loop itself).  It is unbounded, so that emitting never blocks
+// (otherwise the loop could wait for itself to drain the events channel).
+type queue struct {
+	mx     sync.Mutex
+	events []event.Event
+	ready  chan struct{}
+}
+
+// emit enqueues the event for the event loop.
+func (s *Server) emit(e event.Event) {
+	s.derived.mx.Lock()
+	s.derived.events = append(s.derived.events, e)
+	s.derived.mx.Unlock()
+
+	select {
+	case s.derived.ready <- struct{}{}:
+	default:
+		// the loop is notified already
+	}
+}
+
+// dequeue returns the oldest derived event (or nil if there's none).
+func (s *Server) dequeue() event.Event {
+	s.derived.mx.Lock()
+	defer s.derived.mx.Unlock()
+
+	if len(s.derived.events) == 0 {
+		return nil
+	}
+	e := s.derived.events[0]
+	s.derived.events[0] = nil
+	s.derived.events = s.derived.events[1:]
+	return e
+}
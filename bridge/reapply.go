@@ -16,12 +16,12 @@ func (s *Server) reapplyUpdates(_ context.Context, _ chan<- error) {
 			if s.status.Active {
 				reapply.Next = time.Time{} // avoid re-fire
 
-				s.events <- &event.BridgeReactivated{ // emit event
+				s.emit(&event.BridgeReactivated{ // emit event
 					BridgeInterface: s.cfg.BridgeInterface,
 					BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
 					Iteration:       reapply.Count,
 					Timestamp:       time.Now(),
-				}
+				})
 			}
 		}
 	}
@@ -31,13 +31,13 @@ func (s *Server) reapplyUpdates(_ context.Context, _ chan<- error) {
 			if activeInterface := s.status.ActiveInterface(); activeInterface != "" {
 				reapply.Next = time.Time{} // avoid re-fire
 
-				s.events <- &event.TunnelInterfaceReactivated{ // emit event
+				s.emit(&event.TunnelInterfaceReactivated{ // emit event
 					BridgeInterface: s.cfg.BridgeInterface,
 					BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
 					Iteration:       reapply.Count,
 					TunnelInterface: activeInterface,
 					Timestamp:       time.Now(),
-				}
+				})
 			}
 		}
 	}
@@ -0,0 +1,129 @@
+package bridge
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/flashbots/vpnham/event"
+	"github.com/flashbots/vpnham/selector"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tunnels returns the snapshot of the tunnel interfaces for the selector.
+//
+// Must be called while holding the status lock.
+func (s *Server) tunnels() []selector.Tunnel {
+	res := make([]selector.Tunnel, 0, len(s.status.Interfaces))
+	for ifsName, ifs := range s.status.Interfaces {
+		res = append(res, selector.Tunnel{
+			Name:     ifsName,
+			Priority: s.cfg.TunnelInterfaces[ifsName].Priority,
+			Score:    ifs.Score,
+
+			Active:     ifs.Active,
+			Up:         ifs.Up,
+			UpSince:    ifs.UpSince,
+			Suppressed: ifs.Suppressed || s.mtuDegraded(ifsName),
+
+			FarEndActive: ifs.FarEnd != nil && ifs.FarEnd.Active && !ifs.FarEnd.Stale,
+		})
+	}
+	return res
+}
+
+// activateTunnelInterface deactivates the currently active tunnel interface
+// (if any), and then activates the promoted one (initiating its ipsec sa, if
+// configured to).
+//
+// Must be called while holding the status lock.
+func (s *Server) activateTunnelInterface(ctx context.Context, promoted string, ts time.Time) {
+	for demotedIfsName, demotedIfs := range s.status.Interfaces {
+		if demotedIfsName == promoted || !demotedIfs.Active {
+			continue
+		}
+		demotedIfs.Active = false
+		demotedIfs.ActiveSince = ts
+		s.emit(&event.TunnelInterfaceDeactivated{ // emit event
+			BridgeInterface: s.cfg.BridgeInterface,
+			BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
+			TunnelInterface: demotedIfsName,
+			Timestamp:       ts,
+			SpanContext:     trace.SpanContextFromContext(ctx),
+		})
+	}
+
+	ifs := s.status.Interfaces[promoted]
+	if ifs.Active {
+		return
+	}
+	ifs.Active = true
+	ifs.ActiveSince = ts
+	s.emit(&event.TunnelInterfaceActivated{ // emit event
+		BridgeInterface: s.cfg.BridgeInterface,
+		BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
+		TunnelInterface: promoted,
+		Timestamp:       ts,
+		SpanContext:     trace.SpanContextFromContext(ctx),
+	})
+
+	s.initiateIPsec(ctx, promoted)
+}
+
+// preemptTunnelInterface makes the tunnel interface active if there's no other
+// active one, or if it should preempt the active one right away.  Otherwise
+// (if the tunnel has higher priority than the active one) the preemption is
+// re-evaluated later on with a timed event.
+//
+// Must be called while holding the status lock.
+func (s *Server) preemptTunnelInterface(ctx context.Context, ifsName string, ts time.Time) {
+	tunnels := s.tunnels()
+	if s.selector.Preemption(tunnels, ts) == ifsName {
+		s.activateTunnelInterface(ctx, ifsName, ts)
+		return
+	}
+
+	if s.selector.DisablePreemption || s.status.Interfaces[ifsName].Suppressed || s.mtuDegraded(ifsName) {
+		return
+	}
+
+	idx := slices.IndexFunc(tunnels, func(t selector.Tunnel) bool {
+		return t.Name == ifsName
+	})
+	for _, t := range tunnels {
+		if t.Active && s.selector.Outranks(tunnels[idx], t) {
+			s.scheduleTunnelPreemption(ctx, ifsName)
+			return
+		}
+	}
+}
+
+// preemptionHeldBack returns true if the tunnel interface is eligible to
+// preempt the active one (i.e. it has been up for the preempt delay and it
+// outranks the active one), but the selector still holds it back (e.g. due to
+// its quality score).
+//
+// Must be called while holding the status lock.
+func (s *Server) preemptionHeldBack(tunnels []selector.Tunnel, ifsName string, ts time.Time) bool {
+	if s.selector.DisablePreemption {
+		return false
+	}
+
+	idx := slices.IndexFunc(tunnels, func(t selector.Tunnel) bool {
+		return t.Name == ifsName
+	})
+	if idx == -1 {
+		return false
+	}
+	t := tunnels[idx]
+	if !t.Up || t.Suppressed || ts.Sub(t.UpSince) < s.selector.PreemptDelay {
+		return false
+	}
+
+	for _, active := range tunnels {
+		if active.Active && s.selector.Outranks(t, active) {
+			return true
+		}
+	}
+	return false
+}
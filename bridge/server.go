@@ -4,20 +4,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/dampening"
+	"github.com/flashbots/vpnham/dashboard"
 	"github.com/flashbots/vpnham/event"
 	"github.com/flashbots/vpnham/httplogger"
+	"github.com/flashbots/vpnham/journal"
+	"github.com/flashbots/vpnham/lease"
 	"github.com/flashbots/vpnham/logutils"
 	"github.com/flashbots/vpnham/monitor"
+	"github.com/flashbots/vpnham/quality"
 	"github.com/flashbots/vpnham/reconciler"
+	"github.com/flashbots/vpnham/selector"
+	"github.com/flashbots/vpnham/signing"
+	"github.com/flashbots/vpnham/tlsutils"
 	"github.com/flashbots/vpnham/transponder"
+	"github.com/flashbots/vpnham/transponder/bfd"
 	"github.com/flashbots/vpnham/types"
-	"github.com/flashbots/vpnham/utils"
+	"github.com/flashbots/vpnham/watcher"
+	"github.com/flashbots/vpnham/witness"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -27,19 +37,50 @@ type Server struct {
 
 	uuid uuid.UUID
 
+	journal    *journal.Journal
 	reconciler *reconciler.Reconciler
 	server     *http.Server
 	ticker     *time.Ticker
+	tls        *tlsutils.Reloader
 
-	http           *http.Client
-	partner        *types.Partner
-	partnerMonitor *monitor.Monitor
+	partnerPaths []*partnerPath
 
+	signer         *signing.Signer
+	statusSequence atomic.Uint64
+
+	probeBridge atomic.Pointer[types.ProbeBridge] // our state as reported in the probe responses
+
+	witness *witness.Client
+	lease   *lease.Keeper
+
+	dampeners    map[string]*dampening.Dampener
 	monitors     map[string]*monitor.Monitor
+	quality      *quality.Tracker
+	selector     *selector.Selector
 	peers        map[string]*types.Peer
+	pmtu         map[string]*pmtuChecker
+	probers      map[string]transponder.Prober
+	targets      map[string][]*probeTarget
 	transponders map[string]*transponder.Transponder
 
-	events chan event.Event
+	events  chan event.Event
+	calls   calls
+	derived queue
+	timers  timers
+	traces  traces
+
+	probing struct {
+		done  chan struct{}
+		kick  map[string]chan struct{}
+		loops sync.WaitGroup
+	}
+
+	watchers struct {
+		ipsec     map[string]*watcher.IPsec
+		link      *watcher.Link
+		wireguard map[string]*watcher.WireGuard
+		down      map[string]map[string]struct{} // sources reporting the link down
+	}
 
 	partnerStatus   *types.BridgeStatus
 	mxPartnerStatus sync.Mutex
@@ -54,7 +95,11 @@ type Server struct {
 }
 
 const (
-	pathStatus = "status"
+	pathDashboard = "dashboard"
+	pathHistory   = "history"
+	pathJobs      = "jobs"
+	pathStatus    = "status"
+	pathTopology  = "topology"
 )
 
 func NewServer(ctx context.Context, cfg *config.Bridge) (*Server, error) {
@@ -69,50 +114,80 @@ func NewServer(ctx context.Context, cfg *config.Bridge) (*Server, error) {
 
 	cfg.UUID = _uuid
 
-	reconciler, err := reconciler.New(cfg.Name, cfg.Reconcile)
+	journal, err := journal.New(cfg.Name, cfg.History)
 	if err != nil {
 		return nil, err
 	}
 
-	dialer := &net.Dialer{
-		Timeout:   cfg.PartnerStatusTimeout,
-		KeepAlive: 2 * cfg.PartnerStatusTimeout,
+	reconciler, err := reconciler.New(cfg.Name, cfg.Reconcile, journal)
+	if err != nil {
+		return nil, err
 	}
-	if cfg.PartnerPollingInterface != "" {
-		ipv4s, ipv6s, err := utils.GetInterfaceIPs(cfg.PartnerPollingInterface)
-		if err != nil {
+
+	var signer *signing.Signer
+	if cfg.StatusSigning != nil {
+		if signer, err = signing.New(cfg.StatusSigning); err != nil {
 			return nil, err
 		}
-		if len(ipv4s) > 0 {
-			dialer.LocalAddr = &net.TCPAddr{IP: net.ParseIP(ipv4s[0])}
-		} else if len(ipv6s) > 0 {
-			dialer.LocalAddr = &net.TCPAddr{IP: net.ParseIP(ipv6s[0])}
-		}
 	}
-	transport := &http.Transport{
-		DialContext:     dialer.DialContext,
-		IdleConnTimeout: 4 * cfg.PartnerStatusTimeout,
-		MaxIdleConns:    2,
+
+	var witnessClient *witness.Client
+	if cfg.Witness.Enabled() {
+		witnessClient, err = witness.NewClient(cfg.Witness.URL, cfg.Name, string(cfg.Role), cfg.Witness.Timeout)
+		if err != nil {
+			return nil, err
+		}
 	}
-	cli := &http.Client{
-		Transport: transport,
-		Timeout:   cfg.PartnerStatusTimeout,
+
+	var leaseKeeper *lease.Keeper
+	if cfg.Lease.Enabled() {
+		backend, err := lease.New(cfg.Lease, string(cfg.Role))
+		if err != nil {
+			return nil, err
+		}
+		leaseKeeper = lease.NewKeeper(backend, cfg.Lease.TTL, cfg.ProbeInterval, cfg.Lease.Timeout)
 	}
 
-	partner, err := types.NewPartner(cfg.PartnerURL)
-	if err != nil {
-		return nil, err
+	var tls *tlsutils.Reloader
+	if cfg.StatusTLS != nil {
+		tls, err = tlsutils.NewReloader(cfg.StatusTLS.CA, cfg.StatusTLS.Cert, cfg.StatusTLS.Key, cfg.StatusTLS.ReloadInterval)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	partnerMonitor, err := func() (*monitor.Monitor, error) {
+	newPartnerMonitor := func() (*monitor.Monitor, error) {
 		if cfg.Role == types.RoleActive {
-			return monitor.New(cfg.PartnerStatusThresholdDown, cfg.PartnerStatusThresholdUp)
-		} else {
-			return monitor.New(cfg.PartnerStatusThresholdDown+1, cfg.PartnerStatusThresholdUp+1)
+			return cfg.PartnerStatusMonitor.New(cfg.PartnerStatusThresholdDown, cfg.PartnerStatusThresholdUp)
 		}
-	}()
-	if err != nil {
-		return nil, err
+		// standby bridge reacts one poll later than the active one
+		m := *cfg.PartnerStatusMonitor
+		m.DownAfter += cfg.ProbeInterval
+		m.UpAfter += cfg.ProbeInterval
+		return m.New(cfg.PartnerStatusThresholdDown+1, cfg.PartnerStatusThresholdUp+1)
+	}
+
+	paths := make([]*config.PartnerPath, 0, cfg.PartnerPathsCount())
+	paths = append(paths, &config.PartnerPath{
+		Name:             config.DefaultPartnerPathName,
+		URL:              cfg.PartnerURL,
+		PollingInterface: cfg.PartnerPollingInterface,
+	})
+	paths = append(paths, cfg.PartnerPaths...)
+
+	partnerPaths := make([]*partnerPath, 0, len(paths))
+	for _, p := range paths {
+		m, err := newPartnerMonitor()
+		if err != nil {
+			return nil, err
+		}
+		path, err := newPartnerPath(cfg, p.Name, p.URL, p.PollingInterface, m, tls)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w",
+				p.Name, err,
+			)
+		}
+		partnerPaths = append(partnerPaths, path)
 	}
 
 	s := &Server{
@@ -120,30 +195,67 @@ func NewServer(ctx context.Context, cfg *config.Bridge) (*Server, error) {
 
 		uuid: _uuid,
 
+		journal:    journal,
 		reconciler: reconciler,
 		ticker:     time.NewTicker(cfg.ProbeInterval),
+		tls:        tls,
+
+		partnerPaths: partnerPaths,
 
-		http:           cli,
-		partner:        partner,
-		partnerMonitor: partnerMonitor,
+		signer: signer,
 
-		monitors:     make(map[string]*monitor.Monitor, cfg.TunnelInterfacesCount()),
+		witness: witnessClient,
+		lease:   leaseKeeper,
+
+		dampeners: make(map[string]*dampening.Dampener, cfg.TunnelInterfacesCount()),
+		monitors:  make(map[string]*monitor.Monitor, cfg.TunnelInterfacesCount()),
+		quality:   quality.NewTracker(cfg.TunnelSelection.Margin, cfg.TunnelSelection.HoldTime),
+		selector: &selector.Selector{
+			QualityBased:      cfg.TunnelSelection.QualityBased(),
+			PreemptDelay:      cfg.TunnelSelection.PreemptDelay,
+			DisablePreemption: cfg.TunnelSelection.DisablePreemption,
+
+			PreferActiveFarEnd: cfg.TunnelSelection.PreferActiveFarEnd,
+		},
 		peers:        make(map[string]*types.Peer, cfg.TunnelInterfacesCount()),
+		pmtu:         make(map[string]*pmtuChecker, cfg.TunnelInterfacesCount()),
+		probers:      make(map[string]transponder.Prober, cfg.TunnelInterfacesCount()),
+		targets:      make(map[string][]*probeTarget, cfg.TunnelInterfacesCount()),
 		transponders: make(map[string]*transponder.Transponder, cfg.TunnelInterfacesCount()),
 
-		events: make(chan event.Event, 2*cfg.TunnelInterfacesCount()),
+		events: make(chan event.Event, eventsBufferSize(cfg)),
+		calls: calls{
+			pending: make(map[string]context.CancelFunc),
+		},
+		derived: queue{
+			ready: make(chan struct{}, 1),
+		},
+		timers: timers{
+			pending: make(map[*time.Timer]struct{}),
+		},
 
 		status: &types.BridgeStatus{
 			Name:        cfg.Name,
 			Active:      false, // inactive at start, activate only when tunnels are up
 			ActiveSince: ts,
 			Role:        cfg.Role,
+			Location:    cfg.ProbeLocation.String(),
 			Interfaces:  make(map[string]*types.TunnelInterfaceStatus, cfg.TunnelInterfacesCount()),
 		},
 	}
 
+	s.probeBridge.Store(&types.ProbeBridge{})
+
+	// start the sequence from the current time, so that it keeps on
+	// increasing across the restarts (as the partner remembers the latest)
+	s.statusSequence.Store(uint64(ts.UnixNano()))
+
 	mux := http.NewServeMux()
+	mux.Handle("/"+pathDashboard+"/", http.StripPrefix("/"+pathDashboard, dashboard.Handler()))
+	mux.Handle("/"+pathHistory, http.HandlerFunc(s.handleHistory))
+	mux.Handle("/"+pathJobs, http.HandlerFunc(s.handleJobs))
 	mux.Handle("/"+pathStatus, http.HandlerFunc(s.handleStatus))
+	mux.Handle("/"+pathTopology, http.HandlerFunc(s.handleTopology))
 	handler := httplogger.Middleware(l, mux)
 
 	s.server = &http.Server{
@@ -155,10 +267,20 @@ func NewServer(ctx context.Context, cfg *config.Bridge) (*Server, error) {
 		ReadTimeout:       30 * time.Second,
 		WriteTimeout:      30 * time.Second,
 	}
+	if tls != nil {
+		s.server.TLSConfig = tls.ServerConfig()
+	}
+
+	s.probing.done = make(chan struct{})
+	s.probing.kick = make(map[string]chan struct{}, cfg.TunnelInterfacesCount())
+
+	s.watchers.ipsec = make(map[string]*watcher.IPsec)
+	s.watchers.wireguard = make(map[string]*watcher.WireGuard)
+	s.watchers.down = make(map[string]map[string]struct{}, cfg.TunnelInterfacesCount())
 
 	for ifsName, ifs := range cfg.TunnelInterfaces {
 		// monitor
-		m, err := monitor.New(ifs.ThresholdDown, ifs.ThresholdUp)
+		m, err := ifs.Monitor.New(ifs.ThresholdDown, ifs.ThresholdUp)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w",
 				ifsName, err,
@@ -166,6 +288,11 @@ func NewServer(ctx context.Context, cfg *config.Bridge) (*Server, error) {
 		}
 		s.monitors[ifsName] = m
 
+		// dampener
+		if ifs.Dampening.Enabled() {
+			s.dampeners[ifsName] = dampening.New(ifs.Dampening)
+		}
+
 		// peer
 		peer, err := types.NewPeer(ifsName, ifs.ProbeAddr)
 		if err != nil {
@@ -175,15 +302,55 @@ func NewServer(ctx context.Context, cfg *config.Bridge) (*Server, error) {
 		}
 		s.peers[ifsName] = peer
 
-		// transponder
-		tp, err := transponder.New(cfg.Name, ifsName, ifs.Addr)
+		// prober
+		prober, err := newProber(cfg.Name, ifs)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w",
 				ifsName, err,
 			)
 		}
-		tp.Receive = s.handleProbe
-		s.transponders[ifsName] = tp
+
+		if prober != nil {
+			if session, ok := prober.(*bfd.Session); ok {
+				s.watchBFDSession(ifsName, session)
+			}
+			s.probers[ifsName] = prober
+		} else {
+			// transponder
+			tp, err := transponder.New(cfg.Name, ifsName, ifs.Addr, transponderSocketOptions(ifs))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w",
+					ifsName, err,
+				)
+			}
+			tp.Receive = s.handleProbe
+			s.transponders[ifsName] = tp
+		}
+
+		// additional probe targets
+		for _, t := range ifs.ProbeTargets {
+			target, err := newProbeTarget(cfg.Name, ifs, t)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w",
+					ifsName, t.Name, err,
+				)
+			}
+			s.targets[ifsName] = append(s.targets[ifsName], target)
+		}
+
+		// path mtu checker
+		if ifs.PMTU.Enabled() {
+			c, err := newPMTUChecker()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w",
+					ifsName, err,
+				)
+			}
+			s.pmtu[ifsName] = c
+		}
+
+		// probe loop
+		s.probing.kick[ifsName] = make(chan struct{}, 1)
 
 		// status
 		s.status.Interfaces[ifsName] = &types.TunnelInterfaceStatus{
@@ -191,9 +358,16 @@ func NewServer(ctx context.Context, cfg *config.Bridge) (*Server, error) {
 			ActiveSince: ts,
 			Up:          false,
 			UpSince:     ts,
+
+			Priority:        ifs.Priority,
+			SelectionPolicy: cfg.TunnelSelection.Policy,
 		}
 	}
 
+	if err := s.newWatchers(); err != nil {
+		return nil, err
+	}
+
 	if cfg.Reconcile.BridgeActivate.Reapply.Enabled() {
 		s.reapply.bridgeActivate = &types.ReapplyStatus{}
 	}
@@ -218,16 +392,51 @@ func (s *Server) Run(ctx context.Context, failureSink chan<- error) {
 		tp.Run(ctx, failureSink)
 	}
 
+	for _, p := range s.probers {
+		p.Run(ctx, failureSink)
+	}
+
+	for _, targets := range s.targets {
+		for _, t := range targets {
+			t.prober.Run(ctx, failureSink)
+		}
+	}
+
+	if s.tls != nil {
+		s.tls.Run(ctx)
+	} else {
+		l.Warn("Bridge status is served and polled over plain http (insecure); " +
+			"anyone who can reach the status address can impersonate the partner",
+		)
+	}
+
 	go func() {
 		l.Info("VPN HA-monitor bridge server is going up...",
 			zap.String("bridge_listen_address", s.server.Addr),
+			zap.Bool("bridge_tls", s.tls != nil),
 		)
-		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		var err error
+		if s.tls != nil {
+			err = s.server.ListenAndServeTLS("", "") // certificates come from the tls config
+		} else {
+			err = s.server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			failureSink <- err
 		}
 		l.Info("VPN HA-monitor bridge server is down")
 	}()
 
+	for ifsName := range s.peers {
+		s.runProbeLoop(ctx, ifsName, failureSink)
+	}
+
+	for ifsName := range s.pmtu {
+		s.runPMTUCheckLoop(ctx, ifsName, failureSink)
+	}
+
+	s.runWatchers(ctx, failureSink)
+
 	go func() {
 		for {
 			s.handleTick(ctx, <-s.ticker.C, failureSink)
@@ -243,10 +452,28 @@ func (s *Server) Stop(ctx context.Context) {
 
 	s.reconciler.Stop(ctx)
 
+	s.stopProbeLoops(ctx)
+
+	s.stopWatchers(ctx)
+
+	// the probers report the outcomes into the event loop, therefore they
+	// must be stopped (with in-flight probes accounted for) before it is
+	for _, p := range s.probers {
+		p.Stop(ctx)
+	}
+
+	for _, targets := range s.targets {
+		for _, t := range targets {
+			t.prober.Stop(ctx)
+		}
+	}
+
 	s.stopEventLoop(ctx)
 
 	s.ticker.Stop()
 
+	s.closeLease(ctx)
+
 	for _, t := range s.transponders {
 		t.Stop(ctx)
 	}
@@ -258,4 +485,14 @@ func (s *Server) Stop(ctx context.Context) {
 			zap.Error(err),
 		)
 	}
+
+	if s.tls != nil {
+		s.tls.Stop(ctx)
+	}
+
+	if err := s.journal.Close(); err != nil {
+		l.Error("VPN HA-monitor bridge history file close failed",
+			zap.Error(err),
+		)
+	}
 }
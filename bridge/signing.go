@@ -0,0 +1,84 @@
+package bridge
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flashbots/vpnham/types"
+)
+
+var (
+	errPartnerStatusIsReplayed    = errors.New("partner status is replayed")
+	errPartnerStatusIsStale       = errors.New("partner status is stale")
+	errPartnerStatusNonceMismatch = errors.New("partner status has nonce mismatch")
+)
+
+// signedStatus wraps our status into the signed document.
+//
+// Must be called while holding the status lock.
+func (s *Server) signedStatus(nonce string) (*types.SignedBridgeStatus, error) {
+	status, err := json.Marshal(s.status)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &types.SignedBridgeStatus{
+		Status:    status,
+		Timestamp: time.Now(),
+		Sequence:  s.statusSequence.Add(1),
+		Nonce:     nonce,
+	}
+	s.signer.Sign(doc)
+
+	return doc, nil
+}
+
+// verifyPartnerStatus makes sure that the partner's status document is
+// authentic, fresh, and is the response to our poll with the nonce (and not a
+// replayed or a cached one).
+func (s *Server) verifyPartnerStatus(path *partnerPath, b []byte, nonce string) (*types.BridgeStatus, error) {
+	doc := &types.SignedBridgeStatus{}
+	if err := json.Unmarshal(b, doc); err != nil {
+		return nil, err
+	}
+
+	if err := s.signer.Verify(doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Nonce != nonce {
+		return nil, errPartnerStatusNonceMismatch
+	}
+
+	if age := time.Since(doc.Timestamp).Abs(); age > s.cfg.StatusSigning.MaxAge {
+		return nil, fmt.Errorf("%w: signed %s ago (or ahead)",
+			errPartnerStatusIsStale, age,
+		)
+	}
+
+	if doc.Sequence <= path.statusSequence {
+		return nil, fmt.Errorf("%w: sequence %d is not after %d",
+			errPartnerStatusIsReplayed, doc.Sequence, path.statusSequence,
+		)
+	}
+	path.statusSequence = doc.Sequence
+
+	status := &types.BridgeStatus{}
+	if err := json.Unmarshal(doc.Status, status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
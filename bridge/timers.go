@@ -0,0 +1,60 @@
+package bridge
+
+import (
+	"sync"
+	"time"
+
+	"github.com/flashbots/vpnham/event"
+)
+
+// timers keeps track of the events that are scheduled to be emitted into the
+// event loop at some later point in time.
+type timers struct {
+	mx       sync.Mutex
+	pending  map[*time.Timer]struct{}
+	inflight sync.WaitGroup
+	stopped  bool
+}
+
+// scheduleEvent emits the event (produced at the moment when the delay
+// elapses) into the event loop.
+func (s *Server) scheduleEvent(delay time.Duration, produce func(ts time.Time) event.Event) {
+	s.timers.mx.Lock()
+	defer s.timers.mx.Unlock()
+
+	if s.timers.stopped {
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		s.timers.mx.Lock()
+		if s.timers.stopped {
+			s.timers.mx.Unlock()
+			return
+		}
+		delete(s.timers.pending, timer)
+		s.timers.inflight.Add(1)
+		s.timers.mx.Unlock()
+
+		// don't hold the lock while emitting, since the event loop might be
+		// scheduling another event at the same time
+		defer s.timers.inflight.Done()
+		s.events <- produce(time.Now()) // emit event
+	})
+	s.timers.pending[timer] = struct{}{}
+}
+
+// stopTimers cancels all the pending timed events (and waits for the ones
+// that are being emitted at the moment).
+func (s *Server) stopTimers() {
+	s.timers.mx.Lock()
+	s.timers.stopped = true
+	for timer := range s.timers.pending {
+		timer.Stop()
+	}
+	s.timers.pending = nil
+	s.timers.mx.Unlock()
+
+	s.timers.inflight.Wait()
+}
@@ -0,0 +1,91 @@
+package bridge
+
+import (
+	"context"
+	"time"
+
+	"github.com/flashbots/vpnham/logutils"
+	"github.com/flashbots/vpnham/monitor"
+	"github.com/flashbots/vpnham/types"
+	"go.uber.org/zap"
+)
+
+const (
+	farEndStaleProbes = 3 // probe intervals without the report
+)
+
+// evaluateFarEnds refreshes the states of the bridges at the far ends of the
+// tunnels (as reported in their probe responses) and, if the tunnels towards
+// the active far end are preferred, re-evaluates the tunnel preemption once
+// any of them changes.  The far end of the tunnel that is down (or that was
+// not reported for a few probe intervals) is marked as stale.
+func (s *Server) evaluateFarEnds(ctx context.Context, ts time.Time, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+
+	changed := false
+	for ifsName, ifs := range s.status.Interfaces {
+		farEnd := s.peers[ifsName].FarEnd()
+		if farEnd == nil {
+			continue
+		}
+
+		staleAfter := farEndStaleProbes * max(s.cfg.ProbeInterval, s.cfg.TunnelInterfaces[ifsName].ProbeInterval)
+		farEnd.Stale = !ifs.Up || ts.Sub(farEnd.UpdatedAt) > staleAfter
+
+		if ifs.FarEnd == nil ||
+			ifs.FarEnd.Active != farEnd.Active ||
+			ifs.FarEnd.Location != farEnd.Location ||
+			ifs.FarEnd.Stale != farEnd.Stale {
+			l.Info("Far end bridge state changed",
+				zap.String("tunnel_interface", ifsName),
+				zap.String("far_end_location", farEnd.Location),
+				zap.Bool("far_end_active", farEnd.Active),
+				zap.String("far_end_active_interface", farEnd.ActiveInterface),
+				zap.Bool("far_end_stale", farEnd.Stale),
+				zap.Time("far_end_updated_at", farEnd.UpdatedAt),
+			)
+			changed = true
+		}
+		ifs.FarEnd = farEnd
+	}
+
+	if !changed || !s.selector.PreferActiveFarEnd {
+		return
+	}
+
+	for ifsName, ifs := range s.status.Interfaces {
+		if ifs.Up && !ifs.Active {
+			s.preemptTunnelInterface(ctx, ifsName, ts)
+		}
+	}
+}
+
+// topology puts together the current view of the bridges on both sides.
+//
+// Must be called while holding the status lock.
+func (s *Server) topology() *types.Topology {
+	s.mxPartnerStatus.Lock()
+	defer s.mxPartnerStatus.Unlock()
+
+	return types.NewTopology(s.status, s.partnerStatus, s.partnerMonitorStatus() == monitor.Up)
+}
+
+// refreshProbeBridge updates our bridge state that is reported in the probe
+// responses.
+func (s *Server) refreshProbeBridge() {
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+
+	activeInterface := s.status.ActiveInterface()
+	if len(activeInterface) > 16 {
+		activeInterface = "" // does not fit into the probe
+	}
+
+	s.probeBridge.Store(&types.ProbeBridge{
+		Active:          s.status.Active,
+		ActiveInterface: activeInterface,
+	})
+}
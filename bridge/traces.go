@@ -0,0 +1,79 @@
+package bridge
+
+import (
+	"context"
+	"sync"
+
+	"github.com/flashbots/vpnham/metrics"
+	"github.com/flashbots/vpnham/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traces keeps the root spans of the failovers open until the event loop is
+// done with the events that carry them (and with the ones derived from those).
+type traces struct {
+	mx      sync.Mutex
+	pending map[trace.SpanID]trace.Span
+	handled []trace.Span
+}
+
+// startRootSpan starts a new trace for the failover that the event (carrying
+// the returned span context) is about to kick off.
+func (s *Server) startRootSpan(ctx context.Context, name, ifsName string) trace.SpanContext {
+	attrs := []attribute.KeyValue{
+		attribute.String(metrics.LabelBridge, s.cfg.Name),
+	}
+	if ifsName != "" {
+		attrs = append(attrs, attribute.String(metrics.LabelTunnel, ifsName))
+	}
+
+	_, span := tracing.Start(ctx, name, trace.WithNewRoot(), trace.WithAttributes(attrs...))
+	if !span.IsRecording() {
+		return span.SpanContext()
+	}
+
+	s.traces.mx.Lock()
+	defer s.traces.mx.Unlock()
+
+	if s.traces.pending == nil {
+		s.traces.pending = make(map[trace.SpanID]trace.Span)
+	}
+	s.traces.pending[span.SpanContext().SpanID()] = span
+
+	return span.SpanContext()
+}
+
+// rootSpanHandled marks the root span as the one to end once the event loop
+// drains the events derived from it.
+func (s *Server) rootSpanHandled(sc trace.SpanContext) {
+	s.traces.mx.Lock()
+	defer s.traces.mx.Unlock()
+
+	span, ok := s.traces.pending[sc.SpanID()]
+	if !ok {
+		return
+	}
+	delete(s.traces.pending, sc.SpanID())
+	s.traces.handled = append(s.traces.handled, span)
+}
+
+// endRootSpans ends the root spans of the handled events (or all of them,
+// when the event loop is stopping).
+func (s *Server) endRootSpans(all bool) {
+	s.traces.mx.Lock()
+	defer s.traces.mx.Unlock()
+
+	for _, span := range s.traces.handled {
+		span.End()
+	}
+	s.traces.handled = nil
+
+	if !all {
+		return
+	}
+	for _, span := range s.traces.pending {
+		span.End()
+	}
+	s.traces.pending = nil
+}
@@ -0,0 +1,171 @@
+package bridge
+
+import (
+	"context"
+	"time"
+
+	"github.com/flashbots/vpnham/event"
+	"github.com/flashbots/vpnham/logutils"
+	"github.com/flashbots/vpnham/metrics"
+	"github.com/flashbots/vpnham/watcher"
+	"go.opentelemetry.io/otel/attribute"
+	otelapi "go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+// newWatchers sets up the watchers of the link state, of the wireguard
+// handshakes, and of the ipsec sa state of the tunnel interfaces (if
+// configured).
+func (s *Server) newWatchers() error {
+	links := make([]string, 0, len(s.cfg.TunnelInterfaces))
+	for ifsName, ifs := range s.cfg.TunnelInterfaces {
+		if ifs.Watch.Link {
+			links = append(links, ifsName)
+		}
+
+		if ifs.Watch.WireGuard() {
+			w, err := watcher.NewWireGuard(ifsName,
+				ifs.Watch.WireGuardPollInterval,
+				ifs.Watch.WireGuardHandshakeTimeout,
+				s.emitLinkEvent(watcher.SourceWireGuard),
+			)
+			if err != nil {
+				return err
+			}
+			s.watchers.wireguard[ifsName] = w
+		}
+
+		if ifs.IPsec.Enabled() {
+			w, err := watcher.NewIPsec(ifsName,
+				ifs.IPsec.Socket,
+				ifs.IPsec.Connection,
+				ifs.IPsec.Child,
+				ifs.IPsec.Initiate,
+				ifs.IPsec.PollInterval,
+				s.emitLinkEvent(watcher.SourceIPsec),
+			)
+			if err != nil {
+				return err
+			}
+			s.watchers.ipsec[ifsName] = w
+		}
+	}
+
+	if len(links) > 0 {
+		w, err := watcher.NewLink(links, s.emitLinkEvent(watcher.SourceLink))
+		if err != nil {
+			return err
+		}
+		s.watchers.link = w
+	}
+
+	return nil
+}
+
+func (s *Server) runWatchers(ctx context.Context, failureSink chan<- error) {
+	if s.watchers.link != nil {
+		s.watchers.link.Run(ctx, failureSink)
+	}
+
+	for _, w := range s.watchers.wireguard {
+		w.Run(ctx, failureSink)
+	}
+
+	for _, w := range s.watchers.ipsec {
+		w.Run(ctx, failureSink)
+	}
+}
+
+func (s *Server) stopWatchers(ctx context.Context) {
+	if s.watchers.link != nil {
+		s.watchers.link.Stop(ctx)
+	}
+
+	for _, w := range s.watchers.wireguard {
+		w.Stop(ctx)
+	}
+
+	for _, w := range s.watchers.ipsec {
+		w.Stop(ctx)
+	}
+}
+
+// initiateIPsec asks strongSwan to (re-)establish the sa of the tunnel
+// interface (if configured to).
+func (s *Server) initiateIPsec(ctx context.Context, ifsName string) {
+	w, ok := s.watchers.ipsec[ifsName]
+	if !ok || !s.cfg.TunnelInterfaces[ifsName].IPsec.Initiate {
+		return
+	}
+
+	timeout := s.cfg.TunnelInterfaces[ifsName].IPsec.InitiateTimeout
+
+	go func() {
+		l := logutils.LoggerFromContext(ctx)
+
+		initiated, err := w.Initiate(ctx, timeout)
+		if err != nil {
+			l.Error("Failed to initiate ipsec sa of the promoted tunnel interface",
+				zap.Error(err),
+				zap.String("tunnel_interface", ifsName),
+			)
+			metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
+				attribute.String(metrics.LabelBridge, s.cfg.Name),
+				attribute.String(metrics.LabelErrorScope, metrics.ScopeIPsec),
+			))
+			return
+		}
+		if !initiated {
+			return
+		}
+
+		l.Info("Initiated ipsec sa of the promoted tunnel interface",
+			zap.String("tunnel_interface", ifsName),
+		)
+	}()
+}
+
+func (s *Server) emitLinkEvent(source string) watcher.OnChange {
+	return func(ifsName string, up bool, reason string) {
+		ts := time.Now()
+
+		if up {
+			s.events <- &event.TunnelInterfaceLinkUp{ // emit event
+				TunnelInterface: ifsName,
+				Timestamp:       ts,
+				Source:          source,
+			}
+			return
+		}
+
+		s.events <- &event.TunnelInterfaceLinkDown{ // emit event
+			TunnelInterface: ifsName,
+			Timestamp:       ts,
+			Source:          source,
+			Reason:          reason,
+		}
+	}
+}
+
+// registerLinkState accounts for the link state reported by the source, and
+// returns true if the tunnel interface's link is down according to any of the
+// sources.
+//
+// Must be called while holding the status lock.
+func (s *Server) registerLinkState(ifsName, source string, up bool) bool {
+	sources, ok := s.watchers.down[ifsName]
+	if !ok {
+		sources = make(map[string]struct{}, 2)
+		s.watchers.down[ifsName] = sources
+	}
+
+	if up {
+		delete(sources, source)
+	} else {
+		sources[source] = struct{}{}
+	}
+
+	linkDown := len(sources) > 0
+	s.status.Interfaces[ifsName].LinkDown = linkDown
+	return linkDown
+}
@@ -0,0 +1,126 @@
+package bridge
+
+import (
+	"context"
+	"time"
+
+	"github.com/flashbots/vpnham/event"
+	"github.com/flashbots/vpnham/logutils"
+	"github.com/flashbots/vpnham/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	otelapi "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	callWitnessReport  = "witness_report"
+	callWitnessVerdict = "witness_verdict"
+)
+
+// consultWitness reports to the witness that we are alive (renewing the
+// active lease if we are active), or retries the self-promotion that the
+// witness did not allow earlier.  While the bridge is down there's nothing to
+// retry (the promotion is re-triggered once it goes up).
+func (s *Server) consultWitness(ctx context.Context, ts time.Time, _ chan<- error) {
+	if s.witness == nil {
+		return
+	}
+
+	s.mxStatus.Lock()
+	defer s.mxStatus.Unlock()
+	s.mxPartnerStatus.Lock()
+	defer s.mxPartnerStatus.Unlock()
+
+	if s.status.Up && !s.status.Active && s.partnerStatus != nil && !s.partnerStatus.Up {
+		s.promoteSelf(ctx, ts)
+		return
+	}
+
+	active := s.status.Active
+	s.callOut(ctx, callWitnessReport, func(ctx context.Context) event.Event {
+		l := logutils.LoggerFromContext(ctx)
+
+		if _, err := s.witness.Request(ctx, active); err != nil {
+			l.Warn("Failed to report to the witness",
+				zap.Error(err),
+			)
+			metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
+				attribute.String(metrics.LabelBridge, s.cfg.Name),
+				attribute.String(metrics.LabelErrorScope, metrics.ScopeWitness),
+			))
+		}
+		return nil
+	})
+}
+
+// promoteSelf activates the bridge after its partner went down (if the
+// witness or the lease, when configured, allows it).  With the witness, the
+// promotion resumes once its verdict arrives.
+//
+// Must be called while holding both status locks.
+func (s *Server) promoteSelf(ctx context.Context, ts time.Time) {
+	if s.status.Active {
+		return
+	}
+
+	if s.witness != nil {
+		s.requestWitnessVerdict(ctx)
+		return
+	}
+
+	s.activateSelf(ctx, ts)
+}
+
+// activateSelf activates the bridge (if the lease, when configured, is held).
+// Otherwise the lease is acquired in the background, and the activation
+// resumes once it is.
+//
+// Must be called while holding the status lock.
+func (s *Server) activateSelf(ctx context.Context, ts time.Time) {
+	if s.lease != nil && !s.lease.Held() {
+		s.acquireLease(ctx)
+		return
+	}
+
+	s.status.Active = true
+	s.status.ActiveSince = ts
+	s.status.Preempting = false
+	s.emit(&event.BridgeActivated{ // emit event
+		BridgeInterface: s.cfg.BridgeInterface,
+		BridgePeerCIDRs: s.cfg.BridgePeerCIDRs(),
+		Timestamp:       ts,
+		SpanContext:     trace.SpanContextFromContext(ctx),
+	})
+}
+
+// requestWitnessVerdict asks the witness for the active lease in the
+// background.  The promotion is allowed if we got the lease, or if the
+// witness does not see our partner either.  If the witness is unreachable, we
+// are the ones cut off (as far as we can tell), and there is no verdict (the
+// promotion is held off until the next attempt).
+func (s *Server) requestWitnessVerdict(ctx context.Context) {
+	s.callOut(ctx, callWitnessVerdict, func(ctx context.Context) event.Event {
+		l := logutils.LoggerFromContext(ctx)
+
+		res, err := s.witness.Request(ctx, true)
+		if err != nil {
+			l.Warn("Witness is unreachable; holding off the self-promotion",
+				zap.Error(err),
+			)
+			metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
+				attribute.String(metrics.LabelBridge, s.cfg.Name),
+				attribute.String(metrics.LabelErrorScope, metrics.ScopeWitness),
+			))
+			return nil
+		}
+
+		return &event.WitnessVerdict{
+			Allowed:     res.Granted || !res.PartnerSeen,
+			Holder:      res.Holder,
+			Expires:     res.Expires,
+			Timestamp:   time.Now(),
+			SpanContext: trace.SpanContextFromContext(ctx),
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/journal"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	errHistoryFailedToFetch = errors.New("failed to fetch history")
+	errHistoryNoSources     = errors.New("no history sources provided")
+)
+
+func CommandHistory(_ *config.Config) *cli.Command {
+	sources := &cli.StringSlice{}
+	timeout := time.Duration(0)
+
+	historyFlags := []cli.Flag{
+		&cli.StringSliceFlag{
+			Destination: sources,
+			Name:        "source",
+			Usage:       "bridge status `url` (its /history endpoint is queried), or a path to the history jsonl-file (can be repeated)",
+		},
+
+		&cli.DurationFlag{
+			Destination: &timeout,
+			Name:        "timeout",
+			Usage:       "timeout for fetching the history from the bridge",
+			Value:       10 * time.Second,
+		},
+	}
+
+	return &cli.Command{
+		Name:  "history",
+		Usage: "merge event histories from several bridges into one timeline",
+		Flags: historyFlags,
+
+		Action: func(_ *cli.Context) error {
+			if len(sources.Value()) == 0 {
+				return errHistoryNoSources
+			}
+
+			cli := &http.Client{
+				Timeout: timeout,
+			}
+
+			histories := make([][]*journal.Entry, 0, len(sources.Value()))
+			for _, source := range sources.Value() {
+				history, err := readHistory(cli, source)
+				if err != nil {
+					return fmt.Errorf("%s: %w", source, err)
+				}
+				histories = append(histories, history)
+			}
+
+			enc := json.NewEncoder(os.Stdout)
+			for _, entry := range journal.Merge(histories...) {
+				if err := enc.Encode(entry); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func readHistory(cli *http.Client, source string) ([]*journal.Entry, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return journal.ReadJSONL(f)
+	}
+
+	_url, err := url.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	if _url.Path == "" || _url.Path == "/" {
+		_url = _url.JoinPath("history")
+	}
+
+	res, err := cli.Get(_url.String())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s",
+			errHistoryFailedToFetch, res.Status,
+		)
+	}
+
+	history := make([]*journal.Entry, 0)
+	if err := json.NewDecoder(res.Body).Decode(&history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
@@ -44,6 +44,9 @@ func main() {
 
 	commands := []*cli.Command{
 		CommandServe(cfg),
+		CommandHistory(cfg),
+		CommandTopology(cfg),
+		CommandWitness(cfg),
 		CommandHelp(cfg),
 	}
 
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/tlsutils"
+	"github.com/flashbots/vpnham/topology"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	errTopologyFailedToFetch   = errors.New("failed to fetch topology")
+	errTopologyFormatIsInvalid = errors.New("invalid topology format (expected: json, dot, or mermaid)")
+	errTopologyNoSource        = errors.New("no bridge status url provided")
+	errTopologySourceIsInvalid = errors.New("invalid bridge status url (expected the path to be empty, or to end with /status or /topology)")
+)
+
+func CommandTopology(_ *config.Config) *cli.Command {
+	source := ""
+	format := ""
+	timeout := time.Duration(0)
+
+	tlsCA, tlsCert, tlsKey, tlsServerName := "", "", "", ""
+
+	topologyFlags := []cli.Flag{
+		&cli.StringFlag{
+			Destination: &source,
+			Name:        "source",
+			Usage:       "bridge status `url` (its /topology endpoint is queried)",
+		},
+
+		&cli.StringFlag{
+			Destination: &format,
+			Name:        "format",
+			Usage:       "output `format` (json, dot, or mermaid)",
+			Value:       topology.FormatJSON,
+		},
+
+		&cli.DurationFlag{
+			Destination: &timeout,
+			Name:        "timeout",
+			Usage:       "timeout for fetching the topology from the bridge",
+			Value:       10 * time.Second,
+		},
+
+		&cli.StringFlag{
+			Destination: &tlsCA,
+			Name:        "tls-ca",
+			Usage:       "`path` to the ca certificate of the bridge's status_tls (if configured)",
+		},
+
+		&cli.StringFlag{
+			Destination: &tlsCert,
+			Name:        "tls-cert",
+			Usage:       "`path` to the client certificate signed by the same ca",
+		},
+
+		&cli.StringFlag{
+			Destination: &tlsKey,
+			Name:        "tls-key",
+			Usage:       "`path` to the client certificate's key",
+		},
+
+		&cli.StringFlag{
+			Destination: &tlsServerName,
+			Name:        "tls-server-name",
+			Usage:       "expected `name` in the bridge's certificate (defaults to the url host)",
+		},
+	}
+
+	return &cli.Command{
+		Name:  "topology",
+		Usage: "fetch and print the view of the bridges on both sides of the tunnels",
+		Flags: topologyFlags,
+
+		Before: func(_ *cli.Context) error {
+			if source == "" {
+				return errTopologyNoSource
+			}
+			switch format {
+			case topology.FormatDOT, topology.FormatJSON, topology.FormatMermaid:
+				return nil
+			default:
+				return fmt.Errorf("%w: %s", errTopologyFormatIsInvalid, format)
+			}
+		},
+
+		Action: func(_ *cli.Context) error {
+			_url, err := topologyURL(source)
+			if err != nil {
+				return err
+			}
+			_url.RawQuery = url.Values{"format": {format}}.Encode()
+
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			if tlsCA != "" {
+				tls, err := tlsutils.NewReloader(tlsCA, tlsCert, tlsKey, 0)
+				if err != nil {
+					return err
+				}
+				serverName := tlsServerName
+				if serverName == "" {
+					serverName = _url.Hostname()
+				}
+				transport.TLSClientConfig = tls.ClientConfig(serverName)
+			}
+
+			cli := &http.Client{
+				Timeout:   timeout,
+				Transport: transport,
+			}
+
+			res, err := cli.Get(_url.String())
+			if err != nil {
+				return err
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != http.StatusOK {
+				return fmt.Errorf("%w: %s",
+					errTopologyFailedToFetch, res.Status,
+				)
+			}
+
+			_, err = io.Copy(os.Stdout, res.Body)
+			return err
+		},
+	}
+}
+
+// topologyURL points the bridge status url to the topology endpoint (next to
+// the status one).
+func topologyURL(source string) (*url.URL, error) {
+	_url, err := url.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p := strings.TrimSuffix(_url.Path, "/"); {
+	case p == "":
+		_url.Path = "/topology"
+	case path.Base(p) == "status" || path.Base(p) == "topology":
+		_url.Path = path.Join(path.Dir(p), "topology")
+	default:
+		return nil, fmt.Errorf("%w: %s",
+			errTopologySourceIsInvalid, source,
+		)
+	}
+	_url.RawPath = ""
+
+	return _url, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/logutils"
+	"github.com/flashbots/vpnham/types"
+	"github.com/flashbots/vpnham/witness"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+var (
+	errWitnessLeaseDurationIsInvalid = errors.New("invalid witness lease duration")
+	errWitnessListenAddrIsInvalid    = errors.New("invalid witness listen addr")
+)
+
+func CommandWitness(_ *config.Config) *cli.Command {
+	listenAddr := ""
+	leaseDuration := time.Duration(0)
+
+	witnessFlags := []cli.Flag{
+		&cli.StringFlag{
+			Destination: &listenAddr,
+			EnvVars:     []string{envPrefix + "WITNESS_LISTEN_ADDR"},
+			Name:        "listen-addr",
+			Usage:       "`address` where the bridges reach the witness",
+			Value:       config.DefaultWitnessListenAddr,
+		},
+
+		&cli.DurationFlag{
+			Destination: &leaseDuration,
+			EnvVars:     []string{envPrefix + "WITNESS_LEASE_DURATION"},
+			Name:        "lease-duration",
+			Usage:       "for how long the active lease is valid unless renewed (must be well above the bridges' probe interval)",
+			Value:       config.DefaultWitnessLeaseDuration,
+		},
+	}
+
+	return &cli.Command{
+		Name:  "witness",
+		Usage: "run the witness that arbitrates between the partner bridges",
+		Flags: witnessFlags,
+
+		Before: func(_ *cli.Context) error {
+			if err := types.Address(listenAddr).Validate(); err != nil {
+				return fmt.Errorf("%w: %w", errWitnessListenAddrIsInvalid, err)
+			}
+			if leaseDuration <= 0 {
+				return fmt.Errorf("%w: %s", errWitnessLeaseDurationIsInvalid, leaseDuration)
+			}
+			return nil
+		},
+
+		Action: func(_ *cli.Context) error {
+			l := zap.L()
+			ctx := logutils.ContextWithLogger(context.Background(), l)
+
+			s := witness.NewServer(ctx, listenAddr, leaseDuration)
+
+			failureSink := make(chan error, 1)
+			s.Run(ctx, failureSink)
+
+			terminator := make(chan os.Signal, 1)
+			signal.Notify(terminator, os.Interrupt, syscall.SIGTERM)
+
+			var err error
+			select {
+			case stop := <-terminator:
+				l.Info("Stop signal received; shutting down...",
+					zap.String("signal", stop.String()),
+				)
+			case err = <-failureSink:
+				l.Error("Internal failure; shutting down...",
+					zap.Error(err),
+				)
+			}
+
+			s.Stop(ctx)
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type BFD struct {
+	DesiredMinTxInterval  time.Duration `yaml:"desired_min_tx_interval"`
+	RequiredMinRxInterval time.Duration `yaml:"required_min_rx_interval"`
+	DetectMultiplier      int           `yaml:"detect_multiplier"`
+}
+
+var (
+	errBFDDetectMultiplierIsInvalid = errors.New("bfd detect multiplier is invalid")
+	errBFDIntervalIsInvalid         = errors.New("bfd interval is invalid")
+)
+
+func (b *BFD) PostLoad(ctx context.Context) error {
+	if b.DesiredMinTxInterval == 0 {
+		b.DesiredMinTxInterval = DefaultBFDInterval
+	}
+
+	if b.RequiredMinRxInterval == 0 {
+		b.RequiredMinRxInterval = DefaultBFDInterval
+	}
+
+	if b.DetectMultiplier == 0 {
+		b.DetectMultiplier = DefaultBFDDetectMultiplier
+	}
+
+	return nil
+}
+
+func (b *BFD) Validate(ctx context.Context) error {
+	if b.DesiredMinTxInterval < time.Microsecond || b.DesiredMinTxInterval > time.Hour {
+		return fmt.Errorf("%w: desired min tx: %s",
+			errBFDIntervalIsInvalid, b.DesiredMinTxInterval,
+		)
+	}
+
+	if b.RequiredMinRxInterval < time.Microsecond || b.RequiredMinRxInterval > time.Hour {
+		return fmt.Errorf("%w: required min rx: %s",
+			errBFDIntervalIsInvalid, b.RequiredMinRxInterval,
+		)
+	}
+
+	if b.DetectMultiplier < 1 || b.DetectMultiplier > 255 {
+		return fmt.Errorf("%w: expected 1 <= N <= 255, got %d",
+			errBFDDetectMultiplierIsInvalid, b.DetectMultiplier,
+		)
+	}
+
+	return nil
+}
@@ -7,7 +7,6 @@ import (
 	"net/url"
 	"time"
 
-	"github.com/flashbots/vpnham/monitor"
 	"github.com/flashbots/vpnham/types"
 	"github.com/flashbots/vpnham/utils"
 	"github.com/google/uuid"
@@ -19,39 +18,61 @@ type Bridge struct {
 
 	Role types.Role `yaml:"role"`
 
+	PreemptDelay      time.Duration `yaml:"preempt_delay"`
+	DisablePreemption bool          `yaml:"disable_preemption"`
+
 	BridgeInterface string       `yaml:"bridge_interface"`
 	PeerCIDR        types.CIDR   `yaml:"peer_cidr"`
 	ExtraPeerCIDRs  []types.CIDR `yaml:"extra_peer_cidrs"`
 
 	SecondaryInterfaces []string `yaml:"secondary_interfaces"`
 
-	StatusAddr                 types.Address `yaml:"status_addr"`
-	PartnerURL                 string        `yaml:"partner_url"`
-	PartnerPollingInterface    string        `yaml:"partner_polling_interface"`
-	PartnerStatusTimeout       time.Duration `yaml:"partner_status_timeout"`
-	PartnerStatusThresholdDown int           `yaml:"partner_status_threshold_down"`
-	PartnerStatusThresholdUp   int           `yaml:"partner_status_threshold_up"`
+	StatusAddr                 types.Address  `yaml:"status_addr"`
+	StatusTLS                  *StatusTLS     `yaml:"status_tls"`
+	StatusSigning              *StatusSigning `yaml:"status_signing"`
+	PartnerURL                 string         `yaml:"partner_url"`
+	PartnerPollingInterface    string         `yaml:"partner_polling_interface"`
+	PartnerStatusTimeout       time.Duration  `yaml:"partner_status_timeout"`
+	PartnerStatusThresholdDown int            `yaml:"partner_status_threshold_down"`
+	PartnerStatusThresholdUp   int            `yaml:"partner_status_threshold_up"`
+	PartnerStatusMonitor       *Monitor       `yaml:"partner_status_monitor"`
+	PartnerPaths               []*PartnerPath `yaml:"partner_paths"`
 
 	ProbeInterval time.Duration  `yaml:"probe_interval"`
 	ProbeLocation types.Location `yaml:"probe_location"`
 
 	TunnelInterfaces map[string]*TunnelInterface `yaml:"tunnel_interfaces"`
+	TunnelSelection  *TunnelSelection            `yaml:"tunnel_selection"`
+
+	Witness *Witness `yaml:"witness"`
+	Lease   *Lease   `yaml:"lease"`
 
 	Reconcile *Reconcile `yaml:"reconcile"`
+
+	History *History `yaml:"history"`
 }
 
 var (
-	errBridgeActiveTunnelInterfacesCountIsInvalid = errors.New("bridge has invalid count of active interfaces configured (must be only 1)")
+	errBridgeActiveTunnelInterfacesCountIsInvalid = errors.New("bridge has invalid count of active interfaces configured (must be at most 1)")
 	errBridgeExtraPeerCIDRIsInvalid               = errors.New("bridge extra peer cidr is invalid")
+	errBridgeHistoryConfigurationIsInvalid        = errors.New("bridge history configuration is invalid")
 	errBridgeInterfaceIsInvalid                   = errors.New("bridge interface is invalid")
+	errBridgeLeaseIsInvalid                       = errors.New("bridge lease configuration is invalid")
+	errBridgePartnerPathsAreInvalid               = errors.New("bridge partner paths are invalid")
 	errBridgePartnerPollingInterfaceIsInvalid     = errors.New("bridge polling interface is invalid")
 	errBridgePartnerStatusThresholdsAreInvalid    = errors.New("bridge partner status thresholds are invalid")
 	errBridgePartnerStatusURLIsInvalid            = errors.New("bridge partner status url is invalid")
+	errBridgePreemptDelayIsInvalid                = errors.New("bridge preempt delay is invalid")
 	errBridgePeerCIDRIsInvalid                    = errors.New("bridge peer cidr is invalid")
 	errBridgeReconcileConfigurationIsInvalid      = errors.New("bridge reconcile configuration is invalid")
 	errBridgeRoleIsInvalid                        = errors.New("bridge role is invalid")
 	errBridgeStatusAddrIsInvalid                  = errors.New("bridge status addr is invalid")
+	errBridgeStatusSigningIsInvalid               = errors.New("bridge status signing configuration is invalid")
+	errBridgeStatusTLSIsInvalid                   = errors.New("bridge status tls configuration is invalid")
 	errBridgeTunnelInterfaceIsInvalid             = errors.New("bridge tunnel interface is invalid")
+	errBridgeTunnelInterfacePrioritiesAreInvalid  = errors.New("bridge tunnel interface with active role must have the highest priority")
+	errBridgeTunnelSelectionIsInvalid             = errors.New("bridge tunnel selection configuration is invalid")
+	errBridgeWitnessIsInvalid                     = errors.New("bridge witness configuration is invalid")
 )
 
 func (b *Bridge) PostLoad(ctx context.Context) error {
@@ -71,15 +92,87 @@ func (b *Bridge) PostLoad(ctx context.Context) error {
 		b.PartnerStatusThresholdUp = DefaultThresholdUp
 	}
 
+	{ // status_tls
+		if b.StatusTLS != nil {
+			if err := b.StatusTLS.PostLoad(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	{ // status_signing
+		if b.StatusSigning != nil {
+			if err := b.StatusSigning.PostLoad(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	{ // partner_status_monitor
+		if b.PartnerStatusMonitor == nil {
+			b.PartnerStatusMonitor = &Monitor{}
+		}
+
+		if err := b.PartnerStatusMonitor.PostLoad(ctx); err != nil {
+			return err
+		}
+	}
+
+	{ // partner_paths
+		for _, p := range b.PartnerPaths {
+			if p == nil {
+				continue
+			}
+			if err := p.PostLoad(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
 	// tunnel_interfaces
 	for ifsName, ifs := range b.TunnelInterfaces {
 		ifs.Name = ifsName
 
+		if ifs.ProbeInterval == 0 {
+			ifs.ProbeInterval = b.ProbeInterval
+		}
+
 		if err := ifs.PostLoad(ctx); err != nil {
 			return err
 		}
 	}
 
+	{ // tunnel_selection
+		if b.TunnelSelection == nil {
+			b.TunnelSelection = &TunnelSelection{}
+		}
+
+		if err := b.TunnelSelection.PostLoad(ctx); err != nil {
+			return err
+		}
+	}
+
+	{ // witness
+		if b.Witness == nil {
+			b.Witness = &Witness{}
+		}
+
+		if err := b.Witness.PostLoad(ctx); err != nil {
+			return err
+		}
+	}
+
+	{ // lease
+		if b.Lease == nil {
+			b.Lease = &Lease{}
+		}
+		b.Lease.BridgeName = b.Name
+
+		if err := b.Lease.PostLoad(ctx); err != nil {
+			return err
+		}
+	}
+
 	{ // reconcile
 		if b.Reconcile == nil {
 			b.Reconcile = &Reconcile{}
@@ -93,6 +186,16 @@ func (b *Bridge) PostLoad(ctx context.Context) error {
 		}
 	}
 
+	{ // history
+		if b.History == nil {
+			b.History = &History{}
+		}
+
+		if err := b.History.PostLoad(ctx); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -139,12 +242,47 @@ func (b *Bridge) Validate(ctx context.Context) error {
 		}
 	}
 
+	{ // status_tls
+		if b.StatusTLS != nil {
+			if err := b.StatusTLS.Validate(ctx); err != nil {
+				return fmt.Errorf("%w: %w",
+					errBridgeStatusTLSIsInvalid, err,
+				)
+			}
+		}
+	}
+
+	{ // status_signing
+		if b.StatusSigning != nil {
+			if err := b.StatusSigning.Validate(ctx); err != nil {
+				return fmt.Errorf("%w: %w",
+					errBridgeStatusSigningIsInvalid, err,
+				)
+			}
+		}
+	}
+
 	{ // partner_url
-		if _, err := url.Parse(b.PartnerURL); err != nil {
+		partnerURL, err := url.Parse(b.PartnerURL)
+		if err != nil {
 			return fmt.Errorf("%w: %w",
 				errBridgePartnerStatusURLIsInvalid, err,
 			)
 		}
+
+		if err := b.validatePartnerURLScheme(partnerURL); err != nil {
+			return fmt.Errorf("%w: %w",
+				errBridgePartnerStatusURLIsInvalid, err,
+			)
+		}
+	}
+
+	{ // partner_paths
+		if err := b.validatePartnerPaths(ctx); err != nil {
+			return fmt.Errorf("%w: %w",
+				errBridgePartnerPathsAreInvalid, err,
+			)
+		}
 	}
 
 	{ // partner_polling_interface
@@ -157,20 +295,34 @@ func (b *Bridge) Validate(ctx context.Context) error {
 		}
 	}
 
-	{ // partner_status_threshold_down, partner_status_threshold_up
-		if _, err := monitor.New(b.PartnerStatusThresholdDown, b.PartnerStatusThresholdUp); err != nil {
+	{ // partner_status_threshold_down, partner_status_threshold_up, partner_status_monitor
+		if err := b.PartnerStatusMonitor.Validate(ctx); err != nil {
+			return fmt.Errorf("%w: %w",
+				errBridgePartnerStatusThresholdsAreInvalid, err,
+			)
+		}
+
+		if _, err := b.PartnerStatusMonitor.New(b.PartnerStatusThresholdDown, b.PartnerStatusThresholdUp); err != nil {
 			return fmt.Errorf("%w: %w",
 				errBridgePartnerStatusThresholdsAreInvalid, err,
 			)
 		}
 	}
 
+	{ // preempt_delay
+		if b.PreemptDelay < 0 {
+			return fmt.Errorf("%w: %s",
+				errBridgePreemptDelayIsInvalid, b.PreemptDelay,
+			)
+		}
+	}
+
 	// probe_interval is validated at un-marshalling
 
 	// probe_location is validated at un-marshalling
 
 	{ // tunnel_interfaces
-		activeInterfacesCount := 0
+		var active *TunnelInterface
 		for ifsName, ifs := range b.TunnelInterfaces {
 			if _, _, err := utils.GetInterfaceIPs(ifsName); err != nil {
 				return fmt.Errorf("%w: %w",
@@ -185,12 +337,55 @@ func (b *Bridge) Validate(ctx context.Context) error {
 			}
 
 			if ifs.Role == types.RoleActive {
-				activeInterfacesCount++
+				if active != nil {
+					return fmt.Errorf("%w: %s, %s",
+						errBridgeActiveTunnelInterfacesCountIsInvalid, active.Name, ifsName,
+					)
+				}
+				active = ifs
+			}
+		}
+		if active != nil {
+			for ifsName, ifs := range b.TunnelInterfaces {
+				if ifsName != active.Name && ifs.Priority >= active.Priority {
+					return fmt.Errorf("%w: %s (%d) vs. %s (%d)",
+						errBridgeTunnelInterfacePrioritiesAreInvalid, active.Name, active.Priority, ifsName, ifs.Priority,
+					)
+				}
 			}
 		}
-		if activeInterfacesCount != 1 {
-			return fmt.Errorf("%w: %d",
-				errBridgeActiveTunnelInterfacesCountIsInvalid, activeInterfacesCount,
+	}
+
+	{ // tunnel_selection
+		if err := b.TunnelSelection.Validate(ctx); err != nil {
+			return fmt.Errorf("%w: %w",
+				errBridgeTunnelSelectionIsInvalid, err,
+			)
+		}
+	}
+
+	{ // witness
+		if err := b.Witness.Validate(ctx); err != nil {
+			return fmt.Errorf("%w: %w",
+				errBridgeWitnessIsInvalid, err,
+			)
+		}
+	}
+
+	{ // lease
+		if err := b.Lease.Validate(ctx); err != nil {
+			return fmt.Errorf("%w: %w",
+				errBridgeLeaseIsInvalid, err,
+			)
+		}
+		if b.Lease.Enabled() && b.Witness.Enabled() {
+			return fmt.Errorf("%w: witness and lease are mutually exclusive",
+				errBridgeLeaseIsInvalid,
+			)
+		}
+		if b.Lease.Enabled() && b.Lease.TTL <= b.ProbeInterval {
+			return fmt.Errorf("%w: ttl (%s) must be longer than the probe interval (%s)",
+				errBridgeLeaseIsInvalid, b.Lease.TTL, b.ProbeInterval,
 			)
 		}
 	}
@@ -203,6 +398,14 @@ func (b *Bridge) Validate(ctx context.Context) error {
 		}
 	}
 
+	{ // history
+		if err := b.History.Validate(ctx); err != nil {
+			return fmt.Errorf("%w: %w",
+				errBridgeHistoryConfigurationIsInvalid, err,
+			)
+		}
+	}
+
 	return nil
 }
 
@@ -210,6 +413,54 @@ func (b *Bridge) TunnelInterfacesCount() int {
 	return len(b.TunnelInterfaces)
 }
 
+// PartnerPathsCount returns the count of all paths over which the partner is
+// polled (including the default one at partner_url).
+func (b *Bridge) PartnerPathsCount() int {
+	return len(b.PartnerPaths) + 1
+}
+
+func (b *Bridge) validatePartnerPaths(ctx context.Context) error {
+	names := make(map[string]struct{}, len(b.PartnerPaths))
+	for idx, p := range b.PartnerPaths {
+		if p == nil {
+			return fmt.Errorf("path #%d is empty", idx)
+		}
+		if err := p.Validate(ctx); err != nil {
+			return err
+		}
+		if _, duplicate := names[p.Name]; duplicate {
+			return fmt.Errorf("%w: duplicate name: %s",
+				errPartnerPathNameIsInvalid, p.Name,
+			)
+		}
+		names[p.Name] = struct{}{}
+
+		partnerURL, _ := url.Parse(p.URL)
+		if err := b.validatePartnerURLScheme(partnerURL); err != nil {
+			return fmt.Errorf("%s: %w: %w",
+				p.Name, errPartnerPathURLIsInvalid, err,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (b *Bridge) validatePartnerURLScheme(partnerURL *url.URL) error {
+	switch {
+	case b.StatusTLS != nil && partnerURL.Scheme != "https":
+		return fmt.Errorf("must be https when status tls is configured: %s",
+			partnerURL,
+		)
+	case b.StatusTLS == nil && partnerURL.Scheme == "https":
+		return fmt.Errorf("status tls must be configured for https: %s",
+			partnerURL,
+		)
+	}
+
+	return nil
+}
+
 func (b *Bridge) BridgePeerCIDRs() []types.CIDR {
 	cidrs := make([]types.CIDR, 0, 1+len(b.ExtraPeerCIDRs))
 	cidrs = append(cidrs, b.PeerCIDR)
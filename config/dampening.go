@@ -0,0 +1,101 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+type Dampening struct {
+	HalfLife        time.Duration `yaml:"half_life"`
+	MaxSuppressTime time.Duration `yaml:"max_suppress_time"`
+
+	Penalty           float64 `yaml:"penalty"`
+	SuppressThreshold float64 `yaml:"suppress_threshold"`
+	ReuseThreshold    float64 `yaml:"reuse_threshold"`
+}
+
+var (
+	errDampeningHalfLifeIsInvalid        = errors.New("invalid dampening half-life")
+	errDampeningMaxSuppressTimeIsInvalid = errors.New("invalid dampening max suppress time")
+	errDampeningPenaltyIsInvalid         = errors.New("invalid dampening penalty")
+	errDampeningThresholdsAreInvalid     = errors.New("invalid dampening thresholds")
+)
+
+func (d *Dampening) PostLoad(ctx context.Context) error {
+	if !d.Enabled() {
+		return nil
+	}
+
+	if d.MaxSuppressTime == 0 {
+		d.MaxSuppressTime = DefaultDampeningMaxSuppressFactor * d.HalfLife
+	}
+
+	if d.Penalty == 0 {
+		d.Penalty = DefaultDampeningPenalty
+	}
+
+	if d.SuppressThreshold == 0 {
+		d.SuppressThreshold = DefaultDampeningSuppressThreshold
+	}
+
+	if d.ReuseThreshold == 0 {
+		d.ReuseThreshold = DefaultDampeningReuseThreshold
+	}
+
+	return nil
+}
+
+func (d *Dampening) Validate(ctx context.Context) error {
+	if d.HalfLife < 0 {
+		return fmt.Errorf("%w: %s",
+			errDampeningHalfLifeIsInvalid, d.HalfLife,
+		)
+	}
+
+	if !d.Enabled() {
+		return nil
+	}
+
+	if d.MaxSuppressTime < d.HalfLife {
+		return fmt.Errorf("%w: expected >= %s, got %s",
+			errDampeningMaxSuppressTimeIsInvalid, d.HalfLife, d.MaxSuppressTime,
+		)
+	}
+
+	if d.Penalty <= 0 {
+		return fmt.Errorf("%w: expected > 0, got %f",
+			errDampeningPenaltyIsInvalid, d.Penalty,
+		)
+	}
+
+	if d.ReuseThreshold <= 0 || d.ReuseThreshold >= d.SuppressThreshold {
+		return fmt.Errorf("%w: expected 0 < reuse < suppress, got reuse %f and suppress %f",
+			errDampeningThresholdsAreInvalid, d.ReuseThreshold, d.SuppressThreshold,
+		)
+	}
+
+	if d.MaxPenalty() < d.SuppressThreshold {
+		return fmt.Errorf("%w: max suppress time is too short for the penalty to reach suppress threshold",
+			errDampeningThresholdsAreInvalid,
+		)
+	}
+
+	return nil
+}
+
+func (d *Dampening) Enabled() bool {
+	if d == nil {
+		return false
+	}
+
+	return d.HalfLife > 0
+}
+
+// MaxPenalty is the ceiling of the penalty (it's such that a tunnel that
+// stopped flapping is never suppressed for longer than max suppress time).
+func (d *Dampening) MaxPenalty() float64 {
+	return d.ReuseThreshold * math.Pow(2, float64(d.MaxSuppressTime)/float64(d.HalfLife))
+}
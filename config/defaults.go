@@ -8,12 +8,27 @@ const (
 	DefaultLogLevel = "info"
 	DefaultLogMode  = "prod"
 
-	DefaultPartnerStatusTimeout = time.Second
-	DefaultProbeInterval        = 15 * time.Second
+	DefaultPartnerStatusTimeout    = time.Second
+	DefaultStatusSigningMaxAge     = 10 * time.Second
+	DefaultStatusTLSReloadInterval = time.Minute
+	DefaultProbeInterval           = 15 * time.Second
+
+	DefaultTunnelInterfacePriorityActive = 100
 
 	DefaultThresholdDown = 5
 	DefaultThresholdUp   = 2
 
+	DefaultBFDDetectMultiplier = 3
+	DefaultBFDInterval         = 300 * time.Millisecond
+
+	DefaultPartnerPathName = "default"
+	DefaultProbeTargetName = "default"
+
+	DefaultMonitorDownAfter     = 8 * time.Second
+	DefaultMonitorLossRatioDown = 0.3
+	DefaultMonitorLossRatioUp   = 0.1
+	DefaultMonitorWindow        = 60
+
 	DefaultAWSTimeout     = 15 * time.Second
 	DefaultGCPTimeout     = 15 * time.Second
 	DefaultScriptsTimeout = 30 * time.Second
@@ -24,8 +39,44 @@ const (
 
 	DefaultMetricsListenAddr = "0.0.0.0:8000"
 
+	DefaultLeaseKeyPrefix = "vpnham/"
+	DefaultLeaseTTL       = 15 * time.Second
+	DefaultLeaseTimeout   = time.Second
+
+	DefaultWitnessLeaseDuration = 30 * time.Second
+	DefaultWitnessListenAddr    = "0.0.0.0:8090"
+	DefaultWitnessTimeout       = time.Second
+
+	DefaultOTLPTimeout         = 10 * time.Second
+	DefaultOTLPMetricsInterval = 30 * time.Second
+	DefaultOTLPSampleRatio     = 1.0
+
 	DefaultLatencyBucketsCount = 33      // from 1us to 1s
 	DefaultMaxLatencyUs        = 1000000 // 1s
 
 	DefaultReapplyFactor = 2.0
+
+	DefaultTunnelSelectionHoldTime      = 30 * time.Second
+	DefaultTunnelSelectionMargin        = 10.0
+	DefaultTunnelSelectionWeightJitter  = 0.5
+	DefaultTunnelSelectionWeightLatency = 0.2
+	DefaultTunnelSelectionWeightLoss    = 1.0
+
+	DefaultDampeningMaxSuppressFactor = 4
+	DefaultDampeningPenalty           = 1000.0
+	DefaultDampeningReuseThreshold    = 750.0
+	DefaultDampeningSuppressThreshold = 2000.0
+
+	DefaultWatchWireGuardPollInterval = 5 * time.Second
+
+	DefaultIPsecInitiateTimeout = 10 * time.Second
+	DefaultIPsecPollInterval    = 5 * time.Second
+	DefaultIPsecSocket          = "/var/run/charon.vici"
+
+	DefaultPMTUMaxMTU  = 1500
+	DefaultPMTUTimeout = time.Second
+
+	DefaultHistorySize           = 1024
+	DefaultHistoryFileMaxSize    = 16 * 1024 * 1024 // 16MiB
+	DefaultHistoryFileMaxBackups = 3
 )
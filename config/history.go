@@ -0,0 +1,71 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+type History struct {
+	Size          int  `yaml:"size"`
+	IncludeProbes bool `yaml:"include_probes"`
+
+	File           string `yaml:"file"`
+	FileMaxSize    int64  `yaml:"file_max_size"`
+	FileMaxBackups *int   `yaml:"file_max_backups"`
+}
+
+var (
+	errHistoryFileIsInvalid           = errors.New("history file is invalid")
+	errHistoryFileMaxBackupsIsInvalid = errors.New("history file max backups count is invalid")
+	errHistoryFileMaxSizeIsInvalid    = errors.New("history file max size is invalid")
+	errHistorySizeIsInvalid           = errors.New("history size is invalid")
+)
+
+func (h *History) PostLoad(ctx context.Context) error {
+	if h.Size == 0 {
+		h.Size = DefaultHistorySize
+	}
+
+	if h.FileMaxSize == 0 {
+		h.FileMaxSize = DefaultHistoryFileMaxSize
+	}
+
+	if h.FileMaxBackups == nil {
+		maxBackups := DefaultHistoryFileMaxBackups
+		h.FileMaxBackups = &maxBackups
+	}
+
+	return nil
+}
+
+func (h *History) Validate(ctx context.Context) error {
+	if h.Size <= 0 {
+		return fmt.Errorf("%w: expected > 0, got %d",
+			errHistorySizeIsInvalid, h.Size,
+		)
+	}
+
+	if h.File != "" {
+		if filepath.Base(h.File) == "." || filepath.Base(h.File) == string(filepath.Separator) {
+			return fmt.Errorf("%w: %s",
+				errHistoryFileIsInvalid, h.File,
+			)
+		}
+
+		if h.FileMaxSize < 1024 {
+			return fmt.Errorf("%w: expected >= 1024, got %d",
+				errHistoryFileMaxSizeIsInvalid, h.FileMaxSize,
+			)
+		}
+
+		if *h.FileMaxBackups < 0 {
+			return fmt.Errorf("%w: expected >= 0, got %d",
+				errHistoryFileMaxBackupsIsInvalid, *h.FileMaxBackups,
+			)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package config_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryFileMaxBackups(t *testing.T) {
+	ctx := context.Background()
+
+	zero, negative := 0, -1
+
+	{ // unset => the default count of backups is kept
+		h := &config.History{File: "/var/log/vpnham/history.jsonl"}
+		require.NoError(t, h.PostLoad(ctx))
+		require.NoError(t, h.Validate(ctx))
+		require.NotNil(t, h.FileMaxBackups)
+		assert.Equal(t, config.DefaultHistoryFileMaxBackups, *h.FileMaxBackups)
+	}
+
+	{ // zero => no backups are kept
+		h := &config.History{File: "/var/log/vpnham/history.jsonl", FileMaxBackups: &zero}
+		require.NoError(t, h.PostLoad(ctx))
+		require.NoError(t, h.Validate(ctx))
+		assert.Equal(t, 0, *h.FileMaxBackups)
+	}
+
+	{ // negative => invalid
+		h := &config.History{File: "/var/log/vpnham/history.jsonl", FileMaxBackups: &negative}
+		require.NoError(t, h.PostLoad(ctx))
+		assert.Error(t, h.Validate(ctx))
+	}
+}
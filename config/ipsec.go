@@ -0,0 +1,80 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// IPsec configures the strongSwan connection (queried over its vici socket)
+// that backs the tunnel interface.
+type IPsec struct {
+	Socket     string `yaml:"socket"`
+	Connection string `yaml:"connection"`
+	Child      string `yaml:"child"`
+
+	PollInterval time.Duration `yaml:"poll_interval"`
+
+	Initiate        bool          `yaml:"initiate"`
+	InitiateTimeout time.Duration `yaml:"initiate_timeout"`
+}
+
+var (
+	errIPsecConnectionIsMissing      = errors.New("ipsec connection is missing")
+	errIPsecInitiateTimeoutIsInvalid = errors.New("invalid ipsec initiate timeout")
+	errIPsecPollIntervalIsInvalid    = errors.New("invalid ipsec poll interval")
+)
+
+func (i *IPsec) PostLoad(ctx context.Context) error {
+	if !i.Enabled() {
+		return nil
+	}
+
+	if i.Socket == "" {
+		i.Socket = DefaultIPsecSocket
+	}
+
+	if i.Child == "" {
+		i.Child = i.Connection
+	}
+
+	if i.PollInterval == 0 {
+		i.PollInterval = DefaultIPsecPollInterval
+	}
+
+	if i.InitiateTimeout == 0 {
+		i.InitiateTimeout = DefaultIPsecInitiateTimeout
+	}
+
+	return nil
+}
+
+func (i *IPsec) Validate(ctx context.Context) error {
+	if !i.Enabled() {
+		if i != nil && (i.Child != "" || i.Initiate) {
+			return errIPsecConnectionIsMissing
+		}
+		return nil
+	}
+
+	if i.PollInterval <= 0 {
+		return fmt.Errorf("%w: %s",
+			errIPsecPollIntervalIsInvalid, i.PollInterval,
+		)
+	}
+
+	if i.InitiateTimeout <= 0 {
+		return fmt.Errorf("%w: %s",
+			errIPsecInitiateTimeoutIsInvalid, i.InitiateTimeout,
+		)
+	}
+
+	return nil
+}
+
+// Enabled returns true if the state of the ipsec connection should be
+// watched for.
+func (i *IPsec) Enabled() bool {
+	return i != nil && i.Connection != ""
+}
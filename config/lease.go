@@ -0,0 +1,101 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Lease configures the consensus store that arbitrates the active role of
+// the bridge (as an alternative to the witness).  Only the holder of the
+// lease may be active.
+type Lease struct {
+	BridgeName string `yaml:"-"`
+
+	Backend   string        `yaml:"backend"`
+	Endpoints []string      `yaml:"endpoints"`
+	Key       string        `yaml:"key"`
+	TTL       time.Duration `yaml:"ttl"`
+	Timeout   time.Duration `yaml:"timeout"`
+}
+
+const (
+	LeaseBackendConsul = "consul"
+	LeaseBackendEtcd   = "etcd"
+)
+
+var (
+	errLeaseBackendIsInvalid    = errors.New("invalid lease backend")
+	errLeaseEndpointsAreInvalid = errors.New("invalid lease endpoints")
+	errLeaseTTLIsInvalid        = errors.New("invalid lease ttl")
+	errLeaseTimeoutIsInvalid    = errors.New("invalid lease timeout")
+)
+
+func (l *Lease) PostLoad(ctx context.Context) error {
+	if !l.Enabled() {
+		return nil
+	}
+
+	if l.Key == "" {
+		l.Key = DefaultLeaseKeyPrefix + l.BridgeName + "/active"
+	}
+
+	if l.TTL == 0 {
+		l.TTL = DefaultLeaseTTL
+	}
+
+	if l.Timeout == 0 {
+		l.Timeout = DefaultLeaseTimeout
+	}
+
+	return nil
+}
+
+func (l *Lease) Validate(ctx context.Context) error {
+	if !l.Enabled() {
+		return nil
+	}
+
+	switch l.Backend {
+	case LeaseBackendConsul:
+		if len(l.Endpoints) > 1 {
+			return fmt.Errorf("%w: %s talks to a single agent (got %d endpoints)",
+				errLeaseEndpointsAreInvalid, l.Backend, len(l.Endpoints),
+			)
+		}
+		if l.TTL < 10*time.Second {
+			return fmt.Errorf("%w: %s sessions require at least 10s (got %s)",
+				errLeaseTTLIsInvalid, l.Backend, l.TTL,
+			)
+		}
+	case LeaseBackendEtcd:
+		if len(l.Endpoints) == 0 {
+			return fmt.Errorf("%w: at least one is required with %s",
+				errLeaseEndpointsAreInvalid, l.Backend,
+			)
+		}
+		if l.TTL < time.Second || l.TTL%time.Second != 0 {
+			return fmt.Errorf("%w: %s leases require whole seconds (got %s)",
+				errLeaseTTLIsInvalid, l.Backend, l.TTL,
+			)
+		}
+	default:
+		return fmt.Errorf("%w: %s",
+			errLeaseBackendIsInvalid, l.Backend,
+		)
+	}
+
+	if l.Timeout <= 0 || l.Timeout >= l.TTL {
+		return fmt.Errorf("%w: %s (must be positive and less than ttl)",
+			errLeaseTimeoutIsInvalid, l.Timeout,
+		)
+	}
+
+	return nil
+}
+
+// Enabled returns true if the lease is configured.
+func (l *Lease) Enabled() bool {
+	return l != nil && l.Backend != ""
+}
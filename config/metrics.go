@@ -4,19 +4,43 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/flashbots/vpnham/types"
 )
 
 type Metrics struct {
-	ListenAddr types.Address `yaml:"listen_addr"`
+	ListenAddr        types.Address `yaml:"listen_addr"`
+	DisablePrometheus bool          `yaml:"disable_prometheus"`
 
 	LatencyBucketsCount int `yaml:"latency_buckets_count"`
 	MaxLatencyUs        int `yaml:"max_latency_us"`
+
+	OTLP *MetricsOTLP `yaml:"otlp"`
+
+	ResourceAttributes map[string]string `yaml:"resource_attributes"`
+
+	BridgeNames    []string `yaml:"-"`
+	ProbeLocations []string `yaml:"-"`
+}
+
+type MetricsOTLP struct {
+	Endpoint string            `yaml:"endpoint"`
+	Protocol string            `yaml:"protocol"`
+	Interval time.Duration     `yaml:"interval"`
+	Timeout  time.Duration     `yaml:"timeout"`
+	Insecure bool              `yaml:"insecure"`
+	Headers  map[string]string `yaml:"headers"`
+
+	TLS *TLS `yaml:"tls"`
 }
 
 var (
-	errMetricsInvalidListenAddr = errors.New("invalid metrics listen addr")
+	errMetricsExportIsDisabled    = errors.New("both prometheus and otlp metrics export are disabled")
+	errMetricsInvalidListenAddr   = errors.New("invalid metrics listen addr")
+	errMetricsOTLPIntervalInvalid = errors.New("invalid metrics otlp export interval")
+	errMetricsOTLPProtocolInvalid = errors.New("invalid metrics otlp protocol (expected: grpc or http)")
+	errMetricsOTLPTLSInvalid      = errors.New("invalid metrics otlp tls configuration")
 )
 
 func (m *Metrics) PostLoad(ctx context.Context) error {
@@ -32,6 +56,14 @@ func (m *Metrics) PostLoad(ctx context.Context) error {
 		m.MaxLatencyUs = DefaultMaxLatencyUs
 	}
 
+	if m.OTLP == nil {
+		m.OTLP = &MetricsOTLP{}
+	}
+
+	if err := m.OTLP.PostLoad(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -41,5 +73,68 @@ func (m *Metrics) Validate(ctx context.Context) error {
 			errMetricsInvalidListenAddr, err,
 		)
 	}
+
+	if m.DisablePrometheus && !m.OTLP.Enabled() {
+		return errMetricsExportIsDisabled
+	}
+
+	if err := m.OTLP.Validate(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *MetricsOTLP) PostLoad(ctx context.Context) error {
+	if o.Protocol == "" {
+		o.Protocol = OTLPProtocolGRPC
+	}
+
+	if o.Interval == 0 {
+		o.Interval = DefaultOTLPMetricsInterval
+	}
+
+	if o.Timeout == 0 {
+		o.Timeout = DefaultOTLPTimeout
+	}
+
+	return nil
+}
+
+func (o *MetricsOTLP) Validate(ctx context.Context) error {
+	if o.Protocol != OTLPProtocolGRPC && o.Protocol != OTLPProtocolHTTP {
+		return fmt.Errorf("%w: %s",
+			errMetricsOTLPProtocolInvalid, o.Protocol,
+		)
+	}
+
+	if o.Interval < 0 {
+		return fmt.Errorf("%w: %s",
+			errMetricsOTLPIntervalInvalid, o.Interval,
+		)
+	}
+
+	if o.TLS != nil {
+		if o.Insecure {
+			return fmt.Errorf("%w: insecure can not be combined with tls",
+				errMetricsOTLPTLSInvalid,
+			)
+		}
+
+		if err := o.TLS.Validate(ctx); err != nil {
+			return fmt.Errorf("%w: %w",
+				errMetricsOTLPTLSInvalid, err,
+			)
+		}
+	}
+
 	return nil
 }
+
+func (o *MetricsOTLP) Enabled() bool {
+	if o == nil {
+		return false
+	}
+
+	return o.Endpoint != ""
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flashbots/vpnham/monitor"
+)
+
+type Monitor struct {
+	Policy string `yaml:"policy"`
+
+	Window        int     `yaml:"window"`
+	LossRatioDown float64 `yaml:"loss_ratio_down"`
+	LossRatioUp   float64 `yaml:"loss_ratio_up"`
+
+	DownAfter time.Duration `yaml:"down_after"`
+	UpAfter   time.Duration `yaml:"up_after"`
+
+	ProbeInterval     time.Duration `yaml:"-"`
+	FastProbeInterval time.Duration `yaml:"-"`
+}
+
+const (
+	MonitorPolicyLossRatio = "loss_ratio"
+	MonitorPolicyStreak    = "streak"
+	MonitorPolicyTime      = "time"
+)
+
+var (
+	errMonitorPolicyIsInvalid = errors.New("monitor policy is invalid (expected: streak, loss_ratio, or time)")
+)
+
+func (m *Monitor) PostLoad(ctx context.Context) error {
+	if m.Policy == "" {
+		m.Policy = MonitorPolicyStreak
+	}
+
+	switch m.Policy {
+	case MonitorPolicyLossRatio:
+		if m.Window == 0 {
+			m.Window = DefaultMonitorWindow
+		}
+		if m.LossRatioDown == 0 {
+			m.LossRatioDown = DefaultMonitorLossRatioDown
+		}
+		if m.LossRatioUp == 0 {
+			m.LossRatioUp = DefaultMonitorLossRatioUp
+		}
+
+	case MonitorPolicyTime:
+		if m.DownAfter == 0 {
+			m.DownAfter = DefaultMonitorDownAfter
+		}
+	}
+
+	return nil
+}
+
+func (m *Monitor) Validate(ctx context.Context) error {
+	switch m.Policy {
+	case MonitorPolicyLossRatio, MonitorPolicyStreak, MonitorPolicyTime:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s",
+		errMonitorPolicyIsInvalid, m.Policy,
+	)
+}
+
+// New returns the monitor according to the configured policy (the thresholds
+// are only used by the streak policy).
+func (m *Monitor) New(thresholdDown, thresholdUp int) (*monitor.Monitor, error) {
+	switch m.Policy {
+	case MonitorPolicyLossRatio:
+		if m.FastProbeInterval > 0 {
+			// fit enough of fast probes into the window (as much as the
+			// monitor allows), but keep it to the same amount of time as if
+			// there were only slow probes
+			factor := int((m.ProbeInterval + m.FastProbeInterval - 1) / m.FastProbeInterval)
+			window := min(factor*m.Window, max(m.Window, monitor.MaxWindow))
+			span := time.Duration(m.Window) * m.ProbeInterval
+			return monitor.NewLossRatio(window, span, m.LossRatioDown, m.LossRatioUp)
+		}
+		return monitor.NewLossRatio(m.Window, 0, m.LossRatioDown, m.LossRatioUp)
+	case MonitorPolicyTime:
+		return monitor.NewTimeBased(m.DownAfter, m.UpAfter)
+	default:
+		return monitor.New(thresholdDown, thresholdUp)
+	}
+}
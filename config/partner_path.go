@@ -0,0 +1,56 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/flashbots/vpnham/utils"
+)
+
+// PartnerPath is the additional path over which the partner's status is
+// polled (e.g. through the out-of-band management network).
+type PartnerPath struct {
+	Name             string `yaml:"name"`
+	URL              string `yaml:"url"`
+	PollingInterface string `yaml:"polling_interface"`
+}
+
+var (
+	errPartnerPathNameIsInvalid             = errors.New("partner path name is invalid")
+	errPartnerPathPollingInterfaceIsInvalid = errors.New("partner path polling interface is invalid")
+	errPartnerPathURLIsInvalid              = errors.New("partner path url is invalid")
+)
+
+func (p *PartnerPath) PostLoad(ctx context.Context) error {
+	if p.Name == "" {
+		p.Name = p.URL
+	}
+
+	return nil
+}
+
+func (p *PartnerPath) Validate(ctx context.Context) error {
+	if p.Name == DefaultPartnerPathName {
+		return fmt.Errorf("%w: %s is reserved for the bridge's partner_url",
+			errPartnerPathNameIsInvalid, p.Name,
+		)
+	}
+
+	if _, err := url.Parse(p.URL); err != nil || p.URL == "" {
+		return fmt.Errorf("%s: %w: %q",
+			p.Name, errPartnerPathURLIsInvalid, p.URL,
+		)
+	}
+
+	if p.PollingInterface != "" {
+		if _, _, err := utils.GetInterfaceIPs(p.PollingInterface); err != nil {
+			return fmt.Errorf("%s: %w: %w",
+				p.Name, errPartnerPathPollingInterfaceIsInvalid, err,
+			)
+		}
+	}
+
+	return nil
+}
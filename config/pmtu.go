@@ -0,0 +1,92 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type PMTU struct {
+	Interval time.Duration `yaml:"interval"`
+	Timeout  time.Duration `yaml:"timeout"`
+
+	MaxMTU int    `yaml:"max_mtu"`
+	MinMTU int    `yaml:"min_mtu"`
+	Action string `yaml:"action"`
+}
+
+const (
+	PMTUActionDegrade = "degrade"
+	PMTUActionDown    = "down"
+)
+
+const (
+	pmtuMaxMTU = 65535
+	pmtuMinMTU = 48 + 142 // ipv6 and udp headers + the probe
+)
+
+var (
+	errPMTUActionIsInvalid   = errors.New("invalid pmtu action")
+	errPMTUIntervalIsInvalid = errors.New("invalid pmtu interval")
+	errPMTUMTUIsInvalid      = errors.New("invalid pmtu mtu")
+)
+
+func (p *PMTU) PostLoad(ctx context.Context) error {
+	if !p.Enabled() {
+		return nil
+	}
+
+	if p.Timeout == 0 {
+		p.Timeout = DefaultPMTUTimeout
+	}
+
+	if p.MaxMTU == 0 {
+		p.MaxMTU = DefaultPMTUMaxMTU
+	}
+
+	if p.Action == "" {
+		p.Action = PMTUActionDegrade
+	}
+
+	return nil
+}
+
+func (p *PMTU) Validate(ctx context.Context) error {
+	if !p.Enabled() {
+		return nil
+	}
+
+	if p.Timeout <= 0 || p.Timeout > p.Interval {
+		return fmt.Errorf("%w: timeout must be 0 < T <= %s, got %s",
+			errPMTUIntervalIsInvalid, p.Interval, p.Timeout,
+		)
+	}
+
+	if p.MaxMTU < pmtuMinMTU || p.MaxMTU > pmtuMaxMTU {
+		return fmt.Errorf("%w: max mtu must be %d <= N <= %d, got %d",
+			errPMTUMTUIsInvalid, pmtuMinMTU, pmtuMaxMTU, p.MaxMTU,
+		)
+	}
+
+	if p.MinMTU < 0 || p.MinMTU > p.MaxMTU {
+		return fmt.Errorf("%w: min mtu must be 0 <= N <= %d, got %d",
+			errPMTUMTUIsInvalid, p.MaxMTU, p.MinMTU,
+		)
+	}
+
+	switch p.Action {
+	case PMTUActionDegrade, PMTUActionDown:
+		// noop
+	default:
+		return fmt.Errorf("%w: %s",
+			errPMTUActionIsInvalid, p.Action,
+		)
+	}
+
+	return nil
+}
+
+func (p *PMTU) Enabled() bool {
+	return p != nil && p.Interval > 0
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flashbots/vpnham/types"
+)
+
+// ProbeTarget is the additional host that is probed through the tunnel (e.g.
+// the one in the remote network behind the tunnel's peer).
+type ProbeTarget struct {
+	Name      string        `yaml:"name"`
+	Addr      types.Address `yaml:"addr"`
+	ProbeType string        `yaml:"probe_type"`
+}
+
+const (
+	ProbeTargetsPolicyAll    = "all"
+	ProbeTargetsPolicyAny    = "any"
+	ProbeTargetsPolicyQuorum = "quorum"
+)
+
+var (
+	errProbeTargetAddrIsInvalid      = errors.New("probe target addr is invalid")
+	errProbeTargetNameIsInvalid      = errors.New("probe target name is invalid")
+	errProbeTargetProbeTypeIsInvalid = errors.New("probe target probe type is invalid")
+)
+
+func (t *ProbeTarget) PostLoad(ctx context.Context) error {
+	if t.Name == "" {
+		t.Name = t.Addr.String()
+	}
+
+	if t.ProbeType == "" {
+		t.ProbeType = TunnelInterfaceProbeTypeICMP
+	}
+
+	return nil
+}
+
+func (t *ProbeTarget) Validate(ctx context.Context) error {
+	if t.Name == DefaultProbeTargetName {
+		return fmt.Errorf("%w: %s is reserved for the tunnel's probe_addr",
+			errProbeTargetNameIsInvalid, t.Name,
+		)
+	}
+
+	if err := t.Addr.Validate(); err != nil {
+		return fmt.Errorf("%s: %w: %w",
+			t.Name, errProbeTargetAddrIsInvalid, err,
+		)
+	}
+
+	switch t.ProbeType {
+	case TunnelInterfaceProbeTypeICMP, TunnelInterfaceProbeTypeTCP:
+		// noop
+	default:
+		return fmt.Errorf("%s: %w: expected %s or %s, got %s",
+			t.Name, errProbeTargetProbeTypeIsInvalid,
+			TunnelInterfaceProbeTypeICMP, TunnelInterfaceProbeTypeTCP, t.ProbeType,
+		)
+	}
+
+	return nil
+}
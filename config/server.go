@@ -3,11 +3,13 @@ package config
 import (
 	"context"
 	"fmt"
+	"slices"
 )
 
 type Server struct {
 	Bridges map[string]*Bridge `yaml:"bridges"`
 	Metrics *Metrics           `yaml:"metrics"`
+	Tracing *Tracing           `yaml:"tracing"`
 }
 
 func (s *Server) PostLoad(ctx context.Context) error {
@@ -23,10 +25,36 @@ func (s *Server) PostLoad(ctx context.Context) error {
 
 	// metrics
 
+	if s.Metrics == nil {
+		s.Metrics = &Metrics{}
+	}
+
+	s.Metrics.BridgeNames = make([]string, 0, len(s.Bridges))
+	s.Metrics.ProbeLocations = make([]string, 0, len(s.Bridges))
+	for bn, b := range s.Bridges {
+		s.Metrics.BridgeNames = append(s.Metrics.BridgeNames, bn)
+		if loc := b.ProbeLocation.String(); loc != "" {
+			s.Metrics.ProbeLocations = append(s.Metrics.ProbeLocations, loc)
+		}
+	}
+	slices.Sort(s.Metrics.BridgeNames)
+	slices.Sort(s.Metrics.ProbeLocations)
+	s.Metrics.ProbeLocations = slices.Compact(s.Metrics.ProbeLocations)
+
 	if err := s.Metrics.PostLoad(ctx); err != nil {
 		return err
 	}
 
+	// tracing
+
+	if s.Tracing == nil {
+		s.Tracing = &Tracing{}
+	}
+
+	if err := s.Tracing.PostLoad(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -43,6 +71,10 @@ func (s *Server) Validate(ctx context.Context) error {
 		return err
 	}
 
+	if err := s.Tracing.Validate(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
 
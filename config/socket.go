@@ -0,0 +1,49 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Socket is the set of options for the socket of the tunnel's transponder.
+type Socket struct {
+	BindToDevice bool `yaml:"bind_to_device"`
+	Mark         int  `yaml:"mark"`
+	DSCP         int  `yaml:"dscp"`
+	TTL          int  `yaml:"ttl"`
+}
+
+var (
+	errSocketDSCPIsInvalid = errors.New("invalid socket dscp")
+	errSocketMarkIsInvalid = errors.New("invalid socket mark")
+	errSocketTTLIsInvalid  = errors.New("invalid socket ttl")
+)
+
+func (s *Socket) Validate(ctx context.Context) error {
+	if s.Mark < 0 || s.Mark > math.MaxUint32 {
+		return fmt.Errorf("%w: expected 0 <= N <= %d, got %d",
+			errSocketMarkIsInvalid, uint32(math.MaxUint32), s.Mark,
+		)
+	}
+
+	if s.DSCP < 0 || s.DSCP > 63 {
+		return fmt.Errorf("%w: expected 0 <= N <= 63, got %d",
+			errSocketDSCPIsInvalid, s.DSCP,
+		)
+	}
+
+	if s.TTL < 0 || s.TTL > 255 {
+		return fmt.Errorf("%w: expected 0 <= N <= 255, got %d",
+			errSocketTTLIsInvalid, s.TTL,
+		)
+	}
+
+	return nil
+}
+
+// IsDefault returns true if none of the socket options are set.
+func (s *Socket) IsDefault() bool {
+	return s == nil || *s == Socket{}
+}
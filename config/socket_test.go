@@ -0,0 +1,47 @@
+package config_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSocketValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		socket config.Socket
+		valid  bool
+	}{
+		"defaults":          {socket: config.Socket{}, valid: true},
+		"all set":           {socket: config.Socket{BindToDevice: true, Mark: 0x100, DSCP: 46, TTL: 1}, valid: true},
+		"max mark":          {socket: config.Socket{Mark: math.MaxUint32}, valid: true},
+		"negative mark":     {socket: config.Socket{Mark: -1}},
+		"mark overflow":     {socket: config.Socket{Mark: math.MaxUint32 + 1}},
+		"max dscp":          {socket: config.Socket{DSCP: 63}, valid: true},
+		"negative dscp":     {socket: config.Socket{DSCP: -1}},
+		"dscp overflow":     {socket: config.Socket{DSCP: 64}},
+		"max ttl":           {socket: config.Socket{TTL: 255}, valid: true},
+		"negative ttl":      {socket: config.Socket{TTL: -1}},
+		"ttl overflow":      {socket: config.Socket{TTL: 256}},
+		"bind to device":    {socket: config.Socket{BindToDevice: true}, valid: true},
+		"only invalid dscp": {socket: config.Socket{Mark: 1, DSCP: 100, TTL: 1}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.socket.Validate(context.Background())
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSocketIsDefault(t *testing.T) {
+	assert.True(t, (*config.Socket)(nil).IsDefault())
+	assert.True(t, (&config.Socket{}).IsDefault())
+	assert.False(t, (&config.Socket{BindToDevice: true}).IsDefault())
+	assert.False(t, (&config.Socket{TTL: 1}).IsDefault())
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StatusSigning configures the signatures of the bridge status documents
+// (served to, and polled from the partner).
+type StatusSigning struct {
+	Algorithm string `yaml:"algorithm"`
+
+	PrivateKey       string `yaml:"private_key"`
+	PartnerPublicKey string `yaml:"partner_public_key"`
+
+	Secret string `yaml:"secret"`
+
+	MaxAge time.Duration `yaml:"max_age"`
+}
+
+const (
+	StatusSigningAlgorithmEd25519    = "ed25519"
+	StatusSigningAlgorithmHMACSHA256 = "hmac-sha256"
+)
+
+var (
+	errStatusSigningAlgorithmIsInvalid = errors.New("invalid status signing algorithm")
+	errStatusSigningKeyIsMissing       = errors.New("status signing key is missing")
+	errStatusSigningMaxAgeIsInvalid    = errors.New("invalid status signing max age")
+)
+
+func (s *StatusSigning) PostLoad(ctx context.Context) error {
+	if s.Algorithm == "" {
+		s.Algorithm = StatusSigningAlgorithmEd25519
+	}
+
+	if s.MaxAge == 0 {
+		s.MaxAge = DefaultStatusSigningMaxAge
+	}
+
+	return nil
+}
+
+func (s *StatusSigning) Validate(ctx context.Context) error {
+	switch s.Algorithm {
+	case StatusSigningAlgorithmEd25519:
+		if s.PrivateKey == "" || s.PartnerPublicKey == "" {
+			return fmt.Errorf("%w: both private_key and partner_public_key are required with %s",
+				errStatusSigningKeyIsMissing, s.Algorithm,
+			)
+		}
+		if s.Secret != "" {
+			return fmt.Errorf("%w: secret is not applicable to %s",
+				errStatusSigningAlgorithmIsInvalid, s.Algorithm,
+			)
+		}
+	case StatusSigningAlgorithmHMACSHA256:
+		if s.Secret == "" {
+			return fmt.Errorf("%w: secret is required with %s",
+				errStatusSigningKeyIsMissing, s.Algorithm,
+			)
+		}
+		if s.PrivateKey != "" || s.PartnerPublicKey != "" {
+			return fmt.Errorf("%w: keys are not applicable to %s",
+				errStatusSigningAlgorithmIsInvalid, s.Algorithm,
+			)
+		}
+	default:
+		return fmt.Errorf("%w: %s",
+			errStatusSigningAlgorithmIsInvalid, s.Algorithm,
+		)
+	}
+
+	if s.MaxAge <= 0 {
+		return fmt.Errorf("%w: %s",
+			errStatusSigningMaxAgeIsInvalid, s.MaxAge,
+		)
+	}
+
+	return nil
+}
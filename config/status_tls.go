@@ -0,0 +1,50 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StatusTLS configures the mutual tls between the partner bridges (for both
+// our status listener and for the polls of the partner's status).
+type StatusTLS struct {
+	CA   string `yaml:"ca"`
+	Cert string `yaml:"cert"`
+	Key  string `yaml:"key"`
+
+	ServerName     string        `yaml:"server_name"`
+	ReloadInterval time.Duration `yaml:"reload_interval"`
+}
+
+var (
+	errStatusTLSCAIsMissing             = errors.New("status tls ca is missing (must be pinned)")
+	errStatusTLSReloadIntervalIsInvalid = errors.New("invalid status tls reload interval")
+)
+
+func (t *StatusTLS) PostLoad(ctx context.Context) error {
+	if t.ReloadInterval == 0 {
+		t.ReloadInterval = DefaultStatusTLSReloadInterval
+	}
+
+	return nil
+}
+
+func (t *StatusTLS) Validate(ctx context.Context) error {
+	if t.CA == "" {
+		return errStatusTLSCAIsMissing
+	}
+
+	if t.Cert == "" || t.Key == "" {
+		return errTLSCertOrKeyIsMissing
+	}
+
+	if t.ReloadInterval <= 0 {
+		return fmt.Errorf("%w: %s",
+			errStatusTLSReloadIntervalIsInvalid, t.ReloadInterval,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"context"
+	"errors"
+)
+
+type TLS struct {
+	CA   string `yaml:"ca"`
+	Cert string `yaml:"cert"`
+	Key  string `yaml:"key"`
+}
+
+var (
+	errTLSCertOrKeyIsMissing = errors.New("tls cert and key must be configured together")
+)
+
+func (t *TLS) Validate(ctx context.Context) error {
+	if (t.Cert == "") != (t.Key == "") {
+		return errTLSCertOrKeyIsMissing
+	}
+
+	return nil
+}
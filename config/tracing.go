@@ -0,0 +1,69 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Tracing struct {
+	Endpoint string            `yaml:"endpoint"`
+	Protocol string            `yaml:"protocol"`
+	Insecure bool              `yaml:"insecure"`
+	Headers  map[string]string `yaml:"headers"`
+	Timeout  time.Duration     `yaml:"timeout"`
+
+	SampleRatio *float64 `yaml:"sample_ratio"`
+}
+
+const (
+	OTLPProtocolGRPC = "grpc"
+	OTLPProtocolHTTP = "http"
+)
+
+var (
+	errTracingProtocolIsInvalid    = errors.New("invalid tracing otlp protocol (expected: grpc or http)")
+	errTracingSampleRatioIsInvalid = errors.New("invalid tracing sample ratio")
+)
+
+func (t *Tracing) PostLoad(ctx context.Context) error {
+	if t.Protocol == "" {
+		t.Protocol = OTLPProtocolGRPC
+	}
+
+	if t.Timeout == 0 {
+		t.Timeout = DefaultOTLPTimeout
+	}
+
+	if t.SampleRatio == nil {
+		ratio := DefaultOTLPSampleRatio
+		t.SampleRatio = &ratio
+	}
+
+	return nil
+}
+
+func (t *Tracing) Validate(ctx context.Context) error {
+	if t.Protocol != OTLPProtocolGRPC && t.Protocol != OTLPProtocolHTTP {
+		return fmt.Errorf("%w: %s",
+			errTracingProtocolIsInvalid, t.Protocol,
+		)
+	}
+
+	if *t.SampleRatio < 0 || *t.SampleRatio > 1 {
+		return fmt.Errorf("%w: expected 0.0 <= R <= 1.0, got %f",
+			errTracingSampleRatioIsInvalid, *t.SampleRatio,
+		)
+	}
+
+	return nil
+}
+
+func (t *Tracing) Enabled() bool {
+	if t == nil {
+		return false
+	}
+
+	return t.Endpoint != ""
+}
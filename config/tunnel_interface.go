@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
-	"github.com/flashbots/vpnham/monitor"
 	"github.com/flashbots/vpnham/types"
 )
 
@@ -13,21 +13,106 @@ type TunnelInterface struct {
 	Name string
 
 	Role      types.Role    `yaml:"role"`
+	Priority  int           `yaml:"priority"`
 	Addr      types.Address `yaml:"addr"`
 	ProbeAddr types.Address `yaml:"probe_addr"`
 
+	ProbeType string `yaml:"probe_type"`
+	BFD       *BFD   `yaml:"bfd"`
+
+	ProbeTargets       []*ProbeTarget `yaml:"probe_targets"`
+	ProbeTargetsPolicy string         `yaml:"probe_targets_policy"`
+	ProbeTargetsQuorum int            `yaml:"probe_targets_quorum"`
+
+	ProbeInterval     time.Duration `yaml:"probe_interval"`
+	FastProbeInterval time.Duration `yaml:"fast_probe_interval"`
+
 	ThresholdDown int `yaml:"threshold_down"`
 	ThresholdUp   int `yaml:"threshold_up"`
+
+	Monitor *Monitor `yaml:"monitor"`
+
+	Dampening *Dampening `yaml:"dampening"`
+
+	PMTU *PMTU `yaml:"pmtu"`
+
+	Socket *Socket `yaml:"socket"`
+
+	Watch *Watch `yaml:"watch"`
+
+	IPsec *IPsec `yaml:"ipsec"`
 }
 
+const (
+	TunnelInterfaceProbeTypeBFD  = "bfd"
+	TunnelInterfaceProbeTypeICMP = "icmp"
+	TunnelInterfaceProbeTypeTCP  = "tcp"
+	TunnelInterfaceProbeTypeUDP  = "udp"
+)
+
 var (
 	errTunnelInterfaceAddrIsInvalid              = errors.New("tunnel interface addr is invalid")
+	errTunnelInterfaceBFDIsInvalid               = errors.New("tunnel interface bfd configuration is invalid")
+	errTunnelInterfaceDampeningIsInvalid         = errors.New("tunnel interface dampening configuration is invalid")
+	errTunnelInterfaceIPsecIsInvalid             = errors.New("tunnel interface ipsec configuration is invalid")
+	errTunnelInterfacePMTUIsInvalid              = errors.New("tunnel interface pmtu configuration is invalid")
 	errTunnelInterfaceProbeAddrIsInvalid         = errors.New("tunnel interface probe addr is invalid")
+	errTunnelInterfaceProbeIntervalIsInvalid     = errors.New("tunnel interface probe interval is invalid")
+	errTunnelInterfaceProbeTargetsAreInvalid     = errors.New("tunnel interface probe targets are invalid")
+	errTunnelInterfaceProbeTypeIsInvalid         = errors.New("tunnel interface probe type is invalid")
 	errTunnelInterfaceRoleIsInvalid              = errors.New("tunnel interface role is invalid")
+	errTunnelInterfaceSocketIsInvalid            = errors.New("tunnel interface socket configuration is invalid")
 	errTunnelInterfaceStatusThresholdsAreInvalid = errors.New("tunnel interface status thresholds are invalid")
+	errTunnelInterfaceWatchIsInvalid             = errors.New("tunnel interface watch configuration is invalid")
 )
 
 func (ifs *TunnelInterface) PostLoad(ctx context.Context) error {
+	if ifs.Role == "" {
+		ifs.Role = types.RoleStandby
+	}
+
+	if ifs.Priority == 0 && ifs.Role == types.RoleActive {
+		ifs.Priority = DefaultTunnelInterfacePriorityActive
+	}
+
+	if ifs.ProbeType == "" {
+		ifs.ProbeType = TunnelInterfaceProbeTypeUDP
+	}
+
+	if ifs.ProbeType == TunnelInterfaceProbeTypeBFD {
+		if ifs.BFD == nil {
+			ifs.BFD = &BFD{}
+		}
+
+		if err := ifs.BFD.PostLoad(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range ifs.ProbeTargets {
+		if t == nil {
+			continue
+		}
+		if err := t.PostLoad(ctx); err != nil {
+			return err
+		}
+	}
+
+	if ifs.ProbeTargetsPolicy == "" {
+		ifs.ProbeTargetsPolicy = ProbeTargetsPolicyAll
+	}
+
+	if ifs.ProbeTargetsQuorum == 0 {
+		switch ifs.ProbeTargetsPolicy {
+		case ProbeTargetsPolicyAll:
+			ifs.ProbeTargetsQuorum = ifs.ProbeTargetsCount()
+		case ProbeTargetsPolicyAny:
+			ifs.ProbeTargetsQuorum = 1
+		case ProbeTargetsPolicyQuorum:
+			ifs.ProbeTargetsQuorum = ifs.ProbeTargetsCount()/2 + 1
+		}
+	}
+
 	if ifs.ThresholdDown == 0 {
 		ifs.ThresholdDown = DefaultThresholdDown
 	}
@@ -36,6 +121,52 @@ func (ifs *TunnelInterface) PostLoad(ctx context.Context) error {
 		ifs.ThresholdUp = DefaultThresholdUp
 	}
 
+	if ifs.Monitor == nil {
+		ifs.Monitor = &Monitor{}
+	}
+	ifs.Monitor.ProbeInterval = ifs.ProbeInterval
+	ifs.Monitor.FastProbeInterval = ifs.FastProbeInterval
+
+	if err := ifs.Monitor.PostLoad(ctx); err != nil {
+		return err
+	}
+
+	if ifs.Dampening == nil {
+		ifs.Dampening = &Dampening{}
+	}
+
+	if err := ifs.Dampening.PostLoad(ctx); err != nil {
+		return err
+	}
+
+	if ifs.PMTU == nil {
+		ifs.PMTU = &PMTU{}
+	}
+
+	if err := ifs.PMTU.PostLoad(ctx); err != nil {
+		return err
+	}
+
+	if ifs.Socket == nil {
+		ifs.Socket = &Socket{}
+	}
+
+	if ifs.Watch == nil {
+		ifs.Watch = &Watch{}
+	}
+
+	if err := ifs.Watch.PostLoad(ctx); err != nil {
+		return err
+	}
+
+	if ifs.IPsec == nil {
+		ifs.IPsec = &IPsec{}
+	}
+
+	if err := ifs.IPsec.PostLoad(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -58,11 +189,143 @@ func (ifs *TunnelInterface) Validate(ctx context.Context) error {
 		)
 	}
 
-	if _, err := monitor.New(ifs.ThresholdDown, ifs.ThresholdUp); err != nil {
+	switch ifs.ProbeType {
+	case TunnelInterfaceProbeTypeICMP, TunnelInterfaceProbeTypeTCP, TunnelInterfaceProbeTypeUDP:
+		// noop
+	case TunnelInterfaceProbeTypeBFD:
+		if err := ifs.BFD.Validate(ctx); err != nil {
+			return fmt.Errorf("%s: %w: %w",
+				ifs.Name, errTunnelInterfaceBFDIsInvalid, err,
+			)
+		}
+	default:
+		return fmt.Errorf("%s: %w: %s",
+			ifs.Name, errTunnelInterfaceProbeTypeIsInvalid, ifs.ProbeType,
+		)
+	}
+
+	if err := ifs.validateProbeTargets(ctx); err != nil {
+		return fmt.Errorf("%s: %w: %w",
+			ifs.Name, errTunnelInterfaceProbeTargetsAreInvalid, err,
+		)
+	}
+
+	if ifs.ProbeInterval <= 0 {
+		return fmt.Errorf("%s: %w: %s",
+			ifs.Name, errTunnelInterfaceProbeIntervalIsInvalid, ifs.ProbeInterval,
+		)
+	}
+
+	if ifs.FastProbeInterval < 0 || ifs.FastProbeInterval >= ifs.ProbeInterval {
+		return fmt.Errorf("%s: %w: fast probe interval must be shorter than %s, got %s",
+			ifs.Name, errTunnelInterfaceProbeIntervalIsInvalid, ifs.ProbeInterval, ifs.FastProbeInterval,
+		)
+	}
+
+	if err := ifs.Monitor.Validate(ctx); err != nil {
+		return fmt.Errorf("%s: %w: %w",
+			ifs.Name, errTunnelInterfaceStatusThresholdsAreInvalid, err,
+		)
+	}
+
+	if _, err := ifs.Monitor.New(ifs.ThresholdDown, ifs.ThresholdUp); err != nil {
 		return fmt.Errorf("%s: %w: %w",
 			ifs.Name, errTunnelInterfaceStatusThresholdsAreInvalid, err,
 		)
 	}
 
+	if err := ifs.Dampening.Validate(ctx); err != nil {
+		return fmt.Errorf("%s: %w: %w",
+			ifs.Name, errTunnelInterfaceDampeningIsInvalid, err,
+		)
+	}
+
+	if err := ifs.PMTU.Validate(ctx); err != nil {
+		return fmt.Errorf("%s: %w: %w",
+			ifs.Name, errTunnelInterfacePMTUIsInvalid, err,
+		)
+	}
+
+	if ifs.PMTU.Enabled() && ifs.ProbeType != TunnelInterfaceProbeTypeUDP {
+		return fmt.Errorf("%s: %w: only applicable to %s probes",
+			ifs.Name, errTunnelInterfacePMTUIsInvalid, TunnelInterfaceProbeTypeUDP,
+		)
+	}
+
+	if err := ifs.Socket.Validate(ctx); err != nil {
+		return fmt.Errorf("%s: %w: %w",
+			ifs.Name, errTunnelInterfaceSocketIsInvalid, err,
+		)
+	}
+
+	if !ifs.Socket.IsDefault() && ifs.ProbeType != TunnelInterfaceProbeTypeUDP {
+		return fmt.Errorf("%s: %w: only applicable to %s probes",
+			ifs.Name, errTunnelInterfaceSocketIsInvalid, TunnelInterfaceProbeTypeUDP,
+		)
+	}
+
+	if err := ifs.Watch.Validate(ctx); err != nil {
+		return fmt.Errorf("%s: %w: %w",
+			ifs.Name, errTunnelInterfaceWatchIsInvalid, err,
+		)
+	}
+
+	if err := ifs.IPsec.Validate(ctx); err != nil {
+		return fmt.Errorf("%s: %w: %w",
+			ifs.Name, errTunnelInterfaceIPsecIsInvalid, err,
+		)
+	}
+
+	return nil
+}
+
+// ProbeTargetsCount returns the count of all probe targets of the tunnel
+// interface (including the default one at its probe_addr).
+func (ifs *TunnelInterface) ProbeTargetsCount() int {
+	return len(ifs.ProbeTargets) + 1
+}
+
+func (ifs *TunnelInterface) validateProbeTargets(ctx context.Context) error {
+	names := make(map[string]struct{}, len(ifs.ProbeTargets))
+	for idx, t := range ifs.ProbeTargets {
+		if t == nil {
+			return fmt.Errorf("target #%d is empty", idx)
+		}
+		if err := t.Validate(ctx); err != nil {
+			return err
+		}
+		if _, duplicate := names[t.Name]; duplicate {
+			return fmt.Errorf("%w: duplicate name: %s",
+				errProbeTargetNameIsInvalid, t.Name,
+			)
+		}
+		names[t.Name] = struct{}{}
+	}
+
+	switch ifs.ProbeTargetsPolicy {
+	case ProbeTargetsPolicyAll:
+		if ifs.ProbeTargetsQuorum != ifs.ProbeTargetsCount() {
+			return fmt.Errorf("quorum is not applicable to %s policy",
+				ifs.ProbeTargetsPolicy,
+			)
+		}
+	case ProbeTargetsPolicyAny:
+		if ifs.ProbeTargetsQuorum != 1 {
+			return fmt.Errorf("quorum is not applicable to %s policy",
+				ifs.ProbeTargetsPolicy,
+			)
+		}
+	case ProbeTargetsPolicyQuorum:
+		// noop
+	default:
+		return fmt.Errorf("unknown policy: %s", ifs.ProbeTargetsPolicy)
+	}
+
+	if ifs.ProbeTargetsQuorum < 1 || ifs.ProbeTargetsQuorum > ifs.ProbeTargetsCount() {
+		return fmt.Errorf("quorum must be 1 <= N <= %d, got %d",
+			ifs.ProbeTargetsCount(), ifs.ProbeTargetsQuorum,
+		)
+	}
+
 	return nil
 }
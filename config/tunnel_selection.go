@@ -0,0 +1,108 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type TunnelSelection struct {
+	Policy   string        `yaml:"policy"`
+	Margin   float64       `yaml:"margin"`
+	HoldTime time.Duration `yaml:"hold_time"`
+
+	PreemptDelay      time.Duration `yaml:"preempt_delay"`
+	DisablePreemption bool          `yaml:"disable_preemption"`
+
+	PreferActiveFarEnd bool `yaml:"prefer_active_far_end"`
+
+	Weights *TunnelSelectionWeights `yaml:"weights"`
+}
+
+type TunnelSelectionWeights struct {
+	Loss    float64 `yaml:"loss"`    // score points per 1% of lost probes
+	Latency float64 `yaml:"latency"` // score points per 1ms of round-trip time
+	Jitter  float64 `yaml:"jitter"`  // score points per 1ms of jitter
+}
+
+const (
+	TunnelSelectionPolicyQuality = "quality"
+	TunnelSelectionPolicyRole    = "role"
+)
+
+var (
+	errTunnelSelectionHoldTimeIsInvalid     = errors.New("tunnel selection hold time is invalid")
+	errTunnelSelectionMarginIsInvalid       = errors.New("tunnel selection margin is invalid")
+	errTunnelSelectionPreemptDelayIsInvalid = errors.New("tunnel selection preempt delay is invalid")
+	errTunnelSelectionPolicyIsInvalid       = errors.New("tunnel selection policy is invalid (expected: role or quality)")
+	errTunnelSelectionWeightIsInvalid       = errors.New("tunnel selection weight is invalid")
+)
+
+func (t *TunnelSelection) PostLoad(ctx context.Context) error {
+	if t.Policy == "" {
+		t.Policy = TunnelSelectionPolicyRole
+	}
+
+	if t.Margin == 0 {
+		t.Margin = DefaultTunnelSelectionMargin
+	}
+
+	if t.HoldTime == 0 {
+		t.HoldTime = DefaultTunnelSelectionHoldTime
+	}
+
+	if t.Weights == nil {
+		t.Weights = &TunnelSelectionWeights{
+			Loss:    DefaultTunnelSelectionWeightLoss,
+			Latency: DefaultTunnelSelectionWeightLatency,
+			Jitter:  DefaultTunnelSelectionWeightJitter,
+		}
+	}
+
+	return nil
+}
+
+func (t *TunnelSelection) Validate(ctx context.Context) error {
+	if t.Policy != TunnelSelectionPolicyRole && t.Policy != TunnelSelectionPolicyQuality {
+		return fmt.Errorf("%w: %s",
+			errTunnelSelectionPolicyIsInvalid, t.Policy,
+		)
+	}
+
+	if t.Margin < 0 || t.Margin > 100 {
+		return fmt.Errorf("%w: expected 0 <= M <= 100, got %f",
+			errTunnelSelectionMarginIsInvalid, t.Margin,
+		)
+	}
+
+	if t.HoldTime < 0 {
+		return fmt.Errorf("%w: %s",
+			errTunnelSelectionHoldTimeIsInvalid, t.HoldTime,
+		)
+	}
+
+	if t.PreemptDelay < 0 {
+		return fmt.Errorf("%w: %s",
+			errTunnelSelectionPreemptDelayIsInvalid, t.PreemptDelay,
+		)
+	}
+
+	for name, weight := range map[string]float64{
+		"loss":    t.Weights.Loss,
+		"latency": t.Weights.Latency,
+		"jitter":  t.Weights.Jitter,
+	} {
+		if weight < 0 {
+			return fmt.Errorf("%w: %s: expected >= 0, got %f",
+				errTunnelSelectionWeightIsInvalid, name, weight,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (t *TunnelSelection) QualityBased() bool {
+	return t.Policy == TunnelSelectionPolicyQuality
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Watch configures how the state of the tunnel interface is watched for (on
+// top of the probes) so that the failures are detected sooner.
+type Watch struct {
+	Link bool `yaml:"link"`
+
+	WireGuardHandshakeTimeout time.Duration `yaml:"wireguard_handshake_timeout"`
+	WireGuardPollInterval     time.Duration `yaml:"wireguard_poll_interval"`
+}
+
+var (
+	errWatchWireGuardIntervalIsInvalid = errors.New("invalid wireguard poll interval")
+	errWatchWireGuardTimeoutIsInvalid  = errors.New("invalid wireguard handshake timeout")
+)
+
+func (w *Watch) PostLoad(ctx context.Context) error {
+	if w.WireGuard() && w.WireGuardPollInterval == 0 {
+		w.WireGuardPollInterval = DefaultWatchWireGuardPollInterval
+	}
+
+	return nil
+}
+
+func (w *Watch) Validate(ctx context.Context) error {
+	if w.WireGuardHandshakeTimeout < 0 {
+		return fmt.Errorf("%w: %s",
+			errWatchWireGuardTimeoutIsInvalid, w.WireGuardHandshakeTimeout,
+		)
+	}
+
+	if !w.WireGuard() {
+		return nil
+	}
+
+	if w.WireGuardPollInterval <= 0 || w.WireGuardPollInterval >= w.WireGuardHandshakeTimeout {
+		return fmt.Errorf("%w: must be shorter than the handshake timeout (%s), got %s",
+			errWatchWireGuardIntervalIsInvalid, w.WireGuardHandshakeTimeout, w.WireGuardPollInterval,
+		)
+	}
+
+	return nil
+}
+
+// WireGuard returns true if the handshakes of the wireguard interface should
+// be watched for.
+func (w *Watch) WireGuard() bool {
+	return w != nil && w.WireGuardHandshakeTimeout > 0
+}
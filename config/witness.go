@@ -0,0 +1,54 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Witness configures the external witness that arbitrates the self-promotion
+// of the bridge when its partner is deemed down.
+type Witness struct {
+	URL     string        `yaml:"url"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+var (
+	errWitnessTimeoutIsInvalid = errors.New("invalid witness timeout")
+	errWitnessURLIsInvalid     = errors.New("invalid witness url")
+)
+
+func (w *Witness) PostLoad(ctx context.Context) error {
+	if w.Enabled() && w.Timeout == 0 {
+		w.Timeout = DefaultWitnessTimeout
+	}
+
+	return nil
+}
+
+func (w *Witness) Validate(ctx context.Context) error {
+	if !w.Enabled() {
+		return nil
+	}
+
+	if _, err := url.Parse(w.URL); err != nil {
+		return fmt.Errorf("%w: %w",
+			errWitnessURLIsInvalid, err,
+		)
+	}
+
+	if w.Timeout <= 0 {
+		return fmt.Errorf("%w: %s",
+			errWitnessTimeoutIsInvalid, w.Timeout,
+		)
+	}
+
+	return nil
+}
+
+// Enabled returns true if the witness is configured.
+func (w *Witness) Enabled() bool {
+	return w != nil && w.URL != ""
+}
@@ -0,0 +1,78 @@
+package dampening
+
+import (
+	"math"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+)
+
+// Dampener tracks the flap penalty of a single tunnel interface.
+//
+// Every flap adds a fixed penalty that then decays exponentially with the
+// configured half-life.  Once the penalty crosses the suppress threshold, the
+// tunnel remains suppressed until the penalty decays below reuse threshold.
+type Dampener struct {
+	cfg *config.Dampening
+
+	penalty    float64
+	suppressed bool
+	updated    time.Time
+}
+
+func New(cfg *config.Dampening) *Dampener {
+	return &Dampener{
+		cfg: cfg,
+	}
+}
+
+// Flap registers a flap at the given moment and returns true if it caused the
+// tunnel to become suppressed.
+func (d *Dampener) Flap(ts time.Time) bool {
+	d.decay(ts)
+
+	d.penalty = min(d.penalty+d.cfg.Penalty, d.cfg.MaxPenalty())
+	if !d.suppressed && d.penalty >= d.cfg.SuppressThreshold {
+		d.suppressed = true
+		return true
+	}
+
+	return false
+}
+
+// Update decays the penalty up to the given moment and returns true if the
+// tunnel got unsuppressed as the result.
+func (d *Dampener) Update(ts time.Time) bool {
+	d.decay(ts)
+
+	if d.suppressed && d.penalty < d.cfg.ReuseThreshold {
+		d.suppressed = false
+		return true
+	}
+
+	return false
+}
+
+// Penalty returns the penalty as of the last flap or update.
+func (d *Dampener) Penalty() float64 {
+	return d.penalty
+}
+
+func (d *Dampener) Suppressed() bool {
+	return d.suppressed
+}
+
+func (d *Dampener) decay(ts time.Time) {
+	if d.updated.IsZero() {
+		d.updated = ts
+		return
+	}
+
+	elapsed := ts.Sub(d.updated)
+	if elapsed <= 0 {
+		return
+	}
+
+	d.penalty *= math.Pow(2, -float64(elapsed)/float64(d.cfg.HalfLife))
+	d.updated = ts
+}
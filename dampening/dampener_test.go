@@ -0,0 +1,54 @@
+package dampening_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/dampening"
+	"github.com/stretchr/testify/assert"
+)
+
+func newConfig() *config.Dampening {
+	return &config.Dampening{
+		HalfLife:          time.Minute,
+		MaxSuppressTime:   4 * time.Minute,
+		Penalty:           1000,
+		SuppressThreshold: 2000,
+		ReuseThreshold:    750,
+	}
+}
+
+func TestDampenerSuppressAndReuse(t *testing.T) {
+	d := dampening.New(newConfig())
+	ts := time.Unix(0, 0)
+
+	assert.False(t, d.Flap(ts))
+	assert.False(t, d.Suppressed())
+
+	assert.True(t, d.Flap(ts))
+	assert.True(t, d.Suppressed())
+	assert.InDelta(t, 2000, d.Penalty(), 0.001)
+
+	assert.False(t, d.Update(ts.Add(time.Minute)))
+	assert.InDelta(t, 1000, d.Penalty(), 0.001)
+	assert.True(t, d.Suppressed())
+
+	assert.True(t, d.Update(ts.Add(2*time.Minute)))
+	assert.InDelta(t, 500, d.Penalty(), 0.001)
+	assert.False(t, d.Suppressed())
+}
+
+func TestDampenerMaxSuppressTime(t *testing.T) {
+	cfg := newConfig()
+	d := dampening.New(cfg)
+	ts := time.Unix(0, 0)
+
+	for range 100 {
+		d.Flap(ts)
+	}
+	assert.InDelta(t, cfg.MaxPenalty(), d.Penalty(), 0.001)
+
+	assert.False(t, d.Update(ts.Add(cfg.MaxSuppressTime-time.Second)))
+	assert.True(t, d.Update(ts.Add(cfg.MaxSuppressTime+time.Second)))
+}
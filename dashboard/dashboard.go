@@ -0,0 +1,21 @@
+package dashboard
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the dashboard:  the static page (with no external
+// dependencies) that keeps on polling the json endpoints of the bridge's
+// status server.
+func Handler() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // the embedded directory is always there
+	}
+	return http.FileServer(http.FS(files))
+}
@@ -0,0 +1,37 @@
+package dashboard_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/flashbots/vpnham/dashboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	srv := httptest.NewServer(http.StripPrefix("/dashboard", dashboard.Handler()))
+	defer srv.Close()
+
+	// no external dependencies (so that it works on isolated hosts)
+	external := regexp.MustCompile(`(src|href)=["']?(https?:)?//|@import|url\(`)
+
+	for path, contentType := range map[string]string{
+		"/dashboard/":              "text/html; charset=utf-8",
+		"/dashboard/dashboard.css": "text/css; charset=utf-8",
+		"/dashboard/dashboard.js":  "text/javascript; charset=utf-8",
+	} {
+		res, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode, path)
+		assert.Equal(t, contentType, res.Header.Get("content-type"), path)
+		assert.False(t, external.Match(body), path)
+	}
+}
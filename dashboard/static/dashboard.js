@@ -0,0 +1,280 @@
+"use strict";
+
+// the dashboard is served at /dashboard/, the json endpoints are one level up
+const base = new URL("..", window.location.href);
+
+const params = new URLSearchParams(window.location.search);
+const refreshInterval = 1000 * (Number(params.get("refresh")) || 5);
+const historyWindow = 60 * 60 * 1000; // 1h
+const historyLimit = 25;
+const samplesLimit = 60;
+
+// kinds of the history entries that are too chatty to be shown
+const chattyKinds = [
+  "partner_poll_failure",
+  "partner_poll_success",
+  "tunnel_probe_return_failure",
+  "tunnel_probe_return_success",
+  "tunnel_probe_send_failure",
+  "tunnel_probe_send_success",
+];
+
+// per tunnel interface samples of the loss ratio and of the rtt
+const samples = {};
+
+async function fetchJSON(path) {
+  const res = await fetch(new URL(path, base), { cache: "no-store" });
+  if (!res.ok) {
+    throw new Error(path + ": " + res.status + " " + res.statusText);
+  }
+  return res.json();
+}
+
+function el(tag, text, className) {
+  const e = document.createElement(tag);
+  if (text !== undefined && text !== null) {
+    e.textContent = String(text);
+  }
+  if (className) {
+    e.className = className;
+  }
+  return e;
+}
+
+function badge(value, known = true) {
+  if (!known) {
+    return el("span", "?", "badge off");
+  }
+  return el("span", value ? "yes" : "no", "badge " + (value ? "yes" : "no"));
+}
+
+function row(tbody, cells) {
+  const tr = el("tr");
+  for (const cell of cells) {
+    const td = el("td");
+    if (cell instanceof Node) {
+      td.appendChild(cell);
+    } else {
+      td.textContent = cell === undefined || cell === null ? "" : String(cell);
+    }
+    tr.appendChild(td);
+  }
+  tbody.appendChild(tr);
+}
+
+function replaceRows(id, fill, empty) {
+  const tbody = document.querySelector("#" + id + " tbody");
+  tbody.replaceChildren();
+  fill(tbody);
+  if (tbody.children.length === 0) {
+    const tr = el("tr");
+    const td = el("td", empty, "muted");
+    td.colSpan = document.querySelectorAll("#" + id + " thead th").length;
+    tr.appendChild(td);
+    tbody.appendChild(tr);
+  }
+}
+
+function since(ts) {
+  if (!ts) {
+    return "";
+  }
+  const seconds = Math.round((Date.now() - new Date(ts).getTime()) / 1000);
+  if (seconds < 60) {
+    return seconds + "s ago";
+  }
+  if (seconds < 3600) {
+    return Math.floor(seconds / 60) + "m ago";
+  }
+  return Math.floor(seconds / 3600) + "h ago";
+}
+
+function time(ts) {
+  return new Date(ts).toLocaleTimeString();
+}
+
+function sparkline(values) {
+  const ns = "http://www.w3.org/2000/svg";
+  const width = 80;
+  const height = 16;
+
+  const svg = document.createElementNS(ns, "svg");
+  svg.setAttribute("class", "sparkline");
+  svg.setAttribute("width", width);
+  svg.setAttribute("height", height);
+
+  if (values.length < 2) {
+    return svg;
+  }
+
+  const max = Math.max(...values) || 1;
+  const step = width / (samplesLimit - 1);
+  const offset = width - step * (values.length - 1);
+  const points = values.map((v, i) =>
+    (offset + i * step).toFixed(1) + "," + (height - 1 - (v / max) * (height - 2)).toFixed(1)
+  );
+
+  const line = document.createElementNS(ns, "polyline");
+  line.setAttribute("points", points.join(" "));
+  svg.appendChild(line);
+
+  return svg;
+}
+
+function withSparkline(text, values) {
+  const span = el("span", text);
+  span.appendChild(sparkline(values));
+  return span;
+}
+
+function recordSamples(status) {
+  for (const [name, ifs] of Object.entries(status.interfaces || {})) {
+    const s = (samples[name] = samples[name] || { loss: [], rtt: [] });
+    s.loss.push(100 * (ifs.loss_ratio || 0));
+    s.rtt.push((ifs.rtt_us || 0) / 1000);
+    if (s.loss.length > samplesLimit) {
+      s.loss.shift();
+      s.rtt.shift();
+    }
+  }
+}
+
+function renderBridges(topology) {
+  replaceRows("bridges", (tbody) => {
+    for (const node of topology.nodes || []) {
+      const local = node.side === "local";
+      row(tbody, [
+        node.location,
+        node.side,
+        node.role || "",
+        badge(node.up, local),
+        badge(node.active, !node.stale),
+        node.active_interface || "",
+      ]);
+    }
+  }, "no bridges known");
+}
+
+function renderTunnels(status) {
+  replaceRows("tunnels", (tbody) => {
+    const names = Object.keys(status.interfaces || {}).sort();
+    for (const name of names) {
+      const ifs = status.interfaces[name];
+      const s = samples[name] || { loss: [], rtt: [] };
+
+      const flags = [];
+      if (ifs.suppressed) flags.push("suppressed");
+      if (ifs.mtu_degraded) flags.push("mtu degraded");
+      if (ifs.link_down) flags.push("link down");
+      if (ifs.fast_probing) flags.push("fast probing");
+
+      let farEnd = "";
+      if (ifs.far_end) {
+        if (ifs.far_end.stale) {
+          farEnd = ifs.far_end.location + " (stale)";
+        } else {
+          farEnd = ifs.far_end.location + (ifs.far_end.active ? " (active)" : "");
+        }
+      }
+
+      row(tbody, [
+        name,
+        badge(ifs.up),
+        badge(ifs.active),
+        ifs.priority,
+        ifs.up ? (ifs.score || 0).toFixed(1) : "",
+        withSparkline((100 * (ifs.loss_ratio || 0)).toFixed(1) + "%", s.loss),
+        withSparkline(((ifs.rtt_us || 0) / 1000).toFixed(2) + "ms", s.rtt),
+        ((ifs.jitter_us || 0) / 1000).toFixed(2) + "ms",
+        flags.join(", "),
+        farEnd,
+      ]);
+    }
+  }, "no tunnels configured");
+}
+
+function renderJobs(jobs) {
+  replaceRows("jobs", (tbody) => {
+    for (const job of jobs || []) {
+      row(tbody, [
+        job.name,
+        since(job.scheduled_at),
+        job.started_at ? since(job.started_at) : "queued",
+      ]);
+    }
+  }, "none");
+}
+
+function renderHistory(entries) {
+  const transitions = (entries || [])
+    .filter((e) => !chattyKinds.includes(e.kind))
+    .slice(-historyLimit)
+    .reverse();
+
+  replaceRows("history", (tbody) => {
+    for (const entry of transitions) {
+      const details = [];
+      if (entry.event && entry.event.TunnelInterface) {
+        details.push(entry.event.TunnelInterface);
+      }
+      if (entry.trigger) {
+        details.push("after " + entry.trigger.kind);
+      }
+      if (entry.job) {
+        details.push(entry.job.name + " in " + (entry.job.duration_us / 1000).toFixed(0) + "ms");
+        if (entry.job.error) {
+          details.push(entry.job.error);
+        }
+      }
+      row(tbody, [time(entry.timestamp), entry.kind, details.join("; ")]);
+    }
+  }, "no transitions in the last hour");
+}
+
+async function refresh() {
+  const errors = [];
+  const settle = async (promise) => {
+    try {
+      return await promise;
+    } catch (err) {
+      errors.push(err.message);
+      return null;
+    }
+  };
+
+  const historySince = new Date(Date.now() - historyWindow).toISOString();
+  const [doc, topology, jobs, history] = await Promise.all([
+    settle(fetchJSON("status")),
+    settle(fetchJSON("topology")),
+    settle(fetchJSON("jobs")),
+    settle(fetchJSON("history?since=" + encodeURIComponent(historySince))),
+  ]);
+
+  if (doc) {
+    // signed status documents carry the status inside
+    const status = doc.signature ? doc.status : doc;
+    document.getElementById("bridge-name").textContent = "vpnham: " + status.name;
+    document.title = "vpnham: " + status.name;
+    recordSamples(status);
+    renderTunnels(status);
+  }
+  if (topology) {
+    renderBridges(topology);
+  }
+  if (jobs) {
+    renderJobs(jobs);
+  }
+  if (history) {
+    renderHistory(history);
+  }
+
+  document.getElementById("updated").textContent = "updated " + new Date().toLocaleTimeString();
+  document.getElementById("error").textContent = errors.join("; ");
+}
+
+async function loop() {
+  await refresh();
+  setTimeout(loop, refreshInterval);
+}
+
+loop();
@@ -4,12 +4,15 @@ import (
 	"time"
 
 	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type BridgeActivated struct {
 	BridgeInterface string
 	BridgePeerCIDRs []types.CIDR
 	Timestamp       time.Time
+
+	SpanContext trace.SpanContext `json:"-"`
 }
 
 func (e *BridgeActivated) EvtKind() string {
@@ -27,3 +30,7 @@ func (e *BridgeActivated) EvtBridgePeerCIDRs() []types.CIDR {
 func (e *BridgeActivated) EvtTimestamp() time.Time {
 	return e.Timestamp
 }
+
+func (e *BridgeActivated) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
@@ -4,12 +4,15 @@ import (
 	"time"
 
 	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type BridgeDeactivated struct {
 	BridgeInterface string
 	BridgePeerCIDRs []types.CIDR
 	Timestamp       time.Time
+
+	SpanContext trace.SpanContext `json:"-"`
 }
 
 func (e *BridgeDeactivated) EvtKind() string {
@@ -27,3 +30,7 @@ func (e *BridgeDeactivated) EvtBridgePeerCIDRs() []types.CIDR {
 func (e *BridgeDeactivated) EvtTimestamp() time.Time {
 	return e.Timestamp
 }
+
+func (e *BridgeDeactivated) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
@@ -0,0 +1,36 @@
+package event
+
+import (
+	"time"
+
+	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type BridgeLeaseAcquired struct {
+	BridgeInterface string
+	BridgePeerCIDRs []types.CIDR
+	Timestamp       time.Time
+
+	SpanContext trace.SpanContext `json:"-"`
+}
+
+func (e *BridgeLeaseAcquired) EvtKind() string {
+	return "bridge_lease_acquired"
+}
+
+func (e *BridgeLeaseAcquired) EvtBridgeInterface() string {
+	return e.BridgeInterface
+}
+
+func (e *BridgeLeaseAcquired) EvtBridgePeerCIDRs() []types.CIDR {
+	return e.BridgePeerCIDRs
+}
+
+func (e *BridgeLeaseAcquired) EvtTimestamp() time.Time {
+	return e.Timestamp
+}
+
+func (e *BridgeLeaseAcquired) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
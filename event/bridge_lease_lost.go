@@ -0,0 +1,36 @@
+package event
+
+import (
+	"time"
+
+	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type BridgeLeaseLost struct {
+	BridgeInterface string
+	BridgePeerCIDRs []types.CIDR
+	Timestamp       time.Time
+
+	SpanContext trace.SpanContext `json:"-"`
+}
+
+func (e *BridgeLeaseLost) EvtKind() string {
+	return "bridge_lease_lost"
+}
+
+func (e *BridgeLeaseLost) EvtBridgeInterface() string {
+	return e.BridgeInterface
+}
+
+func (e *BridgeLeaseLost) EvtBridgePeerCIDRs() []types.CIDR {
+	return e.BridgePeerCIDRs
+}
+
+func (e *BridgeLeaseLost) EvtTimestamp() time.Time {
+	return e.Timestamp
+}
+
+func (e *BridgeLeaseLost) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
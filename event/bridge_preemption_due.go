@@ -0,0 +1,36 @@
+package event
+
+import (
+	"time"
+
+	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type BridgePreemptionDue struct {
+	BridgeInterface string
+	BridgePeerCIDRs []types.CIDR
+	Timestamp       time.Time
+
+	SpanContext trace.SpanContext `json:"-"`
+}
+
+func (e *BridgePreemptionDue) EvtKind() string {
+	return "bridge_preemption_due"
+}
+
+func (e *BridgePreemptionDue) EvtBridgeInterface() string {
+	return e.BridgeInterface
+}
+
+func (e *BridgePreemptionDue) EvtBridgePeerCIDRs() []types.CIDR {
+	return e.BridgePeerCIDRs
+}
+
+func (e *BridgePreemptionDue) EvtTimestamp() time.Time {
+	return e.Timestamp
+}
+
+func (e *BridgePreemptionDue) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
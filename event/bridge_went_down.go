@@ -4,12 +4,15 @@ import (
 	"time"
 
 	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type BridgeWentDown struct {
 	BridgeInterface string
 	BridgePeerCIDRs []types.CIDR
 	Timestamp       time.Time
+
+	SpanContext trace.SpanContext `json:"-"`
 }
 
 func (e *BridgeWentDown) EvtKind() string {
@@ -27,3 +30,7 @@ func (e *BridgeWentDown) EvtBridgePeerCIDRs() []types.CIDR {
 func (e *BridgeWentDown) EvtTimestamp() time.Time {
 	return e.Timestamp
 }
+
+func (e *BridgeWentDown) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
@@ -1,9 +1,15 @@
 package event
 
-import "time"
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
 
 type ConnectivityLost struct {
 	Timestamp time.Time
+
+	SpanContext trace.SpanContext `json:"-"`
 }
 
 func (e *ConnectivityLost) EvtKind() string {
@@ -13,3 +19,7 @@ func (e *ConnectivityLost) EvtKind() string {
 func (e *ConnectivityLost) EvtTimestamp() time.Time {
 	return e.Timestamp
 }
+
+func (e *ConnectivityLost) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
@@ -0,0 +1,11 @@
+package event
+
+import "github.com/flashbots/vpnham/monitor"
+
+// DerivedEvent is an event that was derived from the monitor's state after
+// some other (triggering) event has been registered with it.
+type DerivedEvent interface {
+	Event
+	EvtTrigger() Event
+	EvtWindow() []monitor.Status
+}
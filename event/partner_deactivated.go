@@ -1,9 +1,15 @@
 package event
 
-import "time"
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
 
 type PartnerDeactivated struct {
 	Timestamp time.Time
+
+	SpanContext trace.SpanContext `json:"-"`
 }
 
 func (e *PartnerDeactivated) EvtKind() string {
@@ -13,3 +19,7 @@ func (e *PartnerDeactivated) EvtKind() string {
 func (e *PartnerDeactivated) EvtTimestamp() time.Time {
 	return e.Timestamp
 }
+
+func (e *PartnerDeactivated) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
@@ -5,4 +5,5 @@ import "github.com/flashbots/vpnham/types"
 type PartnerPollEvent interface {
 	Event
 	PartnerStatus() *types.BridgeStatus
+	PartnerPath() string
 }
@@ -7,6 +7,7 @@ import (
 )
 
 type PartnerPollFailure struct {
+	Path      string
 	Sequence  uint64
 	Timestamp time.Time
 }
@@ -19,6 +20,10 @@ func (e *PartnerPollFailure) PartnerStatus() *types.BridgeStatus {
 	return nil
 }
 
+func (e *PartnerPollFailure) PartnerPath() string {
+	return e.Path
+}
+
 func (e *PartnerPollFailure) EvtTimestamp() time.Time {
 	return e.Timestamp
 }
@@ -8,6 +8,7 @@ import (
 
 type PartnerPollSuccess struct {
 	Status    *types.BridgeStatus
+	Path      string
 	Sequence  uint64
 	Timestamp time.Time
 }
@@ -20,6 +21,10 @@ func (e *PartnerPollSuccess) PartnerStatus() *types.BridgeStatus {
 	return e.Status
 }
 
+func (e *PartnerPollSuccess) PartnerPath() string {
+	return e.Path
+}
+
 func (e *PartnerPollSuccess) EvtTimestamp() time.Time {
 	return e.Timestamp
 }
@@ -1,9 +1,18 @@
 package event
 
-import "time"
+import (
+	"time"
+
+	"github.com/flashbots/vpnham/monitor"
+	"go.opentelemetry.io/otel/trace"
+)
 
 type PartnerWentDown struct {
 	Timestamp time.Time
+
+	Trigger     Event             `json:"-"`
+	Window      []monitor.Status  `json:"-"`
+	SpanContext trace.SpanContext `json:"-"`
 }
 
 func (e *PartnerWentDown) EvtKind() string {
@@ -13,3 +22,15 @@ func (e *PartnerWentDown) EvtKind() string {
 func (e *PartnerWentDown) EvtTimestamp() time.Time {
 	return e.Timestamp
 }
+
+func (e *PartnerWentDown) EvtTrigger() Event {
+	return e.Trigger
+}
+
+func (e *PartnerWentDown) EvtWindow() []monitor.Status {
+	return e.Window
+}
+
+func (e *PartnerWentDown) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
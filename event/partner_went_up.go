@@ -1,15 +1,30 @@
 package event
 
-import "time"
+import (
+	"time"
+
+	"github.com/flashbots/vpnham/monitor"
+)
 
 type PartnerWentUp struct {
 	Timestamp time.Time
+
+	Trigger Event            `json:"-"`
+	Window  []monitor.Status `json:"-"`
 }
 
 func (e *PartnerWentUp) EvtKind() string {
-	return "partner_went_down"
+	return "partner_went_up"
 }
 
 func (e *PartnerWentUp) EvtTimestamp() time.Time {
 	return e.Timestamp
 }
+
+func (e *PartnerWentUp) EvtTrigger() Event {
+	return e.Trigger
+}
+
+func (e *PartnerWentUp) EvtWindow() []monitor.Status {
+	return e.Window
+}
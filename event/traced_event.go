@@ -0,0 +1,10 @@
+package event
+
+import "go.opentelemetry.io/otel/trace"
+
+// TracedEvent is an event that belongs to a trace (e.g. the one of the
+// failover that started with a tunnel or partner going down).
+type TracedEvent interface {
+	Event
+	EvtSpanContext() trace.SpanContext
+}
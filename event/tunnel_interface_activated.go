@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type TunnelInterfaceActivated struct {
@@ -11,6 +12,8 @@ type TunnelInterfaceActivated struct {
 	BridgePeerCIDRs []types.CIDR
 	TunnelInterface string
 	Timestamp       time.Time
+
+	SpanContext trace.SpanContext `json:"-"`
 }
 
 func (e *TunnelInterfaceActivated) EvtKind() string {
@@ -32,3 +35,7 @@ func (e *TunnelInterfaceActivated) EvtTunnelInterface() string {
 func (e *TunnelInterfaceActivated) EvtTimestamp() time.Time {
 	return e.Timestamp
 }
+
+func (e *TunnelInterfaceActivated) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
@@ -0,0 +1,24 @@
+package event
+
+import "time"
+
+type TunnelInterfaceBFDStateChanged struct {
+	TunnelInterface string
+	Timestamp       time.Time
+
+	From string
+	To   string
+	Diag string
+}
+
+func (e *TunnelInterfaceBFDStateChanged) EvtKind() string {
+	return "tunnel_interface_bfd_state_changed"
+}
+
+func (e *TunnelInterfaceBFDStateChanged) EvtTunnelInterface() string {
+	return e.TunnelInterface
+}
+
+func (e *TunnelInterfaceBFDStateChanged) EvtTimestamp() time.Time {
+	return e.Timestamp
+}
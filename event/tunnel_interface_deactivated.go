@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type TunnelInterfaceDeactivated struct {
@@ -11,6 +12,8 @@ type TunnelInterfaceDeactivated struct {
 	BridgePeerCIDRs []types.CIDR
 	TunnelInterface string
 	Timestamp       time.Time
+
+	SpanContext trace.SpanContext `json:"-"`
 }
 
 func (e *TunnelInterfaceDeactivated) EvtKind() string {
@@ -32,3 +35,7 @@ func (e *TunnelInterfaceDeactivated) EvtTunnelInterface() string {
 func (e *TunnelInterfaceDeactivated) EvtTimestamp() time.Time {
 	return e.Timestamp
 }
+
+func (e *TunnelInterfaceDeactivated) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
@@ -0,0 +1,45 @@
+package event
+
+import (
+	"time"
+
+	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TunnelInterfaceDegraded struct {
+	BridgeInterface string
+	BridgePeerCIDRs []types.CIDR
+	TunnelInterface string
+	Timestamp       time.Time
+
+	Score         float64
+	Promoted      string
+	PromotedScore float64
+
+	SpanContext trace.SpanContext `json:"-"`
+}
+
+func (e *TunnelInterfaceDegraded) EvtKind() string {
+	return "tunnel_interface_degraded"
+}
+
+func (e *TunnelInterfaceDegraded) EvtBridgeInterface() string {
+	return e.BridgeInterface
+}
+
+func (e *TunnelInterfaceDegraded) EvtBridgePeerCIDRs() []types.CIDR {
+	return e.BridgePeerCIDRs
+}
+
+func (e *TunnelInterfaceDegraded) EvtTunnelInterface() string {
+	return e.TunnelInterface
+}
+
+func (e *TunnelInterfaceDegraded) EvtTimestamp() time.Time {
+	return e.Timestamp
+}
+
+func (e *TunnelInterfaceDegraded) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
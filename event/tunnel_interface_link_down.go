@@ -0,0 +1,23 @@
+package event
+
+import "time"
+
+type TunnelInterfaceLinkDown struct {
+	TunnelInterface string
+	Timestamp       time.Time
+
+	Source string
+	Reason string
+}
+
+func (e *TunnelInterfaceLinkDown) EvtKind() string {
+	return "tunnel_interface_link_down"
+}
+
+func (e *TunnelInterfaceLinkDown) EvtTunnelInterface() string {
+	return e.TunnelInterface
+}
+
+func (e *TunnelInterfaceLinkDown) EvtTimestamp() time.Time {
+	return e.Timestamp
+}
@@ -0,0 +1,22 @@
+package event
+
+import "time"
+
+type TunnelInterfaceLinkUp struct {
+	TunnelInterface string
+	Timestamp       time.Time
+
+	Source string
+}
+
+func (e *TunnelInterfaceLinkUp) EvtKind() string {
+	return "tunnel_interface_link_up"
+}
+
+func (e *TunnelInterfaceLinkUp) EvtTunnelInterface() string {
+	return e.TunnelInterface
+}
+
+func (e *TunnelInterfaceLinkUp) EvtTimestamp() time.Time {
+	return e.Timestamp
+}
@@ -0,0 +1,43 @@
+package event
+
+import (
+	"time"
+
+	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TunnelInterfacePMTUChecked struct {
+	BridgeInterface string
+	BridgePeerCIDRs []types.CIDR
+	TunnelInterface string
+	Timestamp       time.Time
+
+	PMTU int
+
+	SpanContext trace.SpanContext `json:"-"`
+}
+
+func (e *TunnelInterfacePMTUChecked) EvtKind() string {
+	return "tunnel_interface_pmtu_checked"
+}
+
+func (e *TunnelInterfacePMTUChecked) EvtBridgeInterface() string {
+	return e.BridgeInterface
+}
+
+func (e *TunnelInterfacePMTUChecked) EvtBridgePeerCIDRs() []types.CIDR {
+	return e.BridgePeerCIDRs
+}
+
+func (e *TunnelInterfacePMTUChecked) EvtTunnelInterface() string {
+	return e.TunnelInterface
+}
+
+func (e *TunnelInterfacePMTUChecked) EvtTimestamp() time.Time {
+	return e.Timestamp
+}
+
+func (e *TunnelInterfacePMTUChecked) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
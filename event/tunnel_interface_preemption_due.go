@@ -0,0 +1,41 @@
+package event
+
+import (
+	"time"
+
+	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TunnelInterfacePreemptionDue struct {
+	BridgeInterface string
+	BridgePeerCIDRs []types.CIDR
+	TunnelInterface string
+	Timestamp       time.Time
+
+	SpanContext trace.SpanContext `json:"-"`
+}
+
+func (e *TunnelInterfacePreemptionDue) EvtKind() string {
+	return "tunnel_interface_preemption_due"
+}
+
+func (e *TunnelInterfacePreemptionDue) EvtBridgeInterface() string {
+	return e.BridgeInterface
+}
+
+func (e *TunnelInterfacePreemptionDue) EvtBridgePeerCIDRs() []types.CIDR {
+	return e.BridgePeerCIDRs
+}
+
+func (e *TunnelInterfacePreemptionDue) EvtTunnelInterface() string {
+	return e.TunnelInterface
+}
+
+func (e *TunnelInterfacePreemptionDue) EvtTimestamp() time.Time {
+	return e.Timestamp
+}
+
+func (e *TunnelInterfacePreemptionDue) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
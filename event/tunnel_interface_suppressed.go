@@ -0,0 +1,43 @@
+package event
+
+import (
+	"time"
+
+	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TunnelInterfaceSuppressed struct {
+	BridgeInterface string
+	BridgePeerCIDRs []types.CIDR
+	TunnelInterface string
+	Timestamp       time.Time
+
+	Penalty float64
+
+	SpanContext trace.SpanContext `json:"-"`
+}
+
+func (e *TunnelInterfaceSuppressed) EvtKind() string {
+	return "tunnel_interface_suppressed"
+}
+
+func (e *TunnelInterfaceSuppressed) EvtBridgeInterface() string {
+	return e.BridgeInterface
+}
+
+func (e *TunnelInterfaceSuppressed) EvtBridgePeerCIDRs() []types.CIDR {
+	return e.BridgePeerCIDRs
+}
+
+func (e *TunnelInterfaceSuppressed) EvtTunnelInterface() string {
+	return e.TunnelInterface
+}
+
+func (e *TunnelInterfaceSuppressed) EvtTimestamp() time.Time {
+	return e.Timestamp
+}
+
+func (e *TunnelInterfaceSuppressed) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
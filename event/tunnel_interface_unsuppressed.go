@@ -0,0 +1,43 @@
+package event
+
+import (
+	"time"
+
+	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TunnelInterfaceUnsuppressed struct {
+	BridgeInterface string
+	BridgePeerCIDRs []types.CIDR
+	TunnelInterface string
+	Timestamp       time.Time
+
+	Penalty float64
+
+	SpanContext trace.SpanContext `json:"-"`
+}
+
+func (e *TunnelInterfaceUnsuppressed) EvtKind() string {
+	return "tunnel_interface_unsuppressed"
+}
+
+func (e *TunnelInterfaceUnsuppressed) EvtBridgeInterface() string {
+	return e.BridgeInterface
+}
+
+func (e *TunnelInterfaceUnsuppressed) EvtBridgePeerCIDRs() []types.CIDR {
+	return e.BridgePeerCIDRs
+}
+
+func (e *TunnelInterfaceUnsuppressed) EvtTunnelInterface() string {
+	return e.TunnelInterface
+}
+
+func (e *TunnelInterfaceUnsuppressed) EvtTimestamp() time.Time {
+	return e.Timestamp
+}
+
+func (e *TunnelInterfaceUnsuppressed) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
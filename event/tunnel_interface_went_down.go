@@ -3,7 +3,9 @@ package event
 import (
 	"time"
 
+	"github.com/flashbots/vpnham/monitor"
 	"github.com/flashbots/vpnham/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type TunnelInterfaceWentDown struct {
@@ -11,6 +13,10 @@ type TunnelInterfaceWentDown struct {
 	BridgePeerCIDRs []types.CIDR
 	TunnelInterface string
 	Timestamp       time.Time
+
+	Trigger     Event             `json:"-"`
+	Window      []monitor.Status  `json:"-"`
+	SpanContext trace.SpanContext `json:"-"`
 }
 
 func (e *TunnelInterfaceWentDown) EvtKind() string {
@@ -32,3 +38,15 @@ func (e *TunnelInterfaceWentDown) EvtTunnelInterface() string {
 func (e *TunnelInterfaceWentDown) EvtTimestamp() time.Time {
 	return e.Timestamp
 }
+
+func (e *TunnelInterfaceWentDown) EvtTrigger() Event {
+	return e.Trigger
+}
+
+func (e *TunnelInterfaceWentDown) EvtWindow() []monitor.Status {
+	return e.Window
+}
+
+func (e *TunnelInterfaceWentDown) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
@@ -3,6 +3,7 @@ package event
 import (
 	"time"
 
+	"github.com/flashbots/vpnham/monitor"
 	"github.com/flashbots/vpnham/types"
 )
 
@@ -11,6 +12,9 @@ type TunnelInterfaceWentUp struct {
 	BridgePeerCIDRs []types.CIDR
 	TunnelInterface string
 	Timestamp       time.Time
+
+	Trigger Event            `json:"-"`
+	Window  []monitor.Status `json:"-"`
 }
 
 func (e *TunnelInterfaceWentUp) EvtKind() string {
@@ -31,3 +35,11 @@ func (e *TunnelInterfaceWentUp) EvtTunnelInterface() string {
 func (e *TunnelInterfaceWentUp) EvtTimestamp() time.Time {
 	return e.Timestamp
 }
+
+func (e *TunnelInterfaceWentUp) EvtTrigger() Event {
+	return e.Trigger
+}
+
+func (e *TunnelInterfaceWentUp) EvtWindow() []monitor.Status {
+	return e.Window
+}
@@ -4,12 +4,18 @@ import "time"
 
 type TunnelProbeReturnSuccess struct {
 	ProbeSequence   uint64
+	ProbeTarget     string // empty for the default target (at probe_addr)
 	TunnelInterface string
 	Timestamp       time.Time
+	ProbeType       string
 
 	LatencyForward time.Duration
 	LatencyReturn  time.Duration
 	Location       string
+
+	RTT         time.Duration
+	ClockOffset time.Duration
+	OutOfOrder  bool
 }
 
 func (e *TunnelProbeReturnSuccess) EvtKind() string {
@@ -4,6 +4,7 @@ import "time"
 
 type TunnelProbeSendSuccess struct {
 	ProbeSequence   uint64
+	ProbeTarget     string // empty for the default target (at probe_addr)
 	TunnelInterface string
 	Timestamp       time.Time
 }
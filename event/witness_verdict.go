@@ -0,0 +1,29 @@
+package event
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type WitnessVerdict struct {
+	Allowed bool
+	Holder  string
+	Expires time.Time
+
+	Timestamp time.Time
+
+	SpanContext trace.SpanContext `json:"-"`
+}
+
+func (e *WitnessVerdict) EvtKind() string {
+	return "witness_verdict"
+}
+
+func (e *WitnessVerdict) EvtTimestamp() time.Time {
+	return e.Timestamp
+}
+
+func (e *WitnessVerdict) EvtSpanContext() trace.SpanContext {
+	return e.SpanContext
+}
@@ -10,7 +10,6 @@ import (
 	awscli "github.com/flashbots/vpnham/aws"
 	"github.com/flashbots/vpnham/metrics"
 	"github.com/flashbots/vpnham/types"
-	"github.com/flashbots/vpnham/utils"
 	"go.opentelemetry.io/otel/attribute"
 	otelapi "go.opentelemetry.io/otel/metric"
 )
@@ -73,7 +72,7 @@ func (j *UpdateAWSRouteTables) updateRouteTable(
 	networkInterfaceID string,
 ) error {
 	var routes []*awstypes.Route
-	err := utils.WithTimeout(ctx, j.Timeout, func(ctx context.Context) (err error) {
+	err := withSpan(ctx, "aws/FindRoute", j.Timeout, func(ctx context.Context) (err error) {
 		routes, err = j.aws.FindRoute(ctx, routeTable, cidr)
 		return err
 	})
@@ -84,7 +83,7 @@ func (j *UpdateAWSRouteTables) updateRouteTable(
 	switch len(routes) {
 	case 0:
 		// no route yet
-		return utils.WithTimeout(ctx, j.Timeout, func(ctx context.Context) error {
+		return withSpan(ctx, "aws/CreateRoute", j.Timeout, func(ctx context.Context) error {
 			return j.aws.CreateRoute(ctx, routeTable, cidr, networkInterfaceID)
 		})
 
@@ -95,7 +94,7 @@ func (j *UpdateAWSRouteTables) updateRouteTable(
 			return nil
 		}
 		// route exists but with different next hop
-		return utils.WithTimeout(ctx, j.Timeout, func(ctx context.Context) error {
+		return withSpan(ctx, "aws/UpdateRoute", j.Timeout, func(ctx context.Context) error {
 			return j.aws.UpdateRoute(ctx, routeTable, route, cidr, networkInterfaceID)
 		})
 
@@ -104,14 +103,14 @@ func (j *UpdateAWSRouteTables) updateRouteTable(
 		// destination cidr in aws route-table.  but at any rate, if that's the
 		// case let's just delete all of them and create a new one
 		for _, route := range routes {
-			err = utils.WithTimeout(ctx, j.Timeout, func(ctx context.Context) error {
+			err = withSpan(ctx, "aws/DeleteRoute", j.Timeout, func(ctx context.Context) error {
 				return j.aws.DeleteRoute(ctx, routeTable, route)
 			})
 			if err != nil {
 				return err
 			}
 		}
-		return utils.WithTimeout(ctx, j.Timeout, func(ctx context.Context) error {
+		return withSpan(ctx, "aws/CreateRoute", j.Timeout, func(ctx context.Context) error {
 			return j.aws.CreateRoute(ctx, routeTable, cidr, networkInterfaceID)
 		})
 	}
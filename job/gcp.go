@@ -63,7 +63,7 @@ func (j *UpdateGCPRoute) updateRoute(
 	}
 
 	var routes []*gcepb.Route
-	err = utils.WithTimeout(ctx, j.Timeout, func(ctx context.Context) (err error) {
+	err = withSpan(ctx, "gcp/FindRoute", j.Timeout, func(ctx context.Context) (err error) {
 		routes, err = gcp.FindRoute(ctx, j.Network, destRange)
 		return err
 	})
@@ -77,7 +77,7 @@ func (j *UpdateGCPRoute) updateRoute(
 	switch len(routes) {
 	case 0:
 		// no route yet
-		return utils.WithTimeout(ctx, j.Timeout, func(ctx context.Context) error {
+		return withSpan(ctx, "gcp/CreateRoute", j.Timeout, func(ctx context.Context) error {
 			return gcp.CreateRoute(ctx, j.gceRoute(idx, destRange))
 		})
 
@@ -88,13 +88,13 @@ func (j *UpdateGCPRoute) updateRoute(
 			return nil
 		}
 		// route exists but with different config => delete, then create
-		err := utils.WithTimeout(ctx, j.Timeout, func(ctx context.Context) error {
+		err := withSpan(ctx, "gcp/DeleteRoute", j.Timeout, func(ctx context.Context) error {
 			return gcp.DeleteRoute(ctx, route)
 		})
 		if err != nil {
 			return err
 		}
-		return utils.WithTimeout(ctx, j.Timeout, func(ctx context.Context) error {
+		return withSpan(ctx, "gcp/CreateRoute", j.Timeout, func(ctx context.Context) error {
 			return gcp.CreateRoute(ctx, j.gceRoute(idx, destRange))
 		})
 
@@ -105,7 +105,7 @@ func (j *UpdateGCPRoute) updateRoute(
 		for _, route := range routes {
 			if foundMatch {
 				// we already found matching rule, so let's clean up the rest
-				err := utils.WithTimeout(ctx, j.Timeout, func(ctx context.Context) error {
+				err := withSpan(ctx, "gcp/DeleteRoute", j.Timeout, func(ctx context.Context) error {
 					return gcp.DeleteRoute(ctx, route)
 				})
 				if err != nil {
@@ -117,7 +117,7 @@ func (j *UpdateGCPRoute) updateRoute(
 				foundMatch = true
 				continue
 			}
-			err := utils.WithTimeout(ctx, j.Timeout, func(ctx context.Context) error {
+			err := withSpan(ctx, "gcp/DeleteRoute", j.Timeout, func(ctx context.Context) error {
 				return gcp.DeleteRoute(ctx, route)
 			})
 			if err != nil {
@@ -127,7 +127,7 @@ func (j *UpdateGCPRoute) updateRoute(
 
 		// if the match not found, create a new one
 		if !foundMatch {
-			err := utils.WithTimeout(ctx, j.Timeout, func(ctx context.Context) error {
+			err := withSpan(ctx, "gcp/CreateRoute", j.Timeout, func(ctx context.Context) error {
 				return gcp.CreateRoute(ctx, j.gceRoute(idx, destRange))
 			})
 			if err != nil {
@@ -1,8 +1,26 @@
 package job
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/flashbots/vpnham/tracing"
+	"github.com/flashbots/vpnham/utils"
+)
 
 type Job interface {
 	Execute(context.Context) error
 	GetJobName() string
 }
+
+// withSpan executes the cloud api call within its own (timed out) span.
+func withSpan(
+	ctx context.Context,
+	name string,
+	timeout time.Duration,
+	do func(context.Context) error,
+) error {
+	return tracing.WithSpan(ctx, name, func(ctx context.Context) error {
+		return utils.WithTimeout(ctx, timeout, do)
+	})
+}
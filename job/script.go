@@ -10,10 +10,12 @@ import (
 
 	"github.com/flashbots/vpnham/logutils"
 	"github.com/flashbots/vpnham/metrics"
+	"github.com/flashbots/vpnham/tracing"
 	"github.com/flashbots/vpnham/types"
 	"github.com/flashbots/vpnham/utils"
 	"go.opentelemetry.io/otel/attribute"
 	otelapi "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -57,9 +59,14 @@ func (j *RunScript) Execute(ctx context.Context) error {
 		cmd.Env = os.Environ()
 
 		start := time.Now()
-		err := utils.WithTimeout(ctx, j.Timeout, func(ctx context.Context) error {
-			return cmd.Run()
-		})
+		err := tracing.WithSpan(ctx, "script/step", func(ctx context.Context) error {
+			return utils.WithTimeout(ctx, j.Timeout, func(ctx context.Context) error {
+				return cmd.Run()
+			})
+		}, trace.WithAttributes(
+			attribute.Int("step", step),
+			attribute.String("command", strCmd),
+		))
 		duration := time.Since(start)
 
 		if err != nil {
@@ -0,0 +1,50 @@
+package journal
+
+import (
+	"time"
+
+	"github.com/flashbots/vpnham/monitor"
+)
+
+type Entry struct {
+	// Timestamp is the time of the recorded event (or of the job start).
+	Timestamp time.Time `json:"timestamp"`
+
+	// Host is the hostname of the machine where the entry was recorded.
+	Host string `json:"host"`
+
+	// Bridge is the name of the bridge that recorded the entry.
+	Bridge string `json:"bridge"`
+
+	// Kind is the kind of the recorded event (or job outcome).
+	Kind string `json:"kind"`
+
+	// Event is the body of the recorded event.
+	Event any `json:"event,omitempty"`
+
+	// Trigger is the event that caused the recorded one (if any).
+	Trigger *Trigger `json:"trigger,omitempty"`
+
+	// Window is the snapshot of the monitor's history window at the moment
+	// when the recorded event was derived.
+	Window []monitor.Status `json:"window,omitempty"`
+
+	// Job is the outcome of the reconcile job.
+	Job *Job `json:"job,omitempty"`
+}
+
+type Trigger struct {
+	Kind  string `json:"kind"`
+	Event any    `json:"event,omitempty"`
+}
+
+type Job struct {
+	Name       string `json:"name"`
+	DurationUs int64  `json:"duration_us"`
+	Error      string `json:"error,omitempty"`
+}
+
+const (
+	KindJobExecuted = "job_executed"
+	KindJobFailed   = "job_failed"
+)
@@ -0,0 +1,156 @@
+package journal
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/event"
+)
+
+type Journal struct {
+	bridge string
+	host   string
+
+	includeProbes bool
+
+	entries []*Entry
+	next    int
+	full    bool
+
+	file *File
+
+	mx sync.Mutex
+}
+
+func New(bridge string, cfg *config.History) (*Journal, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	j := &Journal{
+		bridge: bridge,
+		host:   host,
+
+		includeProbes: cfg.IncludeProbes,
+
+		entries: make([]*Entry, cfg.Size),
+	}
+
+	if cfg.File != "" {
+		file, err := OpenFile(cfg.File, cfg.FileMaxSize, *cfg.FileMaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		j.file = file
+	}
+
+	return j, nil
+}
+
+func (j *Journal) Close() error {
+	j.mx.Lock()
+	defer j.mx.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// RecordEvent adds the processed event to the journal.  High-frequency probe
+// and poll events are skipped unless explicitly configured otherwise.
+func (j *Journal) RecordEvent(e event.Event) error {
+	if !j.includeProbes {
+		switch e.(type) {
+		case *event.PartnerPollFailure,
+			*event.PartnerPollSuccess,
+			*event.TunnelProbeReturnFailure,
+			*event.TunnelProbeReturnSuccess,
+			*event.TunnelProbeSendFailure,
+			*event.TunnelProbeSendSuccess:
+			return nil
+		}
+	}
+
+	entry := &Entry{
+		Timestamp: e.EvtTimestamp(),
+		Kind:      e.EvtKind(),
+		Event:     e,
+	}
+
+	if de, ok := e.(event.DerivedEvent); ok {
+		if trigger := de.EvtTrigger(); trigger != nil {
+			entry.Trigger = &Trigger{
+				Kind:  trigger.EvtKind(),
+				Event: trigger,
+			}
+		}
+		entry.Window = de.EvtWindow()
+	}
+
+	return j.record(entry)
+}
+
+// RecordJob adds the outcome of the reconcile job to the journal.
+func (j *Journal) RecordJob(name string, start time.Time, duration time.Duration, err error) error {
+	entry := &Entry{
+		Timestamp: start,
+		Kind:      KindJobExecuted,
+		Job: &Job{
+			Name:       name,
+			DurationUs: duration.Microseconds(),
+		},
+	}
+
+	if err != nil {
+		entry.Kind = KindJobFailed
+		entry.Job.Error = err.Error()
+	}
+
+	return j.record(entry)
+}
+
+// Entries returns the journal entries (oldest first).
+func (j *Journal) Entries() []*Entry {
+	j.mx.Lock()
+	defer j.mx.Unlock()
+
+	if !j.full {
+		res := make([]*Entry, j.next)
+		copy(res, j.entries[:j.next])
+		return res
+	}
+
+	res := make([]*Entry, 0, len(j.entries))
+	res = append(res, j.entries[j.next:]...)
+	res = append(res, j.entries[:j.next]...)
+	return res
+}
+
+func (j *Journal) record(entry *Entry) error {
+	entry.Bridge = j.bridge
+	entry.Host = j.host
+
+	j.mx.Lock()
+	defer j.mx.Unlock()
+
+	if len(j.entries) > 0 {
+		j.entries[j.next] = entry
+		j.next++
+		if j.next == len(j.entries) {
+			j.next = 0
+			j.full = true
+		}
+	}
+
+	if j.file != nil {
+		return j.file.Write(entry)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package journal_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/event"
+	"github.com/flashbots/vpnham/journal"
+	"github.com/flashbots/vpnham/monitor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournalRingBuffer(t *testing.T) {
+	j, err := journal.New("test", &config.History{Size: 3})
+	assert.NoError(t, err)
+
+	ts := time.Now()
+	for idx := 0; idx < 5; idx++ {
+		assert.NoError(t, j.RecordEvent(&event.ConnectivityLost{
+			Timestamp: ts.Add(time.Duration(idx) * time.Second),
+		}))
+	}
+
+	entries := j.Entries()
+	assert.Len(t, entries, 3)
+	for idx, entry := range entries {
+		assert.Equal(t, ts.Add(time.Duration(idx+2)*time.Second), entry.Timestamp)
+		assert.Equal(t, "connectivity_lost", entry.Kind)
+		assert.Equal(t, "test", entry.Bridge)
+	}
+}
+
+func TestJournalSkipsProbes(t *testing.T) {
+	j, err := journal.New("test", &config.History{Size: 8})
+	assert.NoError(t, err)
+
+	trigger := &event.TunnelProbeReturnFailure{
+		TunnelInterface: "wg0",
+		ProbeSequence:   7,
+		Timestamp:       time.Now(),
+	}
+	assert.NoError(t, j.RecordEvent(trigger))
+	assert.NoError(t, j.RecordEvent(&event.TunnelInterfaceWentDown{
+		TunnelInterface: "wg0",
+		Timestamp:       trigger.Timestamp,
+		Trigger:         trigger,
+		Window:          []monitor.Status{monitor.Up, monitor.Down, monitor.Down},
+	}))
+
+	entries := j.Entries()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "tunnel_interface_went_down", entries[0].Kind)
+	assert.Equal(t, "tunnel_probe_return_failure", entries[0].Trigger.Kind)
+	assert.Equal(t, []monitor.Status{monitor.Up, monitor.Down, monitor.Down}, entries[0].Window)
+}
+
+func TestJournalFileRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history.jsonl")
+
+	maxBackups := 2
+	j, err := journal.New("test", &config.History{
+		Size:           8,
+		File:           path,
+		FileMaxSize:    1024,
+		FileMaxBackups: &maxBackups,
+	})
+	assert.NoError(t, err)
+
+	ts := time.Now()
+	for idx := 0; idx < 64; idx++ {
+		assert.NoError(t, j.RecordJob("script", ts.Add(time.Duration(idx)*time.Second), time.Millisecond, nil))
+	}
+	assert.NoError(t, j.Close())
+
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(p)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, info.Size(), int64(1024))
+	}
+	_, err = os.Stat(path + ".3")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	f, err := os.Open(path)
+	assert.NoError(t, err)
+	defer f.Close()
+	entries, err := journal.ReadJSONL(f)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, entries)
+	assert.Equal(t, ts.Add(63*time.Second).UTC(), entries[len(entries)-1].Timestamp.UTC())
+	assert.Equal(t, journal.KindJobExecuted, entries[len(entries)-1].Kind)
+}
+
+func TestJournalFileRotationWithoutBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history.jsonl")
+
+	maxBackups := 0
+	j, err := journal.New("test", &config.History{
+		Size:           8,
+		File:           path,
+		FileMaxSize:    1024,
+		FileMaxBackups: &maxBackups,
+	})
+	assert.NoError(t, err)
+
+	ts := time.Now()
+	for idx := 0; idx < 64; idx++ {
+		assert.NoError(t, j.RecordJob("script", ts.Add(time.Duration(idx)*time.Second), time.Millisecond, nil))
+	}
+	assert.NoError(t, j.Close())
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, info.Size(), int64(1024))
+
+	_, err = os.Stat(path + ".1")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestMerge(t *testing.T) {
+	ts := time.Now()
+
+	a := []*journal.Entry{
+		{Timestamp: ts.Add(1 * time.Second), Host: "a"},
+		{Timestamp: ts.Add(4 * time.Second), Host: "a"},
+	}
+	b := []*journal.Entry{
+		{Timestamp: ts.Add(2 * time.Second), Host: "b"},
+		{Timestamp: ts.Add(3 * time.Second), Host: "b"},
+		{Timestamp: ts.Add(5 * time.Second), Host: "b"},
+	}
+
+	merged := journal.Merge(a, b)
+	assert.Len(t, merged, 5)
+	for idx, entry := range merged {
+		assert.Equal(t, ts.Add(time.Duration(idx+1)*time.Second), entry.Timestamp)
+	}
+}
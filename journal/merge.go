@@ -0,0 +1,53 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"slices"
+)
+
+// Merge combines the histories from several bridges into one timeline
+// ordered by the entries' timestamps.
+func Merge(histories ...[]*Entry) []*Entry {
+	count := 0
+	for _, h := range histories {
+		count += len(h)
+	}
+
+	res := make([]*Entry, 0, count)
+	for _, h := range histories {
+		res = append(res, h...)
+	}
+
+	slices.SortStableFunc(res, func(a, b *Entry) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	return res
+}
+
+// ReadJSONL reads the journal entries from the JSONL stream (as written by
+// the journal file sink).
+func ReadJSONL(r io.Reader) ([]*Entry, error) {
+	res := make([]*Entry, 0)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		entry := &Entry{}
+		if err := json.Unmarshal(line, entry); err != nil {
+			return nil, err
+		}
+		res = append(res, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,121 @@
+package lease
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+// consul holds the lease as the key locked by the consul session (with the
+// key deleted once the session is invalidated).
+type consul struct {
+	client *consulapi.Client
+	holder string
+	key    string
+	ttl    time.Duration
+
+	mx      sync.Mutex
+	session string
+}
+
+func newConsul(cfg *config.Lease, holder string) (*consul, error) {
+	c := consulapi.DefaultConfig() // picks up CONSUL_HTTP_TOKEN and friends
+	if len(cfg.Endpoints) > 0 {
+		c.Address = cfg.Endpoints[0]
+	}
+
+	client, err := consulapi.NewClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &consul{
+		client: client,
+		holder: holder,
+		key:    cfg.Key,
+		ttl:    cfg.TTL,
+	}, nil
+}
+
+func (c *consul) Acquire(ctx context.Context) (bool, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	wo := (&consulapi.WriteOptions{}).WithContext(ctx)
+
+	if c.session == "" {
+		session, _, err := c.client.Session().Create(&consulapi.SessionEntry{
+			Name:     "vpnham-" + c.holder,
+			Behavior: consulapi.SessionBehaviorDelete,
+			TTL:      c.ttl.String(),
+		}, wo)
+		if err != nil {
+			return false, err
+		}
+		c.session = session
+	}
+
+	acquired, _, err := c.client.KV().Acquire(&consulapi.KVPair{
+		Key:     c.key,
+		Value:   []byte(c.holder),
+		Session: c.session,
+	}, wo)
+	if err != nil {
+		c.session = "" // might have been invalidated => create the new one next time
+		return false, err
+	}
+
+	return acquired, nil
+}
+
+func (c *consul) Renew(ctx context.Context) (bool, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.session == "" {
+		return false, nil
+	}
+
+	entry, _, err := c.client.Session().Renew(c.session, (&consulapi.WriteOptions{}).WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	if entry == nil { // the session is gone
+		c.session = ""
+		return false, nil
+	}
+
+	pair, _, err := c.client.KV().Get(c.key, (&consulapi.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	if pair == nil || pair.Session != c.session {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (c *consul) Release(ctx context.Context) error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.session == "" {
+		return nil
+	}
+
+	// destroying the session releases (and deletes) the key locked by it
+	if _, err := c.client.Session().Destroy(c.session, (&consulapi.WriteOptions{}).WithContext(ctx)); err != nil {
+		return err
+	}
+	c.session = ""
+
+	return nil
+}
+
+func (c *consul) Close() error {
+	return nil
+}
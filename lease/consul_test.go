@@ -0,0 +1,220 @@
+package lease_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/lease"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type consulRequest struct {
+	method string
+	path   string
+	query  string
+	body   string
+}
+
+// fakeConsul is the consul agent that knows of a single session and a single
+// key, and records the requests it receives.
+type fakeConsul struct {
+	mx       sync.Mutex
+	holder   string // session that holds the key (empty if there's none)
+	session  bool   // whether the session is still valid
+	requests []consulRequest
+}
+
+func (f *fakeConsul) serve(t *testing.T, key string) string {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("PUT /v1/session/create", func(w http.ResponseWriter, r *http.Request) {
+		f.session = true
+		_ = json.NewEncoder(w).Encode(map[string]string{"ID": "session-1"})
+	})
+
+	mux.HandleFunc("PUT /v1/session/renew/session-1", func(w http.ResponseWriter, r *http.Request) {
+		if !f.session {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]map[string]string{{"ID": "session-1"}})
+	})
+
+	mux.HandleFunc("PUT /v1/session/destroy/session-1", func(w http.ResponseWriter, r *http.Request) {
+		f.session = false
+		if f.holder == "session-1" {
+			f.holder = ""
+		}
+		_, _ = io.WriteString(w, "true")
+	})
+
+	mux.HandleFunc("PUT /v1/kv/"+key, func(w http.ResponseWriter, r *http.Request) {
+		session := r.URL.Query().Get("acquire")
+		if f.holder != "" && f.holder != session {
+			_, _ = io.WriteString(w, "false")
+			return
+		}
+		f.holder = session
+		_, _ = io.WriteString(w, "true")
+	})
+
+	mux.HandleFunc("GET /v1/kv/"+key, func(w http.ResponseWriter, r *http.Request) {
+		if f.holder == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]map[string]string{{"Key": key, "Session": f.holder}})
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		f.mx.Lock()
+		defer f.mx.Unlock()
+
+		f.requests = append(f.requests, consulRequest{
+			method: r.Method,
+			path:   r.URL.Path,
+			query:  r.URL.RawQuery,
+			body:   string(body),
+		})
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv.URL
+}
+
+func (f *fakeConsul) setHolder(session string) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.holder = session
+}
+
+func (f *fakeConsul) flush() []consulRequest {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	requests := f.requests
+	f.requests = nil
+	return requests
+}
+
+func TestConsul(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("CONSUL_HTTP_TOKEN", "")
+
+	const key = "vpnham/vpnham-dev/active"
+	f := &fakeConsul{}
+	f.setHolder("session-0")
+
+	backend, err := lease.New(&config.Lease{
+		Backend:   config.LeaseBackendConsul,
+		Endpoints: []string{f.serve(t, key)},
+		Key:       key,
+		TTL:       10 * time.Second,
+		Timeout:   time.Second,
+	}, "active")
+	require.NoError(t, err)
+	defer backend.Close()
+
+	{ // the key is held by somebody else
+		acquired, err := backend.Acquire(ctx)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+
+		requests := f.flush()
+		require.Len(t, requests, 2)
+
+		assert.Equal(t, "PUT", requests[0].method)
+		assert.Equal(t, "/v1/session/create", requests[0].path)
+		session := map[string]string{}
+		require.NoError(t, json.Unmarshal([]byte(requests[0].body), &session))
+		assert.Equal(t, "vpnham-active", session["Name"])
+		assert.Equal(t, "delete", session["Behavior"])
+		assert.Equal(t, "10s", session["TTL"])
+
+		assert.Equal(t, "PUT", requests[1].method)
+		assert.Equal(t, "/v1/kv/"+key, requests[1].path)
+		assert.Equal(t, "acquire=session-1", requests[1].query)
+		assert.Equal(t, "active", requests[1].body)
+	}
+
+	{ // the key is released => it is locked with the existing session
+		f.setHolder("")
+
+		acquired, err := backend.Acquire(ctx)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		requests := f.flush()
+		require.Len(t, requests, 1, "the session is reused")
+		assert.Equal(t, "/v1/kv/"+key, requests[0].path)
+		assert.Equal(t, "acquire=session-1", requests[0].query)
+	}
+
+	{ // renewal renews the session, and checks the key
+		renewed, err := backend.Renew(ctx)
+		require.NoError(t, err)
+		assert.True(t, renewed)
+
+		requests := f.flush()
+		require.Len(t, requests, 2)
+		assert.Equal(t, "PUT", requests[0].method)
+		assert.Equal(t, "/v1/session/renew/session-1", requests[0].path)
+		assert.Equal(t, "GET", requests[1].method)
+		assert.Equal(t, "/v1/kv/"+key, requests[1].path)
+	}
+
+	{ // the key locked by another session is lost
+		f.setHolder("session-0")
+
+		renewed, err := backend.Renew(ctx)
+		require.NoError(t, err)
+		assert.False(t, renewed)
+		f.flush()
+	}
+
+	{ // release destroys the session
+		require.NoError(t, backend.Release(ctx))
+
+		requests := f.flush()
+		require.Len(t, requests, 1)
+		assert.Equal(t, "PUT", requests[0].method)
+		assert.Equal(t, "/v1/session/destroy/session-1", requests[0].path)
+
+		renewed, err := backend.Renew(ctx)
+		require.NoError(t, err)
+		assert.False(t, renewed)
+		assert.Empty(t, f.flush(), "nothing to renew after the release")
+	}
+
+	{ // the invalidated session is gone
+		f.setHolder("")
+
+		acquired, err := backend.Acquire(ctx)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		f.mx.Lock()
+		f.session = false
+		f.mx.Unlock()
+		f.flush()
+
+		renewed, err := backend.Renew(ctx)
+		require.NoError(t, err)
+		assert.False(t, renewed)
+
+		requests := f.flush()
+		require.Len(t, requests, 1)
+		assert.Equal(t, "/v1/session/renew/session-1", requests[0].path)
+	}
+}
@@ -0,0 +1,138 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+// etcd holds the lease as the key attached to the etcd lease (so that the key
+// is gone once the etcd lease expires).
+type etcd struct {
+	client *clientv3.Client
+	holder string
+	key    string
+	ttl    time.Duration
+
+	mx sync.Mutex
+	id clientv3.LeaseID
+}
+
+func newEtcd(cfg *config.Lease, holder string) (*etcd, error) {
+	client, err := clientv3.New(clientv3.Config{
+		DialTimeout: cfg.Timeout,
+		Endpoints:   cfg.Endpoints,
+		Logger:      zap.NewNop(), // errors are reported by the bridge
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &etcd{
+		client: client,
+		holder: holder,
+		key:    cfg.Key,
+		ttl:    cfg.TTL,
+	}, nil
+}
+
+func (e *etcd) Acquire(ctx context.Context) (bool, error) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	if e.id == clientv3.NoLease {
+		grant, err := e.client.Grant(ctx, int64(e.ttl/time.Second))
+		if err != nil {
+			return false, err
+		}
+		e.id = grant.ID
+	}
+
+	res, err := e.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(e.key), "=", 0)).
+		Then(clientv3.OpPut(e.key, e.holder, clientv3.WithLease(e.id))).
+		Else(clientv3.OpGet(e.key)).
+		Commit()
+	if err != nil {
+		if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+			e.id = clientv3.NoLease // expired => grant the new one next time
+		}
+		return false, err
+	}
+	if res.Succeeded {
+		return true, nil
+	}
+
+	kvs := res.Responses[0].GetResponseRange().Kvs
+	if len(kvs) == 0 || string(kvs[0].Value) != e.holder {
+		return false, nil // somebody else is holding it
+	}
+	if clientv3.LeaseID(kvs[0].Lease) == e.id {
+		return true, nil
+	}
+
+	// the key is ours, but from the earlier etcd lease (e.g. before restart)
+	// => move it over to the current one
+	res, err = e.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(e.key), "=", kvs[0].ModRevision)).
+		Then(clientv3.OpPut(e.key, e.holder, clientv3.WithLease(e.id))).
+		Commit()
+	if err != nil {
+		return false, err
+	}
+	return res.Succeeded, nil
+}
+
+func (e *etcd) Renew(ctx context.Context) (bool, error) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	if e.id == clientv3.NoLease {
+		return false, nil
+	}
+
+	if _, err := e.client.KeepAliveOnce(ctx, e.id); err != nil {
+		if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+			e.id = clientv3.NoLease
+			return false, nil
+		}
+		return false, err
+	}
+
+	res, err := e.client.Get(ctx, e.key)
+	if err != nil {
+		return false, err
+	}
+	if len(res.Kvs) == 0 || string(res.Kvs[0].Value) != e.holder || clientv3.LeaseID(res.Kvs[0].Lease) != e.id {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (e *etcd) Release(ctx context.Context) error {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	if e.id == clientv3.NoLease {
+		return nil
+	}
+
+	// revoking the etcd lease deletes the key attached to it
+	if _, err := e.client.Revoke(ctx, e.id); err != nil && !errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		return err
+	}
+	e.id = clientv3.NoLease
+
+	return nil
+}
+
+func (e *etcd) Close() error {
+	return e.client.Close()
+}
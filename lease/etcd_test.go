@@ -0,0 +1,232 @@
+package lease_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/lease"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+)
+
+// fakeEtcd is the etcd server that keeps a single key, and records the
+// requests it receives.
+type fakeEtcd struct {
+	pb.UnimplementedKVServer
+	pb.UnimplementedLeaseServer
+
+	mx      sync.Mutex
+	kv      *mvccpb.KeyValue
+	grants  []*pb.LeaseGrantRequest
+	txns    []*pb.TxnRequest
+	alive   []int64
+	revokes []int64
+}
+
+func (f *fakeEtcd) serve(t *testing.T) string {
+	srv := grpc.NewServer()
+	pb.RegisterKVServer(srv, f)
+	pb.RegisterLeaseServer(srv, f)
+
+	ts := httptest.NewServer(h2c.NewHandler(srv, &http2.Server{}))
+	t.Cleanup(ts.Close)
+
+	return ts.URL
+}
+
+func (f *fakeEtcd) setKV(kv *mvccpb.KeyValue) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.kv = kv
+}
+
+func (f *fakeEtcd) LeaseGrant(_ context.Context, req *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	f.grants = append(f.grants, req)
+	return &pb.LeaseGrantResponse{Header: &pb.ResponseHeader{}, ID: 42, TTL: req.TTL}, nil
+}
+
+func (f *fakeEtcd) LeaseRevoke(_ context.Context, req *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResponse, error) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	f.revokes = append(f.revokes, req.ID)
+	if f.kv != nil && f.kv.Lease == req.ID {
+		f.kv = nil
+	}
+	return &pb.LeaseRevokeResponse{Header: &pb.ResponseHeader{}}, nil
+}
+
+func (f *fakeEtcd) LeaseKeepAlive(stream pb.Lease_LeaseKeepAliveServer) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return nil
+		}
+
+		f.mx.Lock()
+		f.alive = append(f.alive, req.ID)
+		f.mx.Unlock()
+
+		if err := stream.Send(&pb.LeaseKeepAliveResponse{Header: &pb.ResponseHeader{}, ID: req.ID, TTL: 10}); err != nil {
+			return err
+		}
+	}
+}
+
+func (f *fakeEtcd) Range(_ context.Context, req *pb.RangeRequest) (*pb.RangeResponse, error) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	return f.rangeResponse(req), nil
+}
+
+func (f *fakeEtcd) Txn(_ context.Context, req *pb.TxnRequest) (*pb.TxnResponse, error) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	f.txns = append(f.txns, req)
+
+	ops := req.Failure
+	if f.kv == nil {
+		ops = req.Success
+	}
+
+	res := &pb.TxnResponse{Header: &pb.ResponseHeader{}, Succeeded: f.kv == nil}
+	for _, op := range ops {
+		switch {
+		case op.GetRequestPut() != nil:
+			put := op.GetRequestPut()
+			f.kv = &mvccpb.KeyValue{Key: put.Key, Value: put.Value, Lease: put.Lease, CreateRevision: 1, ModRevision: 1}
+			res.Responses = append(res.Responses, &pb.ResponseOp{
+				Response: &pb.ResponseOp_ResponsePut{ResponsePut: &pb.PutResponse{Header: &pb.ResponseHeader{}}},
+			})
+		case op.GetRequestRange() != nil:
+			res.Responses = append(res.Responses, &pb.ResponseOp{
+				Response: &pb.ResponseOp_ResponseRange{ResponseRange: f.rangeResponse(op.GetRequestRange())},
+			})
+		}
+	}
+
+	return res, nil
+}
+
+// rangeResponse returns the key (if it is asked for).
+//
+// Must be called while holding the lock.
+func (f *fakeEtcd) rangeResponse(req *pb.RangeRequest) *pb.RangeResponse {
+	res := &pb.RangeResponse{Header: &pb.ResponseHeader{}}
+	if f.kv != nil && string(f.kv.Key) == string(req.Key) {
+		res.Kvs = []*mvccpb.KeyValue{f.kv}
+		res.Count = 1
+	}
+	return res
+}
+
+func TestEtcd(t *testing.T) {
+	ctx := context.Background()
+
+	const key = "vpnham/vpnham-dev/active"
+	f := &fakeEtcd{}
+	f.setKV(&mvccpb.KeyValue{Key: []byte(key), Value: []byte("standby"), Lease: 7, CreateRevision: 1, ModRevision: 1})
+
+	backend, err := lease.New(&config.Lease{
+		Backend:   config.LeaseBackendEtcd,
+		Endpoints: []string{f.serve(t)},
+		Key:       key,
+		TTL:       10 * time.Second,
+		Timeout:   time.Second,
+	}, "active")
+	require.NoError(t, err)
+	defer backend.Close()
+
+	{ // the key is held by somebody else
+		acquired, err := backend.Acquire(ctx)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+
+		f.mx.Lock()
+		require.Len(t, f.grants, 1)
+		assert.Equal(t, int64(10), f.grants[0].TTL)
+
+		require.Len(t, f.txns, 1)
+		txn := f.txns[0]
+		require.Len(t, txn.Compare, 1)
+		assert.Equal(t, key, string(txn.Compare[0].Key))
+		assert.Equal(t, pb.Compare_CREATE, txn.Compare[0].Target)
+		assert.Equal(t, pb.Compare_EQUAL, txn.Compare[0].Result)
+		assert.Equal(t, int64(0), txn.Compare[0].GetCreateRevision())
+
+		require.Len(t, txn.Success, 1)
+		put := txn.Success[0].GetRequestPut()
+		require.NotNil(t, put)
+		assert.Equal(t, key, string(put.Key))
+		assert.Equal(t, "active", string(put.Value))
+		assert.Equal(t, int64(42), put.Lease)
+
+		require.Len(t, txn.Failure, 1)
+		require.NotNil(t, txn.Failure[0].GetRequestRange())
+		assert.Equal(t, key, string(txn.Failure[0].GetRequestRange().Key))
+		f.mx.Unlock()
+	}
+
+	{ // the key is released => it is put with the granted lease
+		f.setKV(nil)
+
+		acquired, err := backend.Acquire(ctx)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		f.mx.Lock()
+		assert.Len(t, f.grants, 1, "the granted lease is reused")
+		require.NotNil(t, f.kv)
+		assert.Equal(t, "active", string(f.kv.Value))
+		assert.Equal(t, int64(42), f.kv.Lease)
+		f.mx.Unlock()
+	}
+
+	{ // renewal keeps the granted lease alive, and checks the key
+		renewed, err := backend.Renew(ctx)
+		require.NoError(t, err)
+		assert.True(t, renewed)
+
+		f.mx.Lock()
+		assert.Equal(t, []int64{42}, f.alive)
+		f.mx.Unlock()
+	}
+
+	{ // the key taken over by somebody else is lost
+		f.setKV(&mvccpb.KeyValue{Key: []byte(key), Value: []byte("standby"), Lease: 7, CreateRevision: 2, ModRevision: 2})
+
+		renewed, err := backend.Renew(ctx)
+		require.NoError(t, err)
+		assert.False(t, renewed)
+	}
+
+	{ // release revokes the granted lease
+		require.NoError(t, backend.Release(ctx))
+
+		f.mx.Lock()
+		assert.Equal(t, []int64{42}, f.revokes)
+		f.mx.Unlock()
+
+		renewed, err := backend.Renew(ctx)
+		require.NoError(t, err)
+		assert.False(t, renewed)
+
+		f.mx.Lock()
+		assert.Len(t, f.alive, 2, "nothing to keep alive after the release")
+		f.mx.Unlock()
+	}
+}
@@ -0,0 +1,135 @@
+package lease
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FakeStore is the in-process stand-in for the consensus store (for the
+// tests).  Backends of the same store compete for the same leases.
+type FakeStore struct {
+	Now func() time.Time
+
+	mx     sync.Mutex
+	leases map[string]*fakeLease
+}
+
+type fakeLease struct {
+	holder  string
+	expires time.Time
+}
+
+// Fake is the backend on top of the fake store.
+type Fake struct {
+	store  *FakeStore
+	holder string
+	key    string
+	ttl    time.Duration
+}
+
+func NewFakeStore() *FakeStore {
+	return &FakeStore{
+		Now:    time.Now,
+		leases: make(map[string]*fakeLease),
+	}
+}
+
+// Backend returns the backend that holds the lease on behalf of the holder.
+func (s *FakeStore) Backend(key, holder string, ttl time.Duration) *Fake {
+	return &Fake{
+		store:  s,
+		holder: holder,
+		key:    key,
+		ttl:    ttl,
+	}
+}
+
+// Expire takes the lease away from its holder (as if it was not renewed in
+// time).
+func (s *FakeStore) Expire(key string) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	delete(s.leases, key)
+}
+
+// Holder returns the current holder of the lease (if any).
+func (s *FakeStore) Holder(key string) string {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if lease := s.lease(key); lease != nil {
+		return lease.holder
+	}
+	return ""
+}
+
+// lease returns the lease unless it expired.
+//
+// Must be called while holding the store lock.
+func (s *FakeStore) lease(key string) *fakeLease {
+	lease, ok := s.leases[key]
+	if !ok {
+		return nil
+	}
+	if !s.Now().Before(lease.expires) {
+		delete(s.leases, key)
+		return nil
+	}
+	return lease
+}
+
+func (f *Fake) Acquire(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	f.store.mx.Lock()
+	defer f.store.mx.Unlock()
+
+	if lease := f.store.lease(f.key); lease != nil && lease.holder != f.holder {
+		return false, nil
+	}
+
+	f.store.leases[f.key] = &fakeLease{
+		holder:  f.holder,
+		expires: f.store.Now().Add(f.ttl),
+	}
+	return true, nil
+}
+
+func (f *Fake) Renew(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	f.store.mx.Lock()
+	defer f.store.mx.Unlock()
+
+	lease := f.store.lease(f.key)
+	if lease == nil || lease.holder != f.holder {
+		return false, nil
+	}
+
+	lease.expires = f.store.Now().Add(f.ttl)
+	return true, nil
+}
+
+func (f *Fake) Release(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.store.mx.Lock()
+	defer f.store.mx.Unlock()
+
+	if lease := f.store.lease(f.key); lease != nil && lease.holder == f.holder {
+		delete(f.store.leases, f.key)
+	}
+	return nil
+}
+
+func (f *Fake) Close() error {
+	return nil
+}
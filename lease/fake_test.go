@@ -0,0 +1,72 @@
+package lease_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/lease"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFake(t *testing.T) {
+	ctx := context.Background()
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store := lease.NewFakeStore()
+	store.Now = func() time.Time { return ts }
+
+	const key = "vpnham/vpnham-dev/active"
+	var (
+		active  lease.Backend = store.Backend(key, "active", 10*time.Second)
+		standby lease.Backend = store.Backend(key, "standby", 10*time.Second)
+	)
+
+	mustAcquire := func(b lease.Backend) bool {
+		acquired, err := b.Acquire(ctx)
+		require.NoError(t, err)
+		return acquired
+	}
+	mustRenew := func(b lease.Backend) bool {
+		renewed, err := b.Renew(ctx)
+		require.NoError(t, err)
+		return renewed
+	}
+
+	{ // first one gets it, second one has to wait
+		assert.True(t, mustAcquire(active))
+		assert.True(t, mustAcquire(active), "acquire is idempotent")
+		assert.False(t, mustAcquire(standby))
+		assert.False(t, mustRenew(standby))
+		assert.Equal(t, "active", store.Holder(key))
+	}
+
+	{ // renewals keep the lease
+		ts = ts.Add(8 * time.Second)
+		assert.True(t, mustRenew(active))
+		ts = ts.Add(8 * time.Second)
+		assert.False(t, mustAcquire(standby))
+	}
+
+	{ // missed renewals lose it
+		ts = ts.Add(10 * time.Second)
+		assert.True(t, mustAcquire(standby))
+		assert.False(t, mustRenew(active))
+		assert.False(t, mustAcquire(active))
+	}
+
+	{ // release by someone else is no-op, release by the holder frees it
+		require.NoError(t, active.Release(ctx))
+		assert.Equal(t, "standby", store.Holder(key))
+		require.NoError(t, standby.Release(ctx))
+		assert.Equal(t, "", store.Holder(key))
+		assert.True(t, mustAcquire(active))
+	}
+
+	{ // the store can take the lease away
+		store.Expire(key)
+		assert.False(t, mustRenew(active))
+		assert.True(t, mustAcquire(standby))
+	}
+}
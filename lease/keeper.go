@@ -0,0 +1,121 @@
+package lease
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Keeper keeps track of the lease held via the backend.  The calls to the
+// backend are made outside of its lock, so that checking whether the lease is
+// held never waits for the consensus store.  They are made one at a time
+// though (e.g. the release waits for the renewal that is in flight, so that
+// it's not undone by it).
+type Keeper struct {
+	backend  Backend
+	interval time.Duration
+	timeout  time.Duration
+	ttl      time.Duration
+
+	mxBackend sync.Mutex
+
+	mx      sync.Mutex
+	held    bool
+	renewed time.Time
+}
+
+// NewKeeper returns the keeper of the lease with given ttl that is renewed
+// every interval (with every call to the backend limited by the timeout).
+func NewKeeper(backend Backend, ttl, interval, timeout time.Duration) *Keeper {
+	return &Keeper{
+		backend:  backend,
+		interval: interval,
+		timeout:  timeout,
+		ttl:      ttl,
+	}
+}
+
+// Held returns true if we are holding the lease (as far as we know).
+func (k *Keeper) Held() bool {
+	k.mx.Lock()
+	defer k.mx.Unlock()
+
+	return k.held
+}
+
+// Acquire tries to get the lease, and returns true if we got it.
+func (k *Keeper) Acquire(ctx context.Context, ts time.Time) (bool, error) {
+	k.mxBackend.Lock()
+	defer k.mxBackend.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, k.timeout)
+	defer cancel()
+
+	acquired, err := k.backend.Acquire(ctx)
+	if err != nil || !acquired {
+		return false, err
+	}
+
+	k.mx.Lock()
+	defer k.mx.Unlock()
+
+	k.held = true
+	k.renewed = ts
+	return true, nil
+}
+
+// Renew extends the lease that we are holding, and returns true if it is
+// lost (either it's gone, or we could not renew it for so long that it might
+// expire before the next attempt).
+func (k *Keeper) Renew(ctx context.Context, ts time.Time) (bool, error) {
+	k.mxBackend.Lock()
+	defer k.mxBackend.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, k.timeout)
+	defer cancel()
+
+	renewed, err := k.backend.Renew(ctx)
+
+	k.mx.Lock()
+	defer k.mx.Unlock()
+
+	if !k.held {
+		return false, err // released in the meantime
+	}
+
+	if err != nil && ts.Sub(k.renewed)+k.interval < k.ttl {
+		return false, err // still ours (for now)
+	}
+
+	if renewed {
+		k.renewed = ts
+		return false, nil
+	}
+
+	k.held = false
+	return true, err
+}
+
+// Release gives the lease up.
+func (k *Keeper) Release(ctx context.Context) error {
+	k.mxBackend.Lock()
+	defer k.mxBackend.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, k.timeout)
+	defer cancel()
+
+	if err := k.backend.Release(ctx); err != nil {
+		return err
+	}
+
+	k.mx.Lock()
+	defer k.mx.Unlock()
+
+	k.held = false
+	return nil
+}
+
+// Close closes the backend.
+func (k *Keeper) Close() error {
+	return k.backend.Close()
+}
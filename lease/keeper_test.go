@@ -0,0 +1,193 @@
+package lease_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/lease"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyBackend fails the renewals on demand.
+type flakyBackend struct {
+	lease.Backend
+	failRenew bool
+}
+
+func (b *flakyBackend) Renew(ctx context.Context) (bool, error) {
+	if b.failRenew {
+		return false, errors.New("store is unreachable")
+	}
+	return b.Backend.Renew(ctx)
+}
+
+// slowBackend holds the renewals until they are let go.
+type slowBackend struct {
+	lease.Backend
+	renewing chan struct{}
+	proceed  chan struct{}
+}
+
+func (b *slowBackend) Renew(ctx context.Context) (bool, error) {
+	b.renewing <- struct{}{}
+	<-b.proceed
+	return b.Backend.Renew(ctx)
+}
+
+func TestKeeperPreemption(t *testing.T) {
+	ctx := context.Background()
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store := lease.NewFakeStore()
+	store.Now = func() time.Time { return ts }
+
+	const key = "vpnham/vpnham-dev/active"
+	var (
+		active  = lease.NewKeeper(store.Backend(key, "active", 10*time.Second), 10*time.Second, time.Second, time.Second)
+		standby = lease.NewKeeper(store.Backend(key, "standby", 10*time.Second), 10*time.Second, time.Second, time.Second)
+	)
+
+	{ // the active one is down => the standby takes over
+		acquired, err := standby.Acquire(ctx, ts)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+		assert.True(t, standby.Held())
+	}
+
+	{ // the active one is back, but the standby is renewing the lease
+		ts = ts.Add(5 * time.Second)
+		lost, err := standby.Renew(ctx, ts)
+		require.NoError(t, err)
+		assert.False(t, lost)
+
+		acquired, err := active.Acquire(ctx, ts)
+		require.NoError(t, err)
+		assert.False(t, acquired, "lease is held by the standby")
+		assert.False(t, active.Held())
+	}
+
+	{ // the active one is preempting => the standby releases the lease
+		require.NoError(t, standby.Release(ctx))
+		assert.False(t, standby.Held())
+		assert.Equal(t, "", store.Holder(key))
+
+		ts = ts.Add(time.Second)
+		acquired, err := active.Acquire(ctx, ts)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+		assert.True(t, active.Held())
+		assert.Equal(t, "active", store.Holder(key))
+	}
+
+	{ // the standby can not take it back while the active one renews it
+		acquired, err := standby.Acquire(ctx, ts)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+
+		ts = ts.Add(5 * time.Second)
+		lost, err := active.Renew(ctx, ts)
+		require.NoError(t, err)
+		assert.False(t, lost)
+	}
+}
+
+func TestKeeperRenew(t *testing.T) {
+	ctx := context.Background()
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store := lease.NewFakeStore()
+	store.Now = func() time.Time { return ts }
+
+	const key = "vpnham/vpnham-dev/active"
+	backend := &flakyBackend{Backend: store.Backend(key, "active", 10*time.Second)}
+	keeper := lease.NewKeeper(backend, 10*time.Second, 2*time.Second, time.Second)
+
+	acquired, err := keeper.Acquire(ctx, ts)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	{ // failed renewals keep the lease while it can't expire before the next one
+		backend.failRenew = true
+
+		ts = ts.Add(7 * time.Second)
+		lost, err := keeper.Renew(ctx, ts)
+		assert.Error(t, err)
+		assert.False(t, lost)
+		assert.True(t, keeper.Held())
+
+		ts = ts.Add(time.Second)
+		lost, err = keeper.Renew(ctx, ts)
+		assert.Error(t, err)
+		assert.True(t, lost)
+		assert.False(t, keeper.Held())
+	}
+
+	{ // the lease that is gone is lost
+		backend.failRenew = false
+
+		acquired, err := keeper.Acquire(ctx, ts)
+		require.NoError(t, err)
+		require.True(t, acquired)
+
+		store.Expire(key)
+		lost, err := keeper.Renew(ctx, ts)
+		require.NoError(t, err)
+		assert.True(t, lost)
+		assert.False(t, keeper.Held())
+	}
+
+	{ // renewal of the lease that is not held is a no-op
+		lost, err := keeper.Renew(ctx, ts)
+		require.NoError(t, err)
+		assert.False(t, lost)
+	}
+}
+
+func TestKeeperReleaseDuringRenew(t *testing.T) {
+	ctx := context.Background()
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store := lease.NewFakeStore()
+	store.Now = func() time.Time { return ts }
+
+	const key = "vpnham/vpnham-dev/active"
+	backend := &slowBackend{
+		Backend:  store.Backend(key, "active", 10*time.Second),
+		renewing: make(chan struct{}),
+		proceed:  make(chan struct{}),
+	}
+	keeper := lease.NewKeeper(backend, 10*time.Second, time.Second, time.Second)
+
+	acquired, err := keeper.Acquire(ctx, ts)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	renewed := make(chan bool, 1)
+	go func() {
+		lost, _ := keeper.Renew(ctx, ts)
+		renewed <- lost
+	}()
+	<-backend.renewing
+
+	released := make(chan error, 1)
+	go func() {
+		released <- keeper.Release(ctx)
+	}()
+
+	select { // the release waits for the renewal
+	case <-released:
+		t.Fatal("released while renewing")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(backend.proceed)
+	assert.False(t, <-renewed)
+	require.NoError(t, <-released)
+
+	// the renewal did not undo the release
+	assert.False(t, keeper.Held())
+	assert.Equal(t, "", store.Holder(key))
+}
@@ -0,0 +1,46 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flashbots/vpnham/config"
+)
+
+// Backend arbitrates the active role of the bridge: only the holder of the
+// lease may be active.  The lease expires unless it is renewed within its ttl.
+type Backend interface {
+	// Acquire tries to get the lease, and returns true if we got it (or if
+	// we are holding it already).
+	Acquire(ctx context.Context) (bool, error)
+
+	// Renew extends the lease, and returns false if it turns out that we
+	// are not holding it anymore.
+	Renew(ctx context.Context) (bool, error)
+
+	// Release gives the lease up (if we are holding it).
+	Release(ctx context.Context) error
+
+	// Close releases the resources of the backend.
+	Close() error
+}
+
+var (
+	errLeaseBackendIsUnknown = errors.New("unknown lease backend")
+)
+
+// New returns the backend per configuration, with the lease to be held on
+// behalf of the holder.
+func New(cfg *config.Lease, holder string) (Backend, error) {
+	switch cfg.Backend {
+	case config.LeaseBackendConsul:
+		return newConsul(cfg, holder)
+	case config.LeaseBackendEtcd:
+		return newEtcd(cfg, holder)
+	default:
+		return nil, fmt.Errorf("%w: %s",
+			errLeaseBackendIsUnknown, cfg.Backend,
+		)
+	}
+}
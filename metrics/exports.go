@@ -18,6 +18,30 @@ var (
 
 	// TunnelInterfaceUp indicates whether the tunnel interface is up
 	TunnelInterfaceUp otelapi.Int64Observable
+
+	// TunnelInterfacePenalty is the flap penalty of the tunnel interface
+	TunnelInterfacePenalty otelapi.Float64Observable
+
+	// TunnelInterfaceSuppressed indicates whether the tunnel interface is
+	// suppressed due to flapping
+	TunnelInterfaceSuppressed otelapi.Int64Observable
+
+	// TunnelInterfacePMTU is the path mtu of the tunnel interface (as found
+	// by the latest check)
+	TunnelInterfacePMTU otelapi.Int64Observable
+
+	// PartnerPathUp indicates whether the partner is reachable over the path
+	PartnerPathUp otelapi.Int64Observable
+)
+
+// Partner polls
+
+var (
+	// PartnerPollsSent is a counter for the partner status polls
+	PartnerPollsSent otelapi.Int64Counter
+
+	// PartnerPollsFailed is a counter for the failed partner status polls
+	PartnerPollsFailed otelapi.Int64Counter
 )
 
 // Probes
@@ -37,6 +61,24 @@ var (
 
 	// ProbesLatencyReturn is the latency of probes on the way back
 	ProbesLatencyReturn otelapi.Float64Histogram
+
+	// ProbesRTT is the round-trip time of probes (measured by the local clock)
+	ProbesRTT otelapi.Float64Histogram
+
+	// ProbesLossRatio is the rolling ratio of the lost probes
+	ProbesLossRatio otelapi.Float64Gauge
+
+	// ProbesJitter is the variation of the probes round-trip time
+	ProbesJitter otelapi.Float64Gauge
+
+	// ProbesClockOffset is the estimated offset of the peer's clock
+	ProbesClockOffset otelapi.Float64Gauge
+
+	// ProbesOutOfOrder is a counter for the probes that returned out of order
+	ProbesOutOfOrder otelapi.Int64Counter
+
+	// ProbesDuplicate is a counter for the probes that returned more than once
+	ProbesDuplicate otelapi.Int64Counter
 )
 
 // Errors
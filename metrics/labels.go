@@ -4,17 +4,29 @@ const (
 	LabelBridge = "bridge"
 	LabelTunnel = "tunnel"
 
-	LabelProbeSrc = "probe_location_src"
-	LabelProbeDst = "probe_location_dst"
+	LabelProbeSrc    = "probe_location_src"
+	LabelProbeDst    = "probe_location_dst"
+	LabelProbeTarget = "probe_target"
+
+	LabelPartnerPath = "partner_path"
 
 	LabelErrorScope = "scope"
 )
 
 const (
+	ResourceBridges        = "vpnham.bridges"
+	ResourceProbeLocations = "vpnham.probe_locations"
+)
+
+const (
+	ScopeHistory        = "history"
 	ScopeHTTPMiddleware = "http_middleware"
+	ScopeIPsec          = "ipsec"
 	ScopeInternalLogic  = "internal_logic"
+	ScopeLease          = "lease"
 	ScopePartnerPolling = "partner_polling"
 	ScopePeerProbing    = "peer_probing"
 	ScopeStatusListener = "status_listener"
 	ScopeSystem         = "system"
+	ScopeWitness        = "witness"
 )
@@ -5,6 +5,7 @@ import (
 	"math"
 
 	"github.com/flashbots/vpnham/config"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/prometheus"
 	otelapi "go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/sdk/metric"
@@ -16,6 +17,7 @@ const (
 )
 
 var (
+	provider            *metric.MeterProvider
 	meter               otelapi.Meter
 	latencyBoundariesUs otelapi.HistogramOption
 )
@@ -35,6 +37,15 @@ func Setup(
 		setupBridgeUp,
 		setupTunnelInterfaceActive,
 		setupTunnelInterfaceUp,
+		setupTunnelInterfacePenalty,
+		setupTunnelInterfaceSuppressed,
+		setupTunnelInterfacePMTU,
+		setupPartnerPathUp,
+
+		// Partner polls
+
+		setupPartnerPollsSent,
+		setupPartnerPollsFailed,
 
 		// Probes
 
@@ -43,6 +54,12 @@ func Setup(
 		setupProbesFailed,
 		setupProbesLatencyForward,
 		setupProbesLatencyReturn,
+		setupProbesRTT,
+		setupProbesLossRatio,
+		setupProbesJitter,
+		setupProbesClockOffset,
+		setupProbesOutOfOrder,
+		setupProbesDuplicate,
 
 		// Errors
 
@@ -64,6 +81,10 @@ func Setup(
 		BridgeUp,
 		TunnelInterfaceActive,
 		TunnelInterfaceUp,
+		TunnelInterfacePenalty,
+		TunnelInterfaceSuppressed,
+		TunnelInterfacePMTU,
+		PartnerPathUp,
 	); err != nil {
 		return err
 	}
@@ -71,30 +92,64 @@ func Setup(
 	return nil
 }
 
-func setupMeter(ctx context.Context, _ *config.Metrics) error {
-	res, err := resource.New(ctx)
-	if err != nil {
-		return err
+func setupMeter(ctx context.Context, cfg *config.Metrics) error {
+	attrs := make([]attribute.KeyValue, 0, len(cfg.ResourceAttributes)+2)
+	if len(cfg.BridgeNames) > 0 {
+		attrs = append(attrs, attribute.StringSlice(ResourceBridges, cfg.BridgeNames))
+	}
+	if len(cfg.ProbeLocations) > 0 {
+		attrs = append(attrs, attribute.StringSlice(ResourceProbeLocations, cfg.ProbeLocations))
+	}
+	for k, v := range cfg.ResourceAttributes {
+		attrs = append(attrs, attribute.String(k, v))
 	}
 
-	exporter, err := prometheus.New(
-		prometheus.WithNamespace(metricsNamespace),
-		prometheus.WithoutScopeInfo(),
+	res, err := resource.New(ctx,
+		resource.WithHost(),
+		resource.WithAttributes(attrs...),
 	)
 	if err != nil {
 		return err
 	}
 
-	provider := metric.NewMeterProvider(
-		metric.WithReader(exporter),
+	opts := []metric.Option{
 		metric.WithResource(res),
-	)
+	}
 
+	if !cfg.DisablePrometheus {
+		exporter, err := prometheus.New(
+			prometheus.WithNamespace(metricsNamespace),
+			prometheus.WithoutScopeInfo(),
+		)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, metric.WithReader(exporter))
+	}
+
+	if cfg.OTLP.Enabled() {
+		reader, err := newOTLPReader(ctx, cfg.OTLP)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, metric.WithReader(reader))
+	}
+
+	provider = metric.NewMeterProvider(opts...)
 	meter = provider.Meter(metricsNamespace)
 
 	return nil
 }
 
+// Shutdown flushes the pending metrics (if pushed via otlp) and stops the
+// readers.
+func Shutdown(ctx context.Context) error {
+	if provider == nil {
+		return nil
+	}
+	return provider.Shutdown(ctx)
+}
+
 func setupLatencyBoundariesUs(ctx context.Context, cfg *config.Metrics) error {
 	latencyBoundariesUs = otelapi.WithExplicitBucketBoundaries(func() []float64 {
 		base := math.Exp(math.Log(float64(cfg.MaxLatencyUs)) / (float64(cfg.LatencyBucketsCount - 1)))
@@ -156,6 +211,74 @@ func setupTunnelInterfaceUp(ctx context.Context, _ *config.Metrics) error {
 	return nil
 }
 
+func setupTunnelInterfacePenalty(ctx context.Context, _ *config.Metrics) error {
+	tunnelInterfacePenalty, err := meter.Float64ObservableGauge("tunnel_interface_penalty",
+		otelapi.WithDescription("flap penalty of the tunnel interface"),
+	)
+	if err != nil {
+		return err
+	}
+	TunnelInterfacePenalty = tunnelInterfacePenalty
+	return nil
+}
+
+func setupTunnelInterfaceSuppressed(ctx context.Context, _ *config.Metrics) error {
+	tunnelInterfaceSuppressed, err := meter.Int64ObservableGauge("tunnel_interface_suppressed",
+		otelapi.WithDescription("indicates whether the tunnel interface is suppressed due to flapping"),
+	)
+	if err != nil {
+		return err
+	}
+	TunnelInterfaceSuppressed = tunnelInterfaceSuppressed
+	return nil
+}
+
+func setupTunnelInterfacePMTU(ctx context.Context, _ *config.Metrics) error {
+	tunnelInterfacePMTU, err := meter.Int64ObservableGauge("tunnel_interface_pmtu",
+		otelapi.WithDescription("largest packet size that made it through the tunnel interface and back"),
+	)
+	if err != nil {
+		return err
+	}
+	TunnelInterfacePMTU = tunnelInterfacePMTU
+	return nil
+}
+
+func setupPartnerPathUp(ctx context.Context, _ *config.Metrics) error {
+	partnerPathUp, err := meter.Int64ObservableGauge("partner_path_up",
+		otelapi.WithDescription("whether the partner is reachable over the path"),
+	)
+	if err != nil {
+		return err
+	}
+	PartnerPathUp = partnerPathUp
+	return nil
+}
+
+// Partner polls
+
+func setupPartnerPollsSent(ctx context.Context, _ *config.Metrics) error {
+	partnerPollsSent, err := meter.Int64Counter("partner_polls_sent",
+		otelapi.WithDescription("counter for the partner status polls"),
+	)
+	if err != nil {
+		return err
+	}
+	PartnerPollsSent = partnerPollsSent
+	return nil
+}
+
+func setupPartnerPollsFailed(ctx context.Context, _ *config.Metrics) error {
+	partnerPollsFailed, err := meter.Int64Counter("partner_polls_failed",
+		otelapi.WithDescription("counter for the failed partner status polls"),
+	)
+	if err != nil {
+		return err
+	}
+	PartnerPollsFailed = partnerPollsFailed
+	return nil
+}
+
 // Probes
 
 func setupProbesSent(ctx context.Context, _ *config.Metrics) error {
@@ -217,6 +340,76 @@ func setupProbesLatencyReturn(ctx context.Context, _ *config.Metrics) error {
 	return nil
 }
 
+func setupProbesRTT(ctx context.Context, _ *config.Metrics) error {
+	probesRTT, err := meter.Float64Histogram("probes_rtt",
+		otelapi.WithDescription("round-trip time of probes"),
+		otelapi.WithUnit("us"),
+		latencyBoundariesUs,
+	)
+	if err != nil {
+		return err
+	}
+	ProbesRTT = probesRTT
+	return nil
+}
+
+func setupProbesLossRatio(ctx context.Context, _ *config.Metrics) error {
+	probesLossRatio, err := meter.Float64Gauge("probes_loss_ratio",
+		otelapi.WithDescription("rolling ratio of the lost probes"),
+	)
+	if err != nil {
+		return err
+	}
+	ProbesLossRatio = probesLossRatio
+	return nil
+}
+
+func setupProbesJitter(ctx context.Context, _ *config.Metrics) error {
+	probesJitter, err := meter.Float64Gauge("probes_jitter",
+		otelapi.WithDescription("variation of the probes round-trip time"),
+		otelapi.WithUnit("us"),
+	)
+	if err != nil {
+		return err
+	}
+	ProbesJitter = probesJitter
+	return nil
+}
+
+func setupProbesClockOffset(ctx context.Context, _ *config.Metrics) error {
+	probesClockOffset, err := meter.Float64Gauge("probes_clock_offset",
+		otelapi.WithDescription("estimated offset of the peer's clock"),
+		otelapi.WithUnit("us"),
+	)
+	if err != nil {
+		return err
+	}
+	ProbesClockOffset = probesClockOffset
+	return nil
+}
+
+func setupProbesOutOfOrder(ctx context.Context, _ *config.Metrics) error {
+	probesOutOfOrder, err := meter.Int64Counter("probes_out_of_order",
+		otelapi.WithDescription("counter for the probes that returned out of order"),
+	)
+	if err != nil {
+		return err
+	}
+	ProbesOutOfOrder = probesOutOfOrder
+	return nil
+}
+
+func setupProbesDuplicate(ctx context.Context, _ *config.Metrics) error {
+	probesDuplicate, err := meter.Int64Counter("probes_duplicate",
+		otelapi.WithDescription("counter for the probes that returned more than once"),
+	)
+	if err != nil {
+		return err
+	}
+	ProbesDuplicate = probesDuplicate
+	return nil
+}
+
 // Errors
 
 func setupErrors(ctx context.Context, _ *config.Metrics) error {
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/flashbots/vpnham/config"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	errOTLPCAIsInvalid = errors.New("invalid otlp ca certificate")
+)
+
+func newOTLPReader(ctx context.Context, cfg *config.MetricsOTLP) (metric.Reader, error) {
+	tlsConfig, err := newOTLPTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	var exporter metric.Exporter
+	switch cfg.Protocol {
+	case config.OTLPProtocolGRPC:
+		opts := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithEndpoint(cfg.Endpoint),
+			otlpmetricgrpc.WithHeaders(cfg.Headers),
+			otlpmetricgrpc.WithTimeout(cfg.Timeout),
+		}
+		switch {
+		case cfg.Insecure:
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		case tlsConfig != nil:
+			opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		}
+		exporter, err = otlpmetricgrpc.New(ctx, opts...)
+
+	case config.OTLPProtocolHTTP:
+		opts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithEndpoint(cfg.Endpoint),
+			otlpmetrichttp.WithHeaders(cfg.Headers),
+			otlpmetrichttp.WithTimeout(cfg.Timeout),
+		}
+		switch {
+		case cfg.Insecure:
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		case tlsConfig != nil:
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsConfig))
+		}
+		exporter, err = otlpmetrichttp.New(ctx, opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return metric.NewPeriodicReader(exporter,
+		metric.WithInterval(cfg.Interval),
+		metric.WithTimeout(cfg.Timeout),
+	), nil
+}
+
+func newOTLPTLSConfig(cfg *config.TLS) (*tls.Config, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	res := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if cfg.CA != "" {
+		pem, err := os.ReadFile(cfg.CA)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: %s",
+				errOTLPCAIsInvalid, cfg.CA,
+			)
+		}
+		res.RootCAs = pool
+	}
+
+	if cfg.Cert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.Cert, cfg.Key)
+		if err != nil {
+			return nil, err
+		}
+		res.Certificates = []tls.Certificate{cert}
+	}
+
+	return res, nil
+}
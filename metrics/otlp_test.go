@@ -0,0 +1,122 @@
+package metrics_test
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/metrics"
+	"github.com/stretchr/testify/assert"
+	otelapi "go.opentelemetry.io/otel/metric"
+	collector "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOTLPExport(t *testing.T) {
+	var (
+		mx       sync.Mutex
+		requests []*collector.ExportMetricsServiceRequest
+		apiKeys  []string
+	)
+
+	collectorStandIn := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		req := &collector.ExportMetricsServiceRequest{}
+		assert.NoError(t, proto.Unmarshal(body, req))
+
+		mx.Lock()
+		requests = append(requests, req)
+		apiKeys = append(apiKeys, r.Header.Get("x-api-key"))
+		mx.Unlock()
+
+		res, err := proto.Marshal(&collector.ExportMetricsServiceResponse{})
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(res)
+	}))
+	defer collectorStandIn.Close()
+
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(ca, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: collectorStandIn.Certificate().Raw,
+	}), 0o600))
+
+	ctx := context.Background()
+	cfg := &config.Metrics{
+		DisablePrometheus: true,
+		OTLP: &config.MetricsOTLP{
+			Endpoint: strings.TrimPrefix(collectorStandIn.URL, "https://"),
+			Protocol: config.OTLPProtocolHTTP,
+			Headers:  map[string]string{"x-api-key": "secret"},
+			TLS:      &config.TLS{CA: ca},
+		},
+		ResourceAttributes: map[string]string{"environment": "test"},
+		BridgeNames:        []string{"left", "right"},
+		ProbeLocations:     []string{"left/active"},
+	}
+	assert.NoError(t, cfg.PostLoad(ctx))
+	assert.NoError(t, cfg.Validate(ctx))
+
+	assert.NoError(t, metrics.Setup(ctx, cfg, func(context.Context, otelapi.Observer) error {
+		return nil
+	}))
+	metrics.ProbesSent.Add(ctx, 42)
+	assert.NoError(t, metrics.Shutdown(ctx)) // flushes the pending metrics
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	if !assert.NotEmpty(t, requests) {
+		return
+	}
+	assert.Equal(t, "secret", apiKeys[0])
+
+	resourceMetrics := requests[0].GetResourceMetrics()
+	if !assert.Len(t, resourceMetrics, 1) {
+		return
+	}
+
+	attrs := make(map[string]string)
+	for _, attr := range resourceMetrics[0].GetResource().GetAttributes() {
+		switch v := attr.GetValue(); {
+		case v.GetArrayValue() != nil:
+			values := make([]string, 0)
+			for _, e := range v.GetArrayValue().GetValues() {
+				values = append(values, e.GetStringValue())
+			}
+			attrs[attr.GetKey()] = strings.Join(values, ",")
+		default:
+			attrs[attr.GetKey()] = v.GetStringValue()
+		}
+	}
+	assert.Equal(t, "left,right", attrs[metrics.ResourceBridges])
+	assert.Equal(t, "left/active", attrs[metrics.ResourceProbeLocations])
+	assert.Equal(t, "test", attrs["environment"])
+	assert.NotEmpty(t, attrs["host.name"])
+
+	found := false
+	for _, sm := range resourceMetrics[0].GetScopeMetrics() {
+		for _, m := range sm.GetMetrics() {
+			if m.GetName() != "probes_sent" {
+				continue
+			}
+			found = true
+			points := m.GetSum().GetDataPoints()
+			if assert.Len(t, points, 1) {
+				assert.Equal(t, int64(42), points[0].GetAsInt())
+			}
+		}
+	}
+	assert.True(t, found, "probes_sent metric was not exported")
+}
@@ -28,7 +28,9 @@ func NewServer(ctx context.Context, cfg *config.Metrics) *Server {
 
 	mux := http.NewServeMux()
 	mux.Handle("/", http.HandlerFunc(s.healthcheck))
-	mux.Handle("/metrics", promhttp.Handler())
+	if !cfg.DisablePrometheus {
+		mux.Handle("/metrics", promhttp.Handler())
+	}
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
 	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
 	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
@@ -0,0 +1,26 @@
+package monitor
+
+// Aggregate derives the overall status from the statuses of several monitors:
+// it is up once at least quorum of them are up, and it is down once there are
+// so many down that the quorum can not be reached.  Otherwise it is pending
+// (i.e. the previous overall status holds).
+func Aggregate(statuses []Status, quorum int) Status {
+	up, down := 0, 0
+	for _, s := range statuses {
+		switch s {
+		case Up:
+			up++
+		case Down:
+			down++
+		}
+	}
+
+	switch {
+	case up >= quorum:
+		return Up
+	case down > len(statuses)-quorum:
+		return Down
+	default:
+		return Pending
+	}
+}
@@ -0,0 +1,99 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type lossRatio struct {
+	window           int
+	span             time.Duration
+	dnRatio, upRatio float64
+}
+
+var (
+	errLossRatiosAreInvalid = errors.New("loss ratios are invalid")
+)
+
+// NewLossRatio returns the monitor that reports `down` status once the ratio
+// of the lost statuses within the sliding window reaches the down ratio, and
+// `up` status once it falls to the up ratio (or below).  In between, the
+// status is `pending` (so that isolated losses are tolerated).
+//
+// If the span is non-zero, only the statuses that are not older than the span
+// (counting from the latest one) are accounted for.  This way the window
+// covers the same amount of time even if the statuses come in at variable
+// rate (in which case the window must be large enough to fit all of them).
+func NewLossRatio(window int, span time.Duration, downRatio, upRatio float64) (*Monitor, error) {
+	if window < 2 || MaxWindow < window {
+		return nil, fmt.Errorf("%w: expected 1 < N <= %d, got %d",
+			errWindowIsInvalid, MaxWindow, window,
+		)
+	}
+
+	if upRatio < 0 || downRatio <= upRatio || 1 < downRatio {
+		return nil, fmt.Errorf("%w: expected 0 <= up < down <= 1, got up %f and down %f",
+			errLossRatiosAreInvalid, upRatio, downRatio,
+		)
+	}
+
+	if span < 0 {
+		return nil, fmt.Errorf("%w: negative span %s",
+			errWindowIsInvalid, span,
+		)
+	}
+
+	return newMonitor(window, &lossRatio{
+		window:  window,
+		span:    span,
+		dnRatio: downRatio,
+		upRatio: upRatio,
+	}), nil
+}
+
+func (p *lossRatio) register(uint64, Status, time.Time) {}
+
+func (p *lossRatio) status(m *Monitor) Status {
+	if !m.started {
+		return Pending
+	}
+
+	var latest time.Time
+	for _, ts := range m.timestamps {
+		if ts.After(latest) {
+			latest = ts
+		}
+	}
+
+	lost, total := 0, 0
+	for idx, s := range m.history {
+		if age := uint64(len(m.history) - 1 - idx); age > m.sequence-m.first {
+			continue // before the very first status
+		}
+		if idx == len(m.history)-1 && s == Pending {
+			continue // still in flight
+		}
+		if p.span > 0 && latest.Sub(m.timestamps[idx]) > p.span {
+			continue // too old
+		}
+		total++
+		if s != Up {
+			lost++
+		}
+	}
+
+	if total == 0 {
+		return Pending
+	}
+
+	ratio := float64(lost) / float64(total)
+	switch {
+	case ratio >= p.dnRatio:
+		return Down
+	case ratio <= p.upRatio:
+		return Up
+	}
+
+	return Pending
+}
@@ -3,26 +3,42 @@ package monitor
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 const (
 	maxThreshold = 10
+
+	// MaxWindow is the largest sliding window the loss ratio monitor accepts.
+	MaxWindow = 3600
 )
 
 type Monitor struct {
-	dnThreshold, upThreshold int
+	policy policy
 
 	sequence uint64
+	started  bool
+	first    uint64
+
+	history    []Status
+	timestamps []time.Time
+}
 
-	history []Status
+// policy derives the overall status from the history of the statuses
+type policy interface {
+	register(sequence uint64, status Status, ts time.Time)
+	status(m *Monitor) Status
 }
 
 var (
 	errDownThresholdIsInvalid = errors.New("down threshold is invalid")
 	errUpThresholdIsInvalid   = errors.New("up threshold is invalid")
+	errWindowIsInvalid        = errors.New("window is invalid")
 	errSequenceTooHigh        = errors.New("sequence is too high")
 )
 
+// New returns the monitor that reports the status once there's a streak of
+// the same statuses that is at least as long as the threshold.
 func New(downThreshold, upThreshold int) (*Monitor, error) {
 	if downThreshold < 2 || maxThreshold < downThreshold {
 		return nil, fmt.Errorf("%w: expected 1 < N < %d, got %d",
@@ -36,46 +52,67 @@ func New(downThreshold, upThreshold int) (*Monitor, error) {
 		)
 	}
 
-	history := make([]Status, max(downThreshold, upThreshold)+1)
-	for idx := len(history); idx < cap(history); idx++ {
-		history = append(history, 0)
-	}
-
-	return &Monitor{
+	return newMonitor(max(downThreshold, upThreshold)+1, &streak{
 		dnThreshold: downThreshold,
 		upThreshold: upThreshold,
+	}), nil
+}
 
-		history: history,
-	}, nil
+func newMonitor(historySize int, p policy) *Monitor {
+	return &Monitor{
+		policy:     p,
+		history:    make([]Status, historySize),
+		timestamps: make([]time.Time, historySize),
+	}
 }
 
 func (m *Monitor) Sequence() uint64 {
 	return m.sequence
 }
 
+// Window returns a copy of the statuses that are currently in the monitor's
+// history window (oldest first).
+func (m *Monitor) Window() []Status {
+	window := make([]Status, len(m.history))
+	copy(window, m.history)
+	return window
+}
+
 func (m *Monitor) advanceSequence(sequence uint64) {
 	jump := int(sequence - m.sequence)
 
 	if jump >= len(m.history) {
 		for idx := 0; idx < len(m.history); idx++ {
 			m.history[idx] = Pending
+			m.timestamps[idx] = time.Time{}
 		}
+		m.sequence = sequence
 		return
 	}
 
 	copy(m.history, m.history[jump:])
+	copy(m.timestamps, m.timestamps[jump:])
 	for idx := len(m.history) - jump; idx < len(m.history); idx++ {
 		m.history[idx] = Pending
+		m.timestamps[idx] = time.Time{}
 	}
 
 	m.sequence = sequence
 }
 
-func (m *Monitor) RegisterStatus(sequence uint64, status Status) {
+func (m *Monitor) RegisterStatus(sequence uint64, status Status, ts time.Time) {
+	if !m.started {
+		m.started = true
+		m.first = sequence
+	}
+	m.first = min(m.first, sequence)
+
 	if sequence > m.sequence {
 		m.advanceSequence(sequence)
 	}
 
+	m.policy.register(sequence, status, ts)
+
 	offset := m.sequence - sequence
 	historyLength := uint64(len(m.history))
 
@@ -88,50 +125,11 @@ func (m *Monitor) RegisterStatus(sequence uint64, status Status) {
 	index := sequence - historyStart
 
 	m.history[int(index)] = status
+	if m.timestamps[int(index)].IsZero() {
+		m.timestamps[int(index)] = ts // i.e. when the probe was sent
+	}
 }
 
 func (m *Monitor) Status() Status {
-	dnStreak := 0
-	unStreak := 0
-	upStreak := 0
-
-	window := min(len(m.history), max(m.dnThreshold, m.upThreshold))
-
-	firstState := true
-	for idx := len(m.history) - 1; idx >= len(m.history)-window; idx-- {
-		switch m.history[idx] {
-		case Up:
-			dnStreak = -1
-			unStreak = -1
-			if upStreak >= 0 {
-				upStreak++
-			}
-		case Down:
-			if dnStreak >= 0 {
-				dnStreak++
-			}
-			unStreak = -1
-			upStreak = -1
-		case Pending:
-			if dnStreak >= 0 {
-				dnStreak++ // sequence of unknown states also counts as down-streak
-			}
-			if unStreak >= 0 {
-				unStreak++
-			}
-			if firstState && upStreak >= 0 {
-				upStreak++ // first unknown state also counts as up-streak
-			}
-		}
-		firstState = false
-
-		if dnStreak >= m.dnThreshold && unStreak < dnStreak {
-			return Down
-		}
-		if upStreak >= m.upThreshold && unStreak < upStreak {
-			return Up
-		}
-	}
-
-	return Pending
+	return m.policy.status(m)
 }
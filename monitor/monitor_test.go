@@ -2,6 +2,7 @@ package monitor_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/flashbots/vpnham/monitor"
 	"github.com/stretchr/testify/assert"
@@ -13,6 +14,16 @@ type event struct {
 	expected monitor.Status
 }
 
+func run(t *testing.T, m *monitor.Monitor, tc testCase) {
+	for idx, step := range tc {
+		ts := time.Unix(int64(step.sequence), 0)
+		m.RegisterStatus(step.sequence, step.probe, ts)
+		assert.Equal(t, step.expected, m.Status(), "step %d: expected %s, got %s",
+			idx, step.expected, m.Status(),
+		)
+	}
+}
+
 type testCase = []event
 
 func TestMonitor(t *testing.T) {
@@ -35,10 +46,136 @@ func TestMonitor(t *testing.T) {
 		{sequence: 10, probe: monitor.Pending, expected: monitor.Up},
 	}
 
-	for idx, step := range tc {
-		m.RegisterStatus(step.sequence, step.probe)
-		assert.Equal(t, step.expected, m.Status(), "step %d: expected %s, got %s",
-			idx, step.expected, m.Status(),
-		)
+	run(t, m, tc)
+}
+
+func TestMonitorLossRatio(t *testing.T) {
+	m, err := monitor.NewLossRatio(5, 0, 0.6, 0.2)
+	assert.NoError(t, err)
+
+	tc := testCase{
+		{sequence: 0, probe: monitor.Pending, expected: monitor.Pending},
+		{sequence: 0, probe: monitor.Up, expected: monitor.Up},
+		{sequence: 1, probe: monitor.Down, expected: monitor.Pending},
+		{sequence: 2, probe: monitor.Up, expected: monitor.Pending},
+		{sequence: 3, probe: monitor.Up, expected: monitor.Pending},
+		{sequence: 4, probe: monitor.Pending, expected: monitor.Pending},
+		{sequence: 4, probe: monitor.Up, expected: monitor.Up},
+		{sequence: 5, probe: monitor.Down, expected: monitor.Pending},
+		{sequence: 6, probe: monitor.Down, expected: monitor.Pending},
+		{sequence: 7, probe: monitor.Down, expected: monitor.Down},
+		{sequence: 8, probe: monitor.Up, expected: monitor.Down},
+		{sequence: 9, probe: monitor.Up, expected: monitor.Down},
+		{sequence: 10, probe: monitor.Up, expected: monitor.Pending},
+		{sequence: 11, probe: monitor.Up, expected: monitor.Up},
+		{sequence: 30, probe: monitor.Pending, expected: monitor.Down},
+	}
+
+	run(t, m, tc)
+}
+
+func TestMonitorLossRatioSpan(t *testing.T) {
+	m, err := monitor.NewLossRatio(20, 4*time.Second, 0.5, 0.25)
+	assert.NoError(t, err)
+
+	ts := time.Unix(0, 0)
+	register := func(sequence uint64, status monitor.Status, after time.Duration) {
+		ts = ts.Add(after)
+		m.RegisterStatus(sequence, status, ts)
+	}
+
+	// slow probes (1 per second)
+	register(1, monitor.Up, time.Second)
+	register(2, monitor.Up, time.Second)
+	register(3, monitor.Up, time.Second)
+	register(4, monitor.Up, time.Second)
+	assert.Equal(t, monitor.Up, m.Status())
+
+	// fast probes (4 per second) after the first miss
+	register(5, monitor.Down, time.Second)
+	assert.Equal(t, monitor.Up, m.Status()) // 1 of 5 lost
+	register(6, monitor.Down, 250*time.Millisecond)
+	assert.Equal(t, monitor.Pending, m.Status()) // 2 of 5 lost (1st is out of span)
+	register(7, monitor.Down, 250*time.Millisecond)
+	assert.Equal(t, monitor.Down, m.Status()) // 3 of 6 lost
+
+	// recovery (older statuses fall out of the span)
+	register(8, monitor.Down, 250*time.Millisecond)
+	for seq := uint64(9); seq <= 16; seq++ {
+		register(seq, monitor.Up, 250*time.Millisecond)
+	}
+	assert.Equal(t, monitor.Pending, m.Status()) // 4 of 13 lost
+	for seq := uint64(17); seq <= 20; seq++ {
+		register(seq, monitor.Up, 250*time.Millisecond)
+	}
+	assert.Equal(t, monitor.Up, m.Status()) // 4 of 16 lost
+}
+
+func TestMonitorTimeBased(t *testing.T) {
+	m, err := monitor.NewTimeBased(3*time.Second, 2*time.Second)
+	assert.NoError(t, err)
+
+	tc := testCase{
+		{sequence: 0, probe: monitor.Pending, expected: monitor.Pending},
+		{sequence: 1, probe: monitor.Up, expected: monitor.Pending},
+		{sequence: 2, probe: monitor.Up, expected: monitor.Pending},
+		{sequence: 3, probe: monitor.Up, expected: monitor.Up},
+		{sequence: 4, probe: monitor.Down, expected: monitor.Pending},
+		{sequence: 5, probe: monitor.Down, expected: monitor.Pending},
+		{sequence: 6, probe: monitor.Down, expected: monitor.Down},
+		{sequence: 7, probe: monitor.Up, expected: monitor.Pending},
+		{sequence: 8, probe: monitor.Down, expected: monitor.Pending},
+		{sequence: 9, probe: monitor.Up, expected: monitor.Pending},
+		{sequence: 10, probe: monitor.Up, expected: monitor.Pending},
+		{sequence: 11, probe: monitor.Up, expected: monitor.Up},
+	}
+
+	run(t, m, tc)
+}
+
+func TestMonitorInvalid(t *testing.T) {
+	_, err := monitor.New(1, 2)
+	assert.Error(t, err)
+
+	_, err = monitor.NewLossRatio(10, 0, 0.2, 0.2)
+	assert.Error(t, err)
+
+	_, err = monitor.NewLossRatio(1, 0, 0.5, 0.2)
+	assert.Error(t, err)
+
+	_, err = monitor.NewTimeBased(0, time.Second)
+	assert.Error(t, err)
+}
+
+func TestAggregate(t *testing.T) {
+	const (
+		u = monitor.Up
+		d = monitor.Down
+		p = monitor.Pending
+	)
+
+	for idx, tc := range []struct {
+		statuses []monitor.Status
+		quorum   int
+		expected monitor.Status
+	}{
+		// all
+		{[]monitor.Status{u, u, u}, 3, u},
+		{[]monitor.Status{u, p, u}, 3, p},
+		{[]monitor.Status{u, d, u}, 3, d},
+		// any
+		{[]monitor.Status{d, p, u}, 1, u},
+		{[]monitor.Status{d, p, d}, 1, p},
+		{[]monitor.Status{d, d, d}, 1, d},
+		// quorum
+		{[]monitor.Status{u, u, d}, 2, u},
+		{[]monitor.Status{u, p, d}, 2, p},
+		{[]monitor.Status{d, p, d}, 2, d},
+		// single
+		{[]monitor.Status{u}, 1, u},
+		{[]monitor.Status{p}, 1, p},
+		{[]monitor.Status{d}, 1, d},
+	} {
+		assert.Equal(t, tc.expected, monitor.Aggregate(tc.statuses, tc.quorum), "case %d", idx)
 	}
 }
@@ -1,5 +1,10 @@
 package monitor
 
+import (
+	"errors"
+	"fmt"
+)
+
 type Status int8
 
 const (
@@ -8,6 +13,10 @@ const (
 	Up      Status = 1
 )
 
+var (
+	errStatusIsInvalid = errors.New("status is invalid")
+)
+
 func (s Status) String() string {
 	switch s {
 	case Down:
@@ -19,3 +28,23 @@ func (s Status) String() string {
 	}
 	return "N/A"
 }
+
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Status) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "DOWN":
+		*s = Down
+	case "PENDING":
+		*s = Pending
+	case "UP":
+		*s = Up
+	default:
+		return fmt.Errorf("%w: %s",
+			errStatusIsInvalid, string(text),
+		)
+	}
+	return nil
+}
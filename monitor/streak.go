@@ -0,0 +1,55 @@
+package monitor
+
+import "time"
+
+type streak struct {
+	dnThreshold, upThreshold int
+}
+
+func (p *streak) register(uint64, Status, time.Time) {}
+
+func (p *streak) status(m *Monitor) Status {
+	dnStreak := 0
+	unStreak := 0
+	upStreak := 0
+
+	window := min(len(m.history), max(p.dnThreshold, p.upThreshold))
+
+	firstState := true
+	for idx := len(m.history) - 1; idx >= len(m.history)-window; idx-- {
+		switch m.history[idx] {
+		case Up:
+			dnStreak = -1
+			unStreak = -1
+			if upStreak >= 0 {
+				upStreak++
+			}
+		case Down:
+			if dnStreak >= 0 {
+				dnStreak++
+			}
+			unStreak = -1
+			upStreak = -1
+		case Pending:
+			if dnStreak >= 0 {
+				dnStreak++ // sequence of unknown states also counts as down-streak
+			}
+			if unStreak >= 0 {
+				unStreak++
+			}
+			if firstState && upStreak >= 0 {
+				upStreak++ // first unknown state also counts as up-streak
+			}
+		}
+		firstState = false
+
+		if dnStreak >= p.dnThreshold && unStreak < dnStreak {
+			return Down
+		}
+		if upStreak >= p.upThreshold && unStreak < upStreak {
+			return Up
+		}
+	}
+
+	return Pending
+}
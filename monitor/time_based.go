@@ -0,0 +1,89 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type timeBased struct {
+	dnAfter, upAfter time.Duration
+
+	latest time.Time
+	lastUp time.Time
+	start  time.Time
+
+	upSince         time.Time
+	upSinceSequence uint64
+}
+
+var (
+	errDownAfterIsInvalid = errors.New("down-after duration is invalid")
+	errUpAfterIsInvalid   = errors.New("up-after duration is invalid")
+)
+
+// NewTimeBased returns the monitor that reports `down` status once there were
+// no `up` statuses for at least the down-after duration, and `up` status once
+// there were only `up` statuses for at least up-after duration.
+func NewTimeBased(downAfter, upAfter time.Duration) (*Monitor, error) {
+	if downAfter <= 0 {
+		return nil, fmt.Errorf("%w: expected > 0, got %s",
+			errDownAfterIsInvalid, downAfter,
+		)
+	}
+
+	if upAfter < 0 {
+		return nil, fmt.Errorf("%w: expected >= 0, got %s",
+			errUpAfterIsInvalid, upAfter,
+		)
+	}
+
+	return newMonitor(maxThreshold+1, &timeBased{
+		dnAfter: downAfter,
+		upAfter: upAfter,
+	}), nil
+}
+
+func (p *timeBased) register(sequence uint64, status Status, ts time.Time) {
+	if p.start.IsZero() {
+		p.start = ts
+	}
+	if ts.After(p.latest) {
+		p.latest = ts
+	}
+
+	switch status {
+	case Up:
+		if ts.After(p.lastUp) {
+			p.lastUp = ts
+		}
+		if p.upSince.IsZero() {
+			p.upSince = ts
+			p.upSinceSequence = sequence
+		}
+	case Down:
+		if !p.upSince.IsZero() && sequence >= p.upSinceSequence {
+			p.upSince = time.Time{}
+		}
+	}
+}
+
+func (p *timeBased) status(m *Monitor) Status {
+	if !m.started {
+		return Pending
+	}
+
+	if !p.upSince.IsZero() && p.latest.Sub(p.upSince) >= p.upAfter {
+		return Up
+	}
+
+	lastUp := p.lastUp
+	if lastUp.IsZero() {
+		lastUp = p.start
+	}
+	if p.latest.Sub(lastUp) >= p.dnAfter {
+		return Down
+	}
+
+	return Pending
+}
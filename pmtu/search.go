@@ -0,0 +1,47 @@
+package pmtu
+
+// Search looks for the largest packet size that makes it through the path.
+// It checks the max size first (since that is the common case), and then
+// bisects the range between the min and the max sizes.
+type Search struct {
+	min int
+	max int
+
+	good int // the largest size known to pass (0 if none yet)
+	bad  int // the smallest size known to fail
+}
+
+func NewSearch(min, max int) *Search {
+	return &Search{
+		min: min,
+		max: max,
+		bad: max + 1,
+	}
+}
+
+// Next returns the size to check next, or false once the search is complete.
+func (s *Search) Next() (int, bool) {
+	if s.good == 0 && s.bad > s.max {
+		return s.max, true
+	}
+
+	lo := max(s.good, s.min-1)
+	if s.bad-lo <= 1 {
+		return 0, false
+	}
+	return lo + (s.bad-lo)/2, true
+}
+
+// Register accounts for the outcome of the check of the size.
+func (s *Search) Register(size int, passed bool) {
+	if passed {
+		s.good = max(s.good, size)
+	} else {
+		s.bad = min(s.bad, size)
+	}
+}
+
+// Result returns the largest size that passed (or 0 if none did).
+func (s *Search) Result() int {
+	return s.good
+}
@@ -0,0 +1,32 @@
+package pmtu_test
+
+import (
+	"testing"
+
+	"github.com/flashbots/vpnham/pmtu"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	for _, tc := range []struct {
+		mtu      int
+		expected int
+		checks   int
+	}{
+		{mtu: 1500, expected: 1500, checks: 1},
+		{mtu: 9000, expected: 1500, checks: 1},
+		{mtu: 1420, expected: 1420, checks: 12},
+		{mtu: 1280, expected: 1280, checks: 12},
+		{mtu: 170, expected: 170, checks: 12},
+		{mtu: 100, expected: 0, checks: 12},
+	} {
+		s := pmtu.NewSearch(170, 1500)
+		checks := 0
+		for size, ok := s.Next(); ok; size, ok = s.Next() {
+			s.Register(size, size <= tc.mtu)
+			checks++
+		}
+		assert.Equal(t, tc.expected, s.Result(), "mtu %d", tc.mtu)
+		assert.LessOrEqual(t, checks, tc.checks, "mtu %d", tc.mtu)
+	}
+}
@@ -0,0 +1,67 @@
+package quality_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/quality"
+	"github.com/flashbots/vpnham/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScore(t *testing.T) {
+	weights := &config.TunnelSelectionWeights{
+		Loss:    1.0,
+		Latency: 0.2,
+		Jitter:  0.5,
+	}
+
+	assert.Equal(t, 100.0, quality.Score(types.PeerStats{}, weights))
+
+	assert.InDelta(t, 64.0, quality.Score(types.PeerStats{
+		LossRatio:   0.2,                   // -20
+		SmoothedRTT: 50 * time.Millisecond, // -10
+		Jitter:      12 * time.Millisecond, // -6
+	}, weights), 0.0001)
+
+	assert.Equal(t, 0.0, quality.Score(types.PeerStats{
+		LossRatio: 1.0,
+		Jitter:    time.Second,
+	}, weights))
+}
+
+func TestTracker(t *testing.T) {
+	tracker := quality.NewTracker(10, 30*time.Second)
+	start := time.Now()
+
+	type step struct {
+		offset   time.Duration
+		scores   map[string]float64
+		expected string
+	}
+
+	for idx, s := range []step{
+		{0, map[string]float64{"eth1": 90, "eth2": 95}, ""},                // within margin
+		{10 * time.Second, map[string]float64{"eth1": 70, "eth2": 95}, ""}, // degraded; hold time starts
+		{30 * time.Second, map[string]float64{"eth1": 70, "eth2": 95}, ""}, // still holding
+		{35 * time.Second, map[string]float64{"eth1": 90, "eth2": 95}, ""}, // recovered; hysteresis resets
+		{40 * time.Second, map[string]float64{"eth1": 70, "eth2": 95}, ""}, // degraded again; hold time restarts
+		{69 * time.Second, map[string]float64{"eth1": 70, "eth2": 95}, ""},
+		{70 * time.Second, map[string]float64{"eth1": 70, "eth2": 95}, "eth2"}, // promoted
+		{71 * time.Second, map[string]float64{"eth2": 95}, ""},                 // active is down
+	} {
+		assert.Equal(t, s.expected, tracker.Evaluate("eth1", s.scores, start.Add(s.offset)),
+			"step %d", idx,
+		)
+	}
+}
+
+func TestBest(t *testing.T) {
+	best, score := quality.Best(map[string]float64{"eth3": 80, "eth2": 80, "eth1": 90}, "eth1")
+	assert.Equal(t, "eth2", best)
+	assert.Equal(t, 80.0, score)
+
+	best, _ = quality.Best(map[string]float64{"eth1": 90}, "eth1")
+	assert.Equal(t, "", best)
+}
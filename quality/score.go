@@ -0,0 +1,23 @@
+package quality
+
+import (
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/types"
+)
+
+const (
+	MaxScore = 100.0
+	MinScore = 0.0
+)
+
+// Score rates the tunnel from its probe stats (the higher the better).
+func Score(stats types.PeerStats, weights *config.TunnelSelectionWeights) float64 {
+	score := MaxScore -
+		weights.Loss*100*stats.LossRatio -
+		weights.Latency*float64(stats.SmoothedRTT)/float64(time.Millisecond) -
+		weights.Jitter*float64(stats.Jitter)/float64(time.Millisecond)
+
+	return max(MinScore, min(MaxScore, score))
+}
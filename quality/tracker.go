@@ -0,0 +1,92 @@
+package quality
+
+import (
+	"slices"
+	"time"
+)
+
+// Tracker decides when the active tunnel should give way to a better one.
+//
+// The best candidate must outscore the active tunnel by more than the margin
+// for at least the hold time, so that small fluctuations do not cause the
+// tunnels to flap.  The clock restarts whenever another tunnel becomes the
+// best candidate, and is reset whenever the advantage falls to the margin or
+// below (or the active tunnel is not scored).
+type Tracker struct {
+	margin   float64
+	holdTime time.Duration
+
+	candidate string
+	since     time.Time
+}
+
+func NewTracker(margin float64, holdTime time.Duration) *Tracker {
+	return &Tracker{
+		margin:   margin,
+		holdTime: holdTime,
+	}
+}
+
+// Evaluate returns the name of the tunnel that should be promoted to be
+// active (or empty string if the active tunnel should stay).
+//
+// Only the tunnels present in the scores are considered (i.e. the caller is
+// expected to pass only the ones that are up).
+func (t *Tracker) Evaluate(active string, scores map[string]float64, ts time.Time) string {
+	activeScore, activeIsUp := scores[active]
+	if !activeIsUp {
+		t.Reset()
+		return ""
+	}
+
+	best, bestScore := Best(scores, active)
+	if best == "" || bestScore-activeScore <= t.margin {
+		t.Reset()
+		return ""
+	}
+
+	if best != t.candidate {
+		t.candidate = best
+		t.since = ts
+	}
+
+	if ts.Sub(t.since) < t.holdTime {
+		return ""
+	}
+
+	t.Reset()
+	return best
+}
+
+// Candidate returns the tunnel that is currently considered for promotion
+// together with the timestamp since when it is so.
+func (t *Tracker) Candidate() (string, time.Time) {
+	return t.candidate, t.since
+}
+
+func (t *Tracker) Reset() {
+	t.candidate = ""
+	t.since = time.Time{}
+}
+
+// Best returns the highest-scored tunnel (except the excluded ones).  The ties
+// are resolved by the tunnel names, so that the outcome is deterministic.
+func Best(scores map[string]float64, except ...string) (string, float64) {
+	names := make([]string, 0, len(scores))
+	for name := range scores {
+		if slices.Contains(except, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	best, bestScore := "", MinScore-1
+	for _, name := range names {
+		if scores[name] > bestScore {
+			best, bestScore = name, scores[name]
+		}
+	}
+
+	return best, bestScore
+}
@@ -48,7 +48,7 @@ func (r *Reconciler) bridgeActivateUpdateAWS(
 	aws := r.cfg.BridgeActivate.AWS
 
 	for _, vpc := range aws.Vpcs {
-		r.scheduleJob(&job.UpdateAWSRouteTables{
+		r.scheduleJob(ctx, &job.UpdateAWSRouteTables{
 			JobName: "aws_update_route_tables",
 			Timeout: aws.Timeout,
 
@@ -77,7 +77,7 @@ func (r *Reconciler) bridgeActivateUpdateGCP(
 
 		description := "Created by vpnham on " + time.Now().UTC().Format(time.RFC3339)
 
-		r.scheduleJob(&job.UpdateGCPRoute{
+		r.scheduleJob(ctx, &job.UpdateGCPRoute{
 			JobName: "gcp_update_route",
 			Timeout: gcp.Timeout,
 
@@ -116,7 +116,7 @@ func (r *Reconciler) bridgeActivateRunScript(
 		return
 	}
 
-	r.scheduleJob(&job.RunScript{
+	r.scheduleJob(ctx, &job.RunScript{
 		JobName: "bridge_activate",
 		Timeout: r.cfg.ScriptsTimeout,
 
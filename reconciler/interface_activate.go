@@ -54,7 +54,7 @@ func (r *Reconciler) interfaceActivateRunScript(
 		return
 	}
 
-	r.scheduleJob(&job.RunScript{
+	r.scheduleJob(ctx, &job.RunScript{
 		JobName: "interface_activate",
 		Timeout: r.cfg.ScriptsTimeout,
 		Script:  r.renderScript(&r.cfg.InterfaceActivate.Script, placeholders),
@@ -36,7 +36,7 @@ func (r *Reconciler) InterfaceDeactivate(
 		return
 	}
 
-	r.scheduleJob(&job.RunScript{
+	r.scheduleJob(ctx, &job.RunScript{
 		JobName: "interface_deactivate",
 		Timeout: r.cfg.ScriptsTimeout,
 		Script:  r.renderScript(&r.cfg.BridgeActivate.Script, placeholders),
@@ -6,13 +6,56 @@ import (
 
 	"github.com/flashbots/vpnham/job"
 	"github.com/flashbots/vpnham/logutils"
+	"github.com/flashbots/vpnham/metrics"
+	"github.com/flashbots/vpnham/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+type scheduledJob struct {
+	job job.Job
+
+	scheduledAt time.Time
+	startedAt   time.Time
+	spanContext trace.SpanContext
+}
+
+// PendingJob is the reconcile job that is queued or is being executed.
+type PendingJob struct {
+	Name        string     `json:"name"`
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+}
+
+// PendingJobs returns the job that is being executed (if any) followed by the
+// queued ones.
+func (r *Reconciler) PendingJobs() []PendingJob {
+	r.mxQueue.Lock()
+	defer r.mxQueue.Unlock()
+
+	res := make([]PendingJob, 0, len(r.queue)+1)
+	if r.running != nil {
+		startedAt := r.running.startedAt
+		res = append(res, PendingJob{
+			Name:        r.running.job.GetJobName(),
+			ScheduledAt: r.running.scheduledAt,
+			StartedAt:   &startedAt,
+		})
+	}
+	for _, scheduled := range r.queue {
+		res = append(res, PendingJob{
+			Name:        scheduled.job.GetJobName(),
+			ScheduledAt: scheduled.scheduledAt,
+		})
+	}
+	return res
+}
+
 func (r *Reconciler) runLoop(
 	ctx context.Context,
 ) {
-	exhaust := make(chan job.Job, 1)
+	exhaust := make(chan *scheduledJob, 1)
 
 	for {
 		select {
@@ -34,29 +77,66 @@ func (r *Reconciler) runLoop(
 }
 
 func (r *Reconciler) scheduleJob(
+	ctx context.Context,
 	job job.Job,
 ) {
 	r.mxQueue.Lock()
 	defer r.mxQueue.Unlock()
 
+	scheduled := &scheduledJob{
+		job: job,
+
+		scheduledAt: time.Now(),
+		spanContext: trace.SpanContextFromContext(ctx),
+	}
+
 	select {
-	case r.next <- job:
+	case r.next <- scheduled:
 		break
 	default:
-		r.queue = append(r.queue, job)
+		r.queue = append(r.queue, scheduled)
 	}
 }
 
 func (r *Reconciler) executeJob(
 	ctx context.Context,
-	job job.Job,
+	scheduled *scheduledJob,
 ) {
 	l := logutils.LoggerFromContext(ctx)
 
+	job := scheduled.job
+
+	// the job outlives the event that scheduled it, so it goes into its own
+	// trace (linked to the one of the event)
+	opts := []trace.SpanStartOption{
+		trace.WithNewRoot(),
+		trace.WithAttributes(
+			attribute.String(metrics.LabelBridge, r.name),
+			attribute.Int64("queued_us", time.Since(scheduled.scheduledAt).Microseconds()),
+		),
+	}
+	if scheduled.spanContext.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: scheduled.spanContext}))
+	}
+	ctx, span := tracing.Start(ctx, "job/"+job.GetJobName(), opts...)
+	defer span.End()
+
 	start := time.Now()
+
+	r.mxQueue.Lock()
+	scheduled.startedAt = start
+	r.running = scheduled
+	r.mxQueue.Unlock()
+
 	err := job.Execute(ctx)
 	duration := time.Since(start)
 
+	r.mxQueue.Lock()
+	r.running = nil
+	r.mxQueue.Unlock()
+
+	tracing.RecordError(span, err)
+
 	if err == nil {
 		l.Info("Executed job",
 			zap.Int64("duration_us", duration.Microseconds()),
@@ -69,4 +149,13 @@ func (r *Reconciler) executeJob(
 			zap.String("job_name", job.GetJobName()),
 		)
 	}
+
+	if r.journal != nil {
+		if err := r.journal.RecordJob(job.GetJobName(), start, duration, err); err != nil {
+			l.Error("Failed to record job outcome into the history",
+				zap.Error(err),
+				zap.String("job_name", job.GetJobName()),
+			)
+		}
+	}
 }
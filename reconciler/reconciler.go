@@ -5,31 +5,34 @@ import (
 	"sync"
 
 	"github.com/flashbots/vpnham/config"
-	"github.com/flashbots/vpnham/job"
+	"github.com/flashbots/vpnham/journal"
 	"github.com/flashbots/vpnham/logutils"
 )
 
 type Reconciler struct {
 	name string
 
-	cfg *config.Reconcile
+	cfg     *config.Reconcile
+	journal *journal.Journal
 
-	queue   []job.Job
+	queue   []*scheduledJob
+	running *scheduledJob
 	mxQueue sync.Mutex
 
-	next chan job.Job
+	next chan *scheduledJob
 	stop chan struct{}
 }
 
-func New(name string, cfg *config.Reconcile) (*Reconciler, error) {
+func New(name string, cfg *config.Reconcile, journal *journal.Journal) (*Reconciler, error) {
 	r := &Reconciler{
 		name: name,
 
-		cfg: cfg,
+		cfg:     cfg,
+		journal: journal,
 
-		queue: make([]job.Job, 0, 1),
+		queue: make([]*scheduledJob, 0, 1),
 
-		next: make(chan job.Job),
+		next: make(chan *scheduledJob),
 		stop: make(chan struct{}, 1),
 	}
 
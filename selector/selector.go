@@ -0,0 +1,142 @@
+package selector
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Tunnel is the snapshot of the tunnel interface state that the selection is
+// based upon.
+type Tunnel struct {
+	Name     string
+	Priority int
+	Score    float64
+
+	Active     bool
+	Up         bool
+	UpSince    time.Time
+	Suppressed bool
+
+	// FarEndActive indicates whether the bridge at the other end of the
+	// tunnel reports itself active.
+	FarEndActive bool
+}
+
+// Selector decides which of the tunnel interfaces should be active.
+type Selector struct {
+	QualityBased      bool
+	PreemptDelay      time.Duration
+	DisablePreemption bool
+
+	// PreferActiveFarEnd ranks the tunnels towards the active bridge on the
+	// other side above all the others (so that after asymmetric failovers
+	// the traffic goes where it is expected on the other side).
+	PreferActiveFarEnd bool
+}
+
+// Order returns the tunnels sorted from the most preferred to the least one.
+//
+// The tunnels are ordered by their priority (the higher the better), and the
+// ties are broken by the names.  With quality-based selection the scores take
+// precedence over the priorities.  With the preference for the active far
+// end, the tunnels towards it go first.
+func (s *Selector) Order(tunnels []Tunnel) []Tunnel {
+	res := slices.Clone(tunnels)
+	slices.SortStableFunc(res, func(a, b Tunnel) int {
+		if c := cmp.Compare(s.tier(b), s.tier(a)); c != 0 {
+			return c
+		}
+		if s.QualityBased {
+			if c := cmp.Compare(b.Score, a.Score); c != 0 {
+				return c
+			}
+		}
+		if c := cmp.Compare(b.Priority, a.Priority); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return res
+}
+
+// Failover returns the most preferred tunnel that is up (except the demoted
+// one), or empty string if there is none.  The suppressed tunnels are picked
+// only if there is no other choice.
+func (s *Selector) Failover(tunnels []Tunnel, demoted string) string {
+	fallback := ""
+	for _, t := range s.Order(tunnels) {
+		if !t.Up || t.Name == demoted {
+			continue
+		}
+		if !t.Suppressed {
+			return t.Name
+		}
+		if fallback == "" {
+			fallback = t.Name
+		}
+	}
+	return fallback
+}
+
+// Preemption returns the tunnel that should take over the active status at
+// the moment, or empty string if the active tunnel should stay.
+//
+//   - If there is no active tunnel, the most preferred one that is up is
+//     returned right away.
+//
+//   - Otherwise (unless the preemption is disabled), the up tunnel with the
+//     highest priority is returned (but only if its priority is higher than
+//     that of the active one, and it has been up for at least the preempt
+//     delay).  With quality-based selection, the preempting tunnel must not
+//     score worse than the active one.  Suppressed tunnels never preempt.
+//
+//   - With the preference for the active far end, the tunnel towards it
+//     preempts the one that is not (regardless of the priorities), and the
+//     one that is not never preempts the one that is.
+func (s *Selector) Preemption(tunnels []Tunnel, ts time.Time) string {
+	idx := slices.IndexFunc(tunnels, func(t Tunnel) bool {
+		return t.Active
+	})
+	if idx == -1 {
+		return s.Failover(tunnels, "")
+	}
+	if s.DisablePreemption {
+		return ""
+	}
+	active := tunnels[idx]
+
+	byPriority := &Selector{PreferActiveFarEnd: s.PreferActiveFarEnd}
+	for _, t := range byPriority.Order(tunnels) {
+		if !s.Outranks(t, active) {
+			return ""
+		}
+		if !t.Up || t.Suppressed || ts.Sub(t.UpSince) < s.PreemptDelay {
+			continue
+		}
+		if s.QualityBased && t.Score < active.Score && s.tier(t) == s.tier(active) {
+			continue
+		}
+		return t.Name
+	}
+
+	return ""
+}
+
+// Outranks returns true if the tunnel a should preempt the tunnel b (once it
+// has been up for long enough).
+func (s *Selector) Outranks(a, b Tunnel) bool {
+	if c := cmp.Compare(s.tier(a), s.tier(b)); c != 0 {
+		return c > 0
+	}
+	return a.Priority > b.Priority
+}
+
+// tier puts the tunnels towards the active far end (if preferred) above the
+// rest.
+func (s *Selector) tier(t Tunnel) int {
+	if s.PreferActiveFarEnd && t.FarEndActive {
+		return 1
+	}
+	return 0
+}
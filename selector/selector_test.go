@@ -0,0 +1,169 @@
+package selector_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/selector"
+	"github.com/stretchr/testify/assert"
+)
+
+func names(tunnels []selector.Tunnel) []string {
+	res := make([]string, 0, len(tunnels))
+	for _, t := range tunnels {
+		res = append(res, t.Name)
+	}
+	return res
+}
+
+func TestOrder(t *testing.T) {
+	tunnels := []selector.Tunnel{
+		{Name: "eth4", Priority: 10, Score: 90},
+		{Name: "eth3", Priority: 50, Score: 80},
+		{Name: "eth2", Priority: 50, Score: 95},
+		{Name: "eth1", Priority: 100, Score: 70},
+	}
+
+	byPriority := &selector.Selector{}
+	assert.Equal(t, []string{"eth1", "eth2", "eth3", "eth4"}, names(byPriority.Order(tunnels)))
+
+	byQuality := &selector.Selector{QualityBased: true}
+	assert.Equal(t, []string{"eth2", "eth4", "eth3", "eth1"}, names(byQuality.Order(tunnels)))
+}
+
+func TestFailover(t *testing.T) {
+	s := &selector.Selector{}
+
+	tunnels := []selector.Tunnel{
+		{Name: "eth1", Priority: 100, Up: false, Active: true},
+		{Name: "eth2", Priority: 50, Up: false},
+		{Name: "eth3", Priority: 30, Up: true},
+		{Name: "eth4", Priority: 30, Up: true},
+	}
+	assert.Equal(t, "eth3", s.Failover(tunnels, "eth1"))
+	assert.Equal(t, "eth4", s.Failover(tunnels, "eth3"))
+
+	tunnels[1].Up = true
+	assert.Equal(t, "eth2", s.Failover(tunnels, "eth1"))
+
+	for idx := range tunnels {
+		tunnels[idx].Up = false
+	}
+	assert.Equal(t, "", s.Failover(tunnels, "eth1"))
+}
+
+func TestPreemption(t *testing.T) {
+	ts := time.Now()
+	s := &selector.Selector{PreemptDelay: 30 * time.Second}
+
+	{ // no active tunnel => activate the most preferred one immediately
+		tunnels := []selector.Tunnel{
+			{Name: "eth1", Priority: 100, Up: false},
+			{Name: "eth2", Priority: 50, Up: true, UpSince: ts},
+			{Name: "eth3", Priority: 30, Up: true, UpSince: ts},
+		}
+		assert.Equal(t, "eth2", s.Preemption(tunnels, ts))
+	}
+
+	{ // higher priority tunnel is up, but not long enough
+		tunnels := []selector.Tunnel{
+			{Name: "eth1", Priority: 100, Up: true, UpSince: ts.Add(-10 * time.Second)},
+			{Name: "eth2", Priority: 50, Up: true, UpSince: ts.Add(-time.Hour), Active: true},
+		}
+		assert.Equal(t, "", s.Preemption(tunnels, ts))
+		assert.Equal(t, "eth1", s.Preemption(tunnels, ts.Add(20*time.Second)))
+	}
+
+	{ // lower priority tunnels never preempt
+		tunnels := []selector.Tunnel{
+			{Name: "eth1", Priority: 100, Up: false},
+			{Name: "eth2", Priority: 50, Up: true, UpSince: ts.Add(-time.Hour), Active: true},
+			{Name: "eth3", Priority: 30, Up: true, UpSince: ts.Add(-time.Hour)},
+		}
+		assert.Equal(t, "", s.Preemption(tunnels, ts))
+	}
+
+	{ // the highest priority tunnel that is ready wins
+		tunnels := []selector.Tunnel{
+			{Name: "eth1", Priority: 100, Up: true, UpSince: ts},
+			{Name: "eth2", Priority: 70, Up: true, UpSince: ts.Add(-time.Hour)},
+			{Name: "eth3", Priority: 50, Up: true, UpSince: ts.Add(-time.Hour), Active: true},
+		}
+		assert.Equal(t, "eth2", s.Preemption(tunnels, ts))
+	}
+
+	{ // disabled preemption
+		s := &selector.Selector{DisablePreemption: true}
+		tunnels := []selector.Tunnel{
+			{Name: "eth1", Priority: 100, Up: true, UpSince: ts.Add(-time.Hour)},
+			{Name: "eth2", Priority: 50, Up: true, UpSince: ts.Add(-time.Hour), Active: true},
+		}
+		assert.Equal(t, "", s.Preemption(tunnels, ts))
+
+		tunnels[1].Active = false
+		assert.Equal(t, "eth1", s.Preemption(tunnels, ts))
+	}
+
+	{ // quality-based selection does not preempt with a worse tunnel
+		s := &selector.Selector{QualityBased: true}
+		tunnels := []selector.Tunnel{
+			{Name: "eth1", Priority: 100, Score: 60, Up: true, UpSince: ts},
+			{Name: "eth2", Priority: 50, Score: 90, Up: true, UpSince: ts, Active: true},
+		}
+		assert.Equal(t, "", s.Preemption(tunnels, ts))
+
+		tunnels[0].Score = 90
+		assert.Equal(t, "eth1", s.Preemption(tunnels, ts))
+	}
+}
+
+func TestSuppressed(t *testing.T) {
+	s := &selector.Selector{}
+	ts := time.Unix(1000, 0)
+
+	tunnels := []selector.Tunnel{
+		{Name: "eth1", Priority: 100, Up: true, UpSince: time.Unix(0, 0), Suppressed: true},
+		{Name: "eth2", Priority: 50, Up: true, UpSince: time.Unix(0, 0), Active: true},
+		{Name: "eth3", Priority: 30, Up: true, UpSince: time.Unix(0, 0)},
+	}
+
+	assert.Equal(t, "", s.Preemption(tunnels, ts))
+	assert.Equal(t, "eth3", s.Failover(tunnels, "eth2"))
+
+	tunnels[2].Up = false
+	assert.Equal(t, "eth1", s.Failover(tunnels, "eth2"))
+}
+
+func TestPreferActiveFarEnd(t *testing.T) {
+	ts := time.Unix(1000, 0)
+
+	tunnels := []selector.Tunnel{
+		{Name: "eth1", Priority: 100, Score: 90, Up: true, UpSince: time.Unix(0, 0), Active: true},
+		{Name: "eth2", Priority: 50, Score: 80, Up: true, UpSince: time.Unix(0, 0), FarEndActive: true},
+		{Name: "eth3", Priority: 30, Score: 70, Up: true, UpSince: time.Unix(0, 0)},
+	}
+
+	{ // not preferred => far ends do not matter
+		s := &selector.Selector{}
+		assert.Equal(t, []string{"eth1", "eth2", "eth3"}, names(s.Order(tunnels)))
+		assert.Equal(t, "", s.Preemption(tunnels, ts))
+	}
+
+	{ // preferred => tunnel towards the active far end takes over
+		s := &selector.Selector{PreferActiveFarEnd: true, QualityBased: true}
+		assert.Equal(t, []string{"eth2", "eth1", "eth3"}, names(s.Order(tunnels)))
+		assert.Equal(t, "eth2", s.Preemption(tunnels, ts))
+		assert.Equal(t, "eth2", s.Failover(tunnels, "eth1"))
+		assert.True(t, s.Outranks(tunnels[1], tunnels[0]))
+		assert.False(t, s.Outranks(tunnels[0], tunnels[1]))
+	}
+
+	{ // ...but only once it has been up for long enough
+		s := &selector.Selector{PreferActiveFarEnd: true, PreemptDelay: time.Minute}
+		tunnels := slices.Clone(tunnels)
+		tunnels[1].UpSince = ts
+		assert.Equal(t, "", s.Preemption(tunnels, ts))
+		assert.Equal(t, "eth2", s.Preemption(tunnels, ts.Add(time.Minute)))
+	}
+}
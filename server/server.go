@@ -12,6 +12,7 @@ import (
 	"github.com/flashbots/vpnham/config"
 	"github.com/flashbots/vpnham/logutils"
 	"github.com/flashbots/vpnham/metrics"
+	"github.com/flashbots/vpnham/tracing"
 	"go.opentelemetry.io/otel/attribute"
 	otelapi "go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
@@ -29,6 +30,10 @@ func New(cfg *config.Config) (*Server, error) {
 	l := zap.L()
 	ctx := logutils.ContextWithLogger(context.Background(), l)
 
+	if err := tracing.Setup(ctx, cfg.Server.Tracing, cfg.Version); err != nil {
+		return nil, err
+	}
+
 	bridges := make(map[string]*bridge.Server, len(cfg.Server.Bridges))
 	for bn, b := range cfg.Server.Bridges {
 		bs, err := bridge.NewServer(ctx, b)
@@ -106,6 +111,18 @@ func (s *Server) Run() error {
 	}
 	s.metrics.Stop(ctx)
 
+	if err := metrics.Shutdown(ctx); err != nil {
+		l.Error("Failed to flush the metrics",
+			zap.Error(err),
+		)
+	}
+
+	if err := tracing.Shutdown(ctx); err != nil {
+		l.Error("Failed to flush the traces",
+			zap.Error(err),
+		)
+	}
+
 	switch len(errs) {
 	default:
 		return errors.Join(errs...)
@@ -0,0 +1,158 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/types"
+)
+
+// Signer signs our status documents and verifies the signatures of the
+// partner's ones.
+type Signer struct {
+	algorithm string
+
+	privateKey ed25519.PrivateKey
+	partnerKey ed25519.PublicKey
+
+	secret []byte
+}
+
+const domain = "vpnham-bridge-status-v1"
+
+var (
+	errKeyIsInvalid       = errors.New("signing key is invalid")
+	errSignatureIsInvalid = errors.New("status signature is invalid")
+	errSignatureIsMissing = errors.New("status signature is missing")
+)
+
+func New(cfg *config.StatusSigning) (*Signer, error) {
+	s := &Signer{
+		algorithm: cfg.Algorithm,
+	}
+
+	switch cfg.Algorithm {
+	case config.StatusSigningAlgorithmEd25519:
+		privateKey, err := readKey(cfg.PrivateKey, x509.ParsePKCS8PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if s.privateKey, ok = privateKey.(ed25519.PrivateKey); !ok {
+			return nil, fmt.Errorf("%w: not an ed25519 private key: %s",
+				errKeyIsInvalid, cfg.PrivateKey,
+			)
+		}
+
+		partnerKey, err := readKey(cfg.PartnerPublicKey, x509.ParsePKIXPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		if s.partnerKey, ok = partnerKey.(ed25519.PublicKey); !ok {
+			return nil, fmt.Errorf("%w: not an ed25519 public key: %s",
+				errKeyIsInvalid, cfg.PartnerPublicKey,
+			)
+		}
+
+	case config.StatusSigningAlgorithmHMACSHA256:
+		secret, err := os.ReadFile(cfg.Secret)
+		if err != nil {
+			return nil, err
+		}
+		s.secret = bytes.TrimSpace(secret)
+		if len(s.secret) == 0 {
+			return nil, fmt.Errorf("%w: empty secret: %s",
+				errKeyIsInvalid, cfg.Secret,
+			)
+		}
+	}
+
+	return s, nil
+}
+
+// Sign fills in the signature of the document.
+func (s *Signer) Sign(doc *types.SignedBridgeStatus) {
+	message := digest(doc)
+
+	switch s.algorithm {
+	case config.StatusSigningAlgorithmEd25519:
+		doc.Signature = ed25519.Sign(s.privateKey, message)
+	case config.StatusSigningAlgorithmHMACSHA256:
+		mac := hmac.New(sha256.New, s.secret)
+		mac.Write(message)
+		doc.Signature = mac.Sum(nil)
+	}
+}
+
+// Verify checks that the document was signed by the partner.
+func (s *Signer) Verify(doc *types.SignedBridgeStatus) error {
+	if len(doc.Signature) == 0 {
+		return errSignatureIsMissing
+	}
+
+	message := digest(doc)
+
+	valid := false
+	switch s.algorithm {
+	case config.StatusSigningAlgorithmEd25519:
+		valid = ed25519.Verify(s.partnerKey, message, doc.Signature)
+	case config.StatusSigningAlgorithmHMACSHA256:
+		mac := hmac.New(sha256.New, s.secret)
+		mac.Write(message)
+		valid = hmac.Equal(mac.Sum(nil), doc.Signature)
+	}
+
+	if !valid {
+		return errSignatureIsInvalid
+	}
+
+	return nil
+}
+
+// digest is the message that is signed (every variable-length field is
+// prefixed with its length, so that the fields can't be shifted around).
+func digest(doc *types.SignedBridgeStatus) []byte {
+	b := make([]byte, 0, len(domain)+8+8+4+len(doc.Nonce)+4+len(doc.Status))
+
+	b = append(b, domain...)
+	b = binary.BigEndian.AppendUint64(b, uint64(doc.Timestamp.UnixNano()))
+	b = binary.BigEndian.AppendUint64(b, doc.Sequence)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(doc.Nonce)))
+	b = append(b, doc.Nonce...)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(doc.Status)))
+	b = append(b, doc.Status...)
+
+	return b
+}
+
+func readKey(file string, parse func([]byte) (any, error)) (any, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no pem data: %s",
+			errKeyIsInvalid, file,
+		)
+	}
+
+	key, err := parse(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w",
+			errKeyIsInvalid, file, err,
+		)
+	}
+
+	return key, nil
+}
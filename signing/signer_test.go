@@ -0,0 +1,105 @@
+package signing_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/config"
+	"github.com/flashbots/vpnham/signing"
+	"github.com/flashbots/vpnham/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKeys(t *testing.T, dir, name string) (string, string) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+
+	privateFile := filepath.Join(dir, name+".key")
+	publicFile := filepath.Join(dir, name+".pub")
+	require.NoError(t, os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600))
+	require.NoError(t, os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600))
+
+	return privateFile, publicFile
+}
+
+func newDoc() *types.SignedBridgeStatus {
+	return &types.SignedBridgeStatus{
+		Status:    []byte(`{"name":"vpnham-dev","active":true}`),
+		Timestamp: time.Now(),
+		Sequence:  42,
+		Nonce:     "c0ffee",
+	}
+}
+
+func TestSigner(t *testing.T) {
+	dir := t.TempDir()
+
+	secret := filepath.Join(dir, "secret")
+	require.NoError(t, os.WriteFile(secret, []byte("s3cr3t\n"), 0o600))
+
+	lftPrivate, lftPublic := writeKeys(t, dir, "lft")
+	rgtPrivate, rgtPublic := writeKeys(t, dir, "rgt")
+
+	for name, pair := range map[string][2]*config.StatusSigning{
+		"ed25519": {
+			{Algorithm: config.StatusSigningAlgorithmEd25519, PrivateKey: lftPrivate, PartnerPublicKey: rgtPublic},
+			{Algorithm: config.StatusSigningAlgorithmEd25519, PrivateKey: rgtPrivate, PartnerPublicKey: lftPublic},
+		},
+		"hmac-sha256": {
+			{Algorithm: config.StatusSigningAlgorithmHMACSHA256, Secret: secret},
+			{Algorithm: config.StatusSigningAlgorithmHMACSHA256, Secret: secret},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			lft, err := signing.New(pair[0])
+			require.NoError(t, err)
+			rgt, err := signing.New(pair[1])
+			require.NoError(t, err)
+
+			doc := newDoc()
+			assert.Error(t, rgt.Verify(doc), "unsigned")
+
+			lft.Sign(doc)
+			assert.NoError(t, rgt.Verify(doc))
+
+			tampered := *doc
+			tampered.Status = []byte(`{"name":"vpnham-dev","active":false}`)
+			assert.Error(t, rgt.Verify(&tampered), "tampered status")
+
+			tampered = *doc
+			tampered.Nonce = "deadbeef"
+			assert.Error(t, rgt.Verify(&tampered), "tampered nonce")
+
+			tampered = *doc
+			tampered.Timestamp = doc.Timestamp.Add(time.Minute)
+			assert.Error(t, rgt.Verify(&tampered), "tampered timestamp")
+
+			tampered = *doc
+			tampered.Sequence++
+			assert.Error(t, rgt.Verify(&tampered), "tampered sequence")
+		})
+	}
+
+	{ // signed by someone else
+		lft, err := signing.New(&config.StatusSigning{
+			Algorithm: config.StatusSigningAlgorithmEd25519, PrivateKey: lftPrivate, PartnerPublicKey: rgtPublic,
+		})
+		require.NoError(t, err)
+
+		doc := newDoc()
+		lft.Sign(doc)
+		assert.Error(t, lft.Verify(doc))
+	}
+}
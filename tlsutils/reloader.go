@@ -0,0 +1,215 @@
+package tlsutils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/flashbots/vpnham/logutils"
+	"go.uber.org/zap"
+)
+
+// Reloader keeps the certificate and the (pinned) ca up-to-date with the
+// files on disk, so that they can be rotated without a restart.  The tls
+// configs it produces always use the latest ones.
+type Reloader struct {
+	ca   string
+	cert string
+	key  string
+
+	interval time.Duration
+
+	mx          sync.RWMutex
+	certificate *tls.Certificate
+	pool        *x509.CertPool
+	modified    time.Time
+
+	done    chan struct{}
+	once    sync.Once
+	running sync.WaitGroup
+}
+
+var (
+	errCAIsInvalid                = errors.New("tls ca is invalid")
+	errPeerCertificateIsMissing   = errors.New("peer did not present tls certificate")
+	errPeerCertificateIsUntrusted = errors.New("peer tls certificate is untrusted")
+)
+
+func NewReloader(ca, cert, key string, interval time.Duration) (*Reloader, error) {
+	r := &Reloader{
+		ca:   ca,
+		cert: cert,
+		key:  key,
+
+		interval: interval,
+
+		done: make(chan struct{}),
+	}
+
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *Reloader) Run(ctx context.Context) {
+	l := logutils.LoggerFromContext(ctx)
+
+	r.running.Add(1)
+	go func() {
+		defer r.running.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.done:
+				return
+			case <-ticker.C:
+			}
+
+			reloaded, err := r.reload()
+			if err != nil {
+				// keep on using the previous ones
+				l.Error("Failed to reload tls certificates",
+					zap.Error(err),
+					zap.String("tls_cert", r.cert),
+				)
+				continue
+			}
+			if reloaded {
+				l.Info("Reloaded tls certificates",
+					zap.String("tls_cert", r.cert),
+				)
+			}
+		}
+	}()
+}
+
+func (r *Reloader) Stop(_ context.Context) {
+	r.once.Do(func() {
+		close(r.done)
+		r.running.Wait()
+	})
+}
+
+// ServerConfig returns the tls config for the server that requires the
+// clients to present the certificates signed by the pinned ca.
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientAuth: tls.RequireAnyClientCert, // verified against the latest ca below
+
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.getCertificate(), nil
+		},
+
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			return r.verify(cs, "", x509.ExtKeyUsageClientAuth)
+		},
+	}
+}
+
+// ClientConfig returns the tls config for the client that presents its
+// certificate and requires the server's certificate to be signed by the
+// pinned ca (and to be issued for the server name).
+func (r *Reloader) ClientConfig(serverName string) *tls.Config {
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: true, // verified against the latest ca below
+
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.getCertificate(), nil
+		},
+
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			return r.verify(cs, serverName, x509.ExtKeyUsageServerAuth)
+		},
+	}
+}
+
+func (r *Reloader) getCertificate() *tls.Certificate {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	return r.certificate
+}
+
+func (r *Reloader) verify(cs tls.ConnectionState, name string, usage x509.ExtKeyUsage) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errPeerCertificateIsMissing
+	}
+
+	r.mx.RLock()
+	pool := r.pool
+	r.mx.RUnlock()
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       name,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+		Roots:         pool,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", errPeerCertificateIsUntrusted, err)
+	}
+
+	return nil
+}
+
+// reload re-reads the files if any of them changed since the last time, and
+// returns true if it did.
+func (r *Reloader) reload() (bool, error) {
+	modified := time.Time{}
+	for _, file := range []string{r.ca, r.cert, r.key} {
+		fi, err := os.Stat(file)
+		if err != nil {
+			return false, err
+		}
+		if fi.ModTime().After(modified) {
+			modified = fi.ModTime()
+		}
+	}
+
+	r.mx.RLock()
+	unchanged := modified.Equal(r.modified)
+	r.mx.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	pem, err := os.ReadFile(r.ca)
+	if err != nil {
+		return false, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return false, fmt.Errorf("%w: %s", errCAIsInvalid, r.ca)
+	}
+
+	certificate, err := tls.LoadX509KeyPair(r.cert, r.key)
+	if err != nil {
+		return false, err
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.certificate = &certificate
+	r.pool = pool
+	r.modified = modified
+
+	return true, nil
+}
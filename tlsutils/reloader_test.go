@@ -0,0 +1,146 @@
+package tlsutils_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/tlsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pki struct {
+	ca    *x509.Certificate
+	caKey *ecdsa.PrivateKey
+	caPEM []byte
+}
+
+func newPKI(t *testing.T) *pki {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "vpnham-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &pki{
+		ca:    ca,
+		caKey: key,
+		caPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue writes the ca, and the certificate (usable both by the server and by
+// the client) with its key into the directory.
+func (p *pki) issue(t *testing.T, dir, name string) (string, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, p.ca, &key.PublicKey, p.caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	ca := filepath.Join(dir, "ca.pem")
+	cert := filepath.Join(dir, "cert.pem")
+	certKey := filepath.Join(dir, "key.pem")
+
+	require.NoError(t, os.WriteFile(ca, p.caPEM, 0o600))
+	require.NoError(t, os.WriteFile(cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(certKey, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return ca, cert, certKey
+}
+
+func get(cfg *tls.Config, url string) error {
+	cli := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: cfg},
+		Timeout:   time.Second,
+	}
+	res, err := cli.Get(url)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func TestReloader(t *testing.T) {
+	ctx := context.Background()
+
+	serverDir, clientDir := t.TempDir(), t.TempDir()
+
+	p := newPKI(t)
+
+	ca, cert, key := p.issue(t, serverDir, "server")
+	server, err := tlsutils.NewReloader(ca, cert, key, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	ca, cert, key = p.issue(t, clientDir, "client")
+	client, err := tlsutils.NewReloader(ca, cert, key, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	srv.Listener = tls.NewListener(srv.Listener, server.ServerConfig())
+	srv.Start()
+	defer srv.Close()
+	url := strings.Replace(srv.URL, "http://", "https://", 1)
+
+	{ // mutual tls
+		assert.NoError(t, get(client.ClientConfig("server"), url))
+	}
+
+	{ // wrong server name
+		assert.Error(t, get(client.ClientConfig("partner"), url))
+	}
+
+	{ // no client certificate
+		cfg := client.ClientConfig("server")
+		cfg.GetClientCertificate = nil
+		assert.Error(t, get(cfg, url))
+	}
+
+	{ // server rotates to another ca
+		server.Run(ctx)
+		defer server.Stop(ctx)
+
+		p = newPKI(t)
+		p.issue(t, serverDir, "server")
+
+		assert.Eventually(t, func() bool {
+			return get(client.ClientConfig("server"), url) != nil
+		}, time.Second, 10*time.Millisecond)
+	}
+}
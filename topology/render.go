@@ -0,0 +1,201 @@
+package topology
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/flashbots/vpnham/types"
+)
+
+const (
+	FormatDOT     = "dot"
+	FormatJSON    = "json"
+	FormatMermaid = "mermaid"
+)
+
+var (
+	errFormatIsUnknown = errors.New("unknown topology format (expected: json, dot, or mermaid)")
+)
+
+// Render renders the topology in the format, and returns it together with
+// its content type.
+func Render(t *types.Topology, format string) ([]byte, string, error) {
+	switch format {
+	case FormatDOT:
+		return DOT(t), "text/vnd.graphviz", nil
+	case FormatJSON, "":
+		b, err := json.Marshal(t)
+		if err != nil {
+			return nil, "", err
+		}
+		return append(b, '\n'), "application/json", nil
+	case FormatMermaid:
+		return Mermaid(t), "text/plain", nil
+	default:
+		return nil, "", fmt.Errorf("%w: %s",
+			errFormatIsUnknown, format,
+		)
+	}
+}
+
+// DOT renders the topology as the graphviz graph.
+func DOT(t *types.Topology) []byte {
+	b := &bytes.Buffer{}
+
+	fmt.Fprintln(b, "graph vpnham {")
+	fmt.Fprintln(b, "  rankdir=LR;")
+	fmt.Fprintln(b, "  node [shape=box];")
+
+	for _, side := range []string{types.TopologySideLocal, types.TopologySideRemote} {
+		fmt.Fprintf(b, "  subgraph cluster_%s {\n", side)
+		fmt.Fprintf(b, "    label=\"%s\";\n", side)
+		for _, n := range t.Nodes {
+			if n.Side != side {
+				continue
+			}
+			attrs := []string{fmt.Sprintf("label=\"%s\"", dotEscape(nodeLabel(n, "\\n")))}
+			if n.Active {
+				attrs = append(attrs, "style=filled", "fillcolor=palegreen")
+			}
+			if n.Side == types.TopologySideLocal && !n.Up {
+				attrs = append(attrs, "color=red")
+			}
+			fmt.Fprintf(b, "    \"%s\" [%s];\n", dotEscape(n.Location), strings.Join(attrs, ", "))
+		}
+		fmt.Fprintln(b, "  }")
+	}
+
+	for _, l := range t.Links {
+		to := l.To
+		if to == "" {
+			to = unknownNode(l)
+			fmt.Fprintf(b, "  \"%s\" [label=\"?\", style=dashed];\n", dotEscape(to))
+		}
+
+		attrs := []string{fmt.Sprintf("label=\"%s\"", dotEscape(linkLabel(l, "\\n")))}
+		switch {
+		case l.Kind == types.TopologyLinkPartner:
+			attrs = append(attrs, "style=dotted")
+			if !l.Up {
+				attrs = append(attrs, "color=red")
+			}
+		case l.Up && l.Active:
+			attrs = append(attrs, "color=darkgreen", "penwidth=3")
+		case !l.Up:
+			attrs = append(attrs, "color=red", "style=dashed")
+		}
+		fmt.Fprintf(b, "  \"%s\" -- \"%s\" [%s];\n", dotEscape(l.From), dotEscape(to), strings.Join(attrs, ", "))
+	}
+
+	fmt.Fprintln(b, "}")
+
+	return b.Bytes()
+}
+
+// Mermaid renders the topology as the mermaid flowchart.
+func Mermaid(t *types.Topology) []byte {
+	b := &bytes.Buffer{}
+
+	ids := make(map[string]string, len(t.Nodes))
+	id := func(location string) string {
+		if _, known := ids[location]; !known {
+			ids[location] = fmt.Sprintf("n%d", len(ids))
+		}
+		return ids[location]
+	}
+
+	fmt.Fprintln(b, "graph LR")
+
+	active := make([]string, 0, len(t.Nodes))
+	for _, side := range []string{types.TopologySideLocal, types.TopologySideRemote} {
+		fmt.Fprintf(b, "  subgraph %s\n", side)
+		for _, n := range t.Nodes {
+			if n.Side != side {
+				continue
+			}
+			fmt.Fprintf(b, "    %s[\"%s\"]\n", id(n.Location), mermaidEscape(nodeLabel(n, "<br/>")))
+			if n.Active {
+				active = append(active, id(n.Location))
+			}
+		}
+		fmt.Fprintln(b, "  end")
+	}
+
+	down := make([]string, 0, len(t.Links))
+	for idx, l := range t.Links {
+		to := ""
+		if l.To != "" {
+			to = id(l.To)
+		} else {
+			to = id(unknownNode(l))
+			fmt.Fprintf(b, "  %s[\"?\"]\n", to)
+		}
+
+		arrow := "---"
+		switch {
+		case l.Kind == types.TopologyLinkPartner:
+			arrow = "-.-"
+		case l.Up && l.Active:
+			arrow = "==="
+		}
+		fmt.Fprintf(b, "  %s %s|\"%s\"| %s\n", id(l.From), arrow, mermaidEscape(linkLabel(l, "<br/>")), to)
+		if !l.Up {
+			down = append(down, fmt.Sprintf("%d", idx))
+		}
+	}
+
+	if len(active) > 0 {
+		fmt.Fprintln(b, "  classDef active fill:#9f9")
+		fmt.Fprintf(b, "  class %s active\n", strings.Join(active, ","))
+	}
+	if len(down) > 0 {
+		fmt.Fprintf(b, "  linkStyle %s stroke:red,stroke-dasharray:4\n", strings.Join(down, ","))
+	}
+
+	return b.Bytes()
+}
+
+func nodeLabel(n *types.TopologyNode, newline string) string {
+	label := n.Location
+	if n.Role != "" {
+		label += newline + "role: " + string(n.Role)
+	}
+	switch {
+	case n.Side == types.TopologySideLocal && !n.Up:
+		label += newline + "down"
+	case n.Active && n.ActiveInterface != "":
+		label += newline + "active (" + n.ActiveInterface + ")"
+	case n.Active:
+		label += newline + "active"
+	}
+	return label
+}
+
+func linkLabel(l *types.TopologyLink, newline string) string {
+	if l.Kind == types.TopologyLinkPartner {
+		return l.Kind
+	}
+	label := l.Interface
+	if !l.Up {
+		return label + newline + "down"
+	}
+	rtt := (time.Duration(l.RTTUs) * time.Microsecond).Round(10 * time.Microsecond)
+	return fmt.Sprintf("%s%s%s, %.1f%% loss", label, newline, rtt, 100*l.LossRatio)
+}
+
+// unknownNode is the placeholder for the far end that did not report itself.
+func unknownNode(l *types.TopologyLink) string {
+	return l.From + "/" + l.Interface + "/?"
+}
+
+func dotEscape(str string) string {
+	return strings.ReplaceAll(str, `"`, `\"`)
+}
+
+func mermaidEscape(str string) string {
+	return strings.ReplaceAll(str, `"`, "#quot;")
+}
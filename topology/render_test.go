@@ -0,0 +1,57 @@
+package topology_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/flashbots/vpnham/topology"
+	"github.com/flashbots/vpnham/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTopology() *types.Topology {
+	return &types.Topology{
+		Nodes: []*types.TopologyNode{
+			{Location: "left/active", Side: types.TopologySideLocal, Role: types.RoleActive, Up: true, Active: true, ActiveInterface: "wg0"},
+			{Location: "left/standby", Side: types.TopologySideLocal, Role: types.RoleStandby, Up: true},
+			{Location: "right/active", Side: types.TopologySideRemote, Active: true, ActiveInterface: "wg0"},
+		},
+		Links: []*types.TopologyLink{
+			{Kind: types.TopologyLinkTunnel, From: "left/active", Interface: "wg0", To: "right/active", Up: true, Active: true, RTTUs: 1234, LossRatio: 0.01},
+			{Kind: types.TopologyLinkTunnel, From: "left/active", Interface: "wg1"},
+			{Kind: types.TopologyLinkPartner, From: "left/active", To: "left/standby", Up: true},
+		},
+	}
+}
+
+func TestDOT(t *testing.T) {
+	dot := string(topology.DOT(testTopology()))
+
+	assert.Contains(t, dot, `"left/active" [label="left/active\nrole: active\nactive (wg0)", style=filled, fillcolor=palegreen];`)
+	assert.Contains(t, dot, `"left/active" -- "right/active" [label="wg0\n1.23ms, 1.0% loss", color=darkgreen, penwidth=3];`)
+	assert.Contains(t, dot, `"left/active/wg1/?" [label="?", style=dashed];`)
+	assert.Contains(t, dot, `"left/active" -- "left/active/wg1/?" [label="wg1\ndown", color=red, style=dashed];`)
+	assert.Contains(t, dot, `"left/active" -- "left/standby" [label="partner", style=dotted];`)
+}
+
+func TestMermaid(t *testing.T) {
+	mermaid := string(topology.Mermaid(testTopology()))
+
+	assert.Contains(t, mermaid, `n0["left/active<br/>role: active<br/>active (wg0)"]`)
+	assert.Contains(t, mermaid, `n0 ===|"wg0<br/>1.23ms, 1.0% loss"| n2`)
+	assert.Contains(t, mermaid, `n0 ---|"wg1<br/>down"| n3`)
+	assert.Contains(t, mermaid, `n0 -.-|"partner"| n1`)
+	assert.Contains(t, mermaid, `class n0,n2 active`)
+	assert.Contains(t, mermaid, `linkStyle 1 stroke:red`)
+}
+
+func TestRender(t *testing.T) {
+	b, contentType, err := topology.Render(testTopology(), topology.FormatJSON)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", contentType)
+	assert.NoError(t, json.Unmarshal(b, &types.Topology{}))
+
+	_, _, err = topology.Render(testTopology(), "svg")
+	assert.Error(t, err)
+}
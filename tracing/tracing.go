@@ -0,0 +1,149 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/flashbots/vpnham/config"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	otelapi "go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	tracerName = "vpnham"
+)
+
+var (
+	provider *trace.TracerProvider
+	tracer   otelapi.Tracer = noop.NewTracerProvider().Tracer(tracerName)
+)
+
+// Setup configures the otlp exporter for the traces.  Unless the endpoint is
+// configured the tracing remains a no-op.
+func Setup(ctx context.Context, cfg *config.Tracing, version string) error {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	var client otlptrace.Client
+	switch cfg.Protocol {
+	case config.OTLPProtocolGRPC:
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(cfg.Endpoint),
+			otlptracegrpc.WithHeaders(cfg.Headers),
+			otlptracegrpc.WithTimeout(cfg.Timeout),
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		client = otlptracegrpc.NewClient(opts...)
+
+	case config.OTLPProtocolHTTP:
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(cfg.Endpoint),
+			otlptracehttp.WithHeaders(cfg.Headers),
+			otlptracehttp.WithTimeout(cfg.Timeout),
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		client = otlptracehttp.NewClient(opts...)
+	}
+
+	exporter, err := otlptrace.New(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithHost(),
+		resource.WithAttributes(
+			semconv.ServiceName(tracerName),
+			semconv.ServiceVersion(version),
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	provider = trace.NewTracerProvider(
+		trace.WithBatcher(exporter),
+		trace.WithResource(res),
+		trace.WithSampler(trace.ParentBased(linkBased{trace.TraceIDRatioBased(*cfg.SampleRatio)})),
+	)
+
+	tracer = provider.Tracer(tracerName)
+
+	return nil
+}
+
+// linkBased samples the new traces that are linked to the sampled ones (so
+// that the reconcile jobs of a sampled failover are not lost), and falls back
+// to the root sampler for the rest.
+type linkBased struct {
+	root trace.Sampler
+}
+
+func (s linkBased) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	for _, link := range p.Links {
+		if link.SpanContext.IsSampled() {
+			return trace.SamplingResult{
+				Decision:   trace.RecordAndSample,
+				Tracestate: otelapi.SpanContextFromContext(p.ParentContext).TraceState(),
+			}
+		}
+	}
+	return s.root.ShouldSample(p)
+}
+
+func (s linkBased) Description() string {
+	return "LinkBased{" + s.root.Description() + "}"
+}
+
+// Shutdown flushes the pending spans and stops the exporter.
+func Shutdown(ctx context.Context) error {
+	if provider == nil {
+		return nil
+	}
+	return provider.Shutdown(ctx)
+}
+
+// Start starts a new span (as a child of the span in the context, if any).
+func Start(
+	ctx context.Context,
+	name string,
+	opts ...otelapi.SpanStartOption,
+) (context.Context, otelapi.Span) {
+	return tracer.Start(ctx, name, opts...)
+}
+
+// WithSpan wraps the call into a span, and records its error (if any).
+func WithSpan(
+	ctx context.Context,
+	name string,
+	do func(context.Context) error,
+	opts ...otelapi.SpanStartOption,
+) error {
+	ctx, span := tracer.Start(ctx, name, opts...)
+	defer span.End()
+
+	err := do(ctx)
+	RecordError(span, err)
+
+	return err
+}
+
+// RecordError marks the span as failed (if the error is not nil).
+func RecordError(span otelapi.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
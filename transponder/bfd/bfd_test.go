@@ -0,0 +1,105 @@
+package bfd_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/transponder/bfd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacket(t *testing.T) {
+	p := bfd.Packet{
+		Diag:  bfd.DiagControlDetectionTimeExpired,
+		State: bfd.StateInit,
+		Poll:  true,
+
+		DetectMultiplier:  3,
+		MyDiscriminator:   0xdeadbeef,
+		YourDiscriminator: 42,
+
+		DesiredMinTxInterval:  300 * time.Millisecond,
+		RequiredMinRxInterval: time.Second,
+	}
+
+	b, err := p.MarshalBinary()
+	require.NoError(t, err)
+	assert.Len(t, b, 24)
+	assert.Equal(t, byte(0x21), b[0]) // version 1, diag 1
+	assert.Equal(t, byte(0xa0), b[1]) // state init, poll
+
+	var q bfd.Packet
+	require.NoError(t, q.UnmarshalBinary(b))
+	assert.Equal(t, p, q)
+
+	b[0] = 0x41 // version 2
+	assert.Error(t, q.UnmarshalBinary(b))
+}
+
+func freeAddr(t *testing.T) *net.UDPAddr {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr)
+}
+
+func TestSession(t *testing.T) {
+	ctx := context.Background()
+	failureSink := make(chan error, 2)
+
+	addrA, addrB := freeAddr(t), freeAddr(t)
+	cfg := func(local, peer *net.UDPAddr) bfd.Config {
+		return bfd.Config{
+			LocalAddr:             local,
+			PeerAddr:              peer,
+			DesiredMinTxInterval:  20 * time.Millisecond,
+			RequiredMinRxInterval: 20 * time.Millisecond,
+			DetectMultiplier:      3,
+		}
+	}
+
+	a, err := bfd.New("test", "a", cfg(addrA, addrB))
+	require.NoError(t, err)
+	b, err := bfd.New("test", "b", cfg(addrB, addrA))
+	require.NoError(t, err)
+
+	diags := make(chan bfd.Diag, 8)
+	a.OnStateChange = func(_, to bfd.State, diag bfd.Diag) {
+		if to == bfd.StateDown {
+			diags <- diag
+		}
+	}
+
+	a.Run(ctx, failureSink)
+	b.Run(ctx, failureSink)
+	defer a.Stop(ctx)
+
+	assert.Eventually(t, func() bool {
+		return a.State() == bfd.StateUp && b.State() == bfd.StateUp
+	}, 2*time.Second, 5*time.Millisecond)
+
+	var probeErr error = assert.AnError
+	a.Probe(ctx, 1, func(_ time.Duration, err error) { probeErr = err })
+	assert.NoError(t, probeErr)
+
+	// let the poll sequence complete and the fast rate kick in
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, bfd.StateUp, a.State())
+
+	b.Stop(ctx)
+
+	select {
+	case diag := <-diags:
+		assert.Equal(t, bfd.DiagNeighborSignaledSessionDown, diag)
+	case <-time.After(time.Second):
+		t.Fatal("session did not go down")
+	}
+
+	a.Probe(ctx, 2, func(_ time.Duration, err error) { probeErr = err })
+	assert.Error(t, probeErr)
+
+	assert.Empty(t, failureSink)
+}
@@ -0,0 +1,115 @@
+package bfd
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+const (
+	// rfc5881, section 5
+	ttl = 255
+
+	// rfc5881, section 4
+	srcPortMin = 49152
+	srcPortMax = 65535
+)
+
+var (
+	errFailedToBindSourcePort = errors.New("failed to bind source port in range 49152..65535")
+)
+
+// rxConn receives control packets and reports their ttl (so that the packets
+// that were not sent from the directly connected peer can be dropped).
+type rxConn struct {
+	conn *net.UDPConn
+	ipv4 *ipv4.PacketConn
+	ipv6 *ipv6.PacketConn
+}
+
+func listenRx(addr *net.UDPAddr) (*rxConn, error) {
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &rxConn{conn: conn}
+	if addr.IP.To4() != nil {
+		c.ipv4 = ipv4.NewPacketConn(conn)
+		err = c.ipv4.SetControlMessage(ipv4.FlagTTL, true)
+	} else {
+		c.ipv6 = ipv6.NewPacketConn(conn)
+		err = c.ipv6.SetControlMessage(ipv6.FlagHopLimit, true)
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *rxConn) read(buf []byte) (int, *net.UDPAddr, int, error) {
+	var (
+		n   int
+		src net.Addr
+		hop = -1
+		err error
+	)
+
+	if c.ipv4 != nil {
+		var cm *ipv4.ControlMessage
+		n, cm, src, err = c.ipv4.ReadFrom(buf)
+		if cm != nil {
+			hop = cm.TTL
+		}
+	} else {
+		var cm *ipv6.ControlMessage
+		n, cm, src, err = c.ipv6.ReadFrom(buf)
+		if cm != nil {
+			hop = cm.HopLimit
+		}
+	}
+	if err != nil {
+		return 0, nil, 0, err
+	}
+
+	addr, _ := src.(*net.UDPAddr)
+	return n, addr, hop, nil
+}
+
+func (c *rxConn) close() error {
+	return c.conn.Close()
+}
+
+// dialTx opens the socket for sending control packets from a source port in
+// the range mandated by rfc5881 and with ttl of 255.
+func dialTx(local, peer *net.UDPAddr) (*net.UDPConn, error) {
+	var errs []error
+
+	for attempt := 0; attempt < 16; attempt++ {
+		port := srcPortMin + rand.IntN(srcPortMax-srcPortMin+1)
+		conn, err := net.DialUDP("udp", &net.UDPAddr{IP: local.IP, Port: port, Zone: local.Zone}, peer)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if peer.IP.To4() != nil {
+			err = ipv4.NewConn(conn).SetTTL(ttl)
+		} else {
+			err = ipv6.NewConn(conn).SetHopLimit(ttl)
+		}
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+
+		return conn, nil
+	}
+
+	return nil, errors.Join(append([]error{errFailedToBindSourcePort}, errs...)...)
+}
@@ -0,0 +1,202 @@
+package bfd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// State is the state of the bfd session (rfc5880, section 4.1).
+type State uint8
+
+const (
+	StateAdminDown State = 0
+	StateDown      State = 1
+	StateInit      State = 2
+	StateUp        State = 3
+)
+
+func (s State) String() string {
+	switch s {
+	case StateAdminDown:
+		return "ADMIN_DOWN"
+	case StateDown:
+		return "DOWN"
+	case StateInit:
+		return "INIT"
+	case StateUp:
+		return "UP"
+	}
+	return "N/A"
+}
+
+// Diag is the reason of the last change of the session state (rfc5880,
+// section 4.1).
+type Diag uint8
+
+const (
+	DiagNone                        Diag = 0
+	DiagControlDetectionTimeExpired Diag = 1
+	DiagEchoFunctionFailed          Diag = 2
+	DiagNeighborSignaledSessionDown Diag = 3
+	DiagForwardingPlaneReset        Diag = 4
+	DiagPathDown                    Diag = 5
+	DiagConcatenatedPathDown        Diag = 6
+	DiagAdministrativelyDown        Diag = 7
+	DiagReverseConcatenatedPathDown Diag = 8
+)
+
+func (d Diag) String() string {
+	switch d {
+	case DiagNone:
+		return "none"
+	case DiagControlDetectionTimeExpired:
+		return "control detection time expired"
+	case DiagEchoFunctionFailed:
+		return "echo function failed"
+	case DiagNeighborSignaledSessionDown:
+		return "neighbor signaled session down"
+	case DiagForwardingPlaneReset:
+		return "forwarding plane reset"
+	case DiagPathDown:
+		return "path down"
+	case DiagConcatenatedPathDown:
+		return "concatenated path down"
+	case DiagAdministrativelyDown:
+		return "administratively down"
+	case DiagReverseConcatenatedPathDown:
+		return "reverse concatenated path down"
+	}
+	return "N/A"
+}
+
+const (
+	version    = 1
+	packetSize = 24
+
+	flagPoll           = 0x20
+	flagFinal          = 0x10
+	flagCPlaneIndep    = 0x08
+	flagAuthentication = 0x04
+	flagDemand         = 0x02
+	flagMultipoint     = 0x01
+)
+
+// Packet is the bfd control packet (without the authentication section).
+type Packet struct {
+	Diag  Diag
+	State State
+
+	Poll  bool
+	Final bool
+
+	DetectMultiplier uint8
+
+	MyDiscriminator   uint32
+	YourDiscriminator uint32
+
+	DesiredMinTxInterval      time.Duration
+	RequiredMinRxInterval     time.Duration
+	RequiredMinEchoRxInterval time.Duration
+}
+
+var (
+	errPacketIsInvalid = errors.New("invalid bfd control packet")
+)
+
+func (p Packet) MarshalBinary() ([]byte, error) {
+	data := make([]byte, packetSize)
+
+	var flags byte
+	if p.Poll {
+		flags |= flagPoll
+	}
+	if p.Final {
+		flags |= flagFinal
+	}
+
+	data[0] = version<<5 | byte(p.Diag)&0x1f                                                    // 00     : vers + diag
+	data[1] = byte(p.State)<<6 | flags                                                          // 01     : state + flags
+	data[2] = p.DetectMultiplier                                                                // 02     : detect mult
+	data[3] = packetSize                                                                        // 03     : length
+	binary.BigEndian.PutUint32(data[4:8], p.MyDiscriminator)                                    // 04..07 : my discriminator
+	binary.BigEndian.PutUint32(data[8:12], p.YourDiscriminator)                                 // 08..11 : your discriminator
+	binary.BigEndian.PutUint32(data[12:16], uint32(p.DesiredMinTxInterval.Microseconds()))      // 12..15 : desired min tx interval
+	binary.BigEndian.PutUint32(data[16:20], uint32(p.RequiredMinRxInterval.Microseconds()))     // 16..19 : required min rx interval
+	binary.BigEndian.PutUint32(data[20:24], uint32(p.RequiredMinEchoRxInterval.Microseconds())) // 20..23 : required min echo rx interval
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes the control packet and validates it according to
+// rfc5880 (section 6.8.6).
+func (p *Packet) UnmarshalBinary(data []byte) error {
+	if len(data) < packetSize {
+		return fmt.Errorf("%w: too short: %d",
+			errPacketIsInvalid, len(data),
+		)
+	}
+
+	if v := data[0] >> 5; v != version {
+		return fmt.Errorf("%w: unsupported version: %d",
+			errPacketIsInvalid, v,
+		)
+	}
+
+	length := int(data[3])
+	if length < packetSize || length > len(data) {
+		return fmt.Errorf("%w: invalid length: %d",
+			errPacketIsInvalid, length,
+		)
+	}
+
+	flags := data[1] & 0x3f
+	if flags&flagAuthentication != 0 {
+		return fmt.Errorf("%w: authentication is not supported",
+			errPacketIsInvalid,
+		)
+	}
+	if flags&flagMultipoint != 0 {
+		return fmt.Errorf("%w: multipoint bit is set",
+			errPacketIsInvalid,
+		)
+	}
+
+	if data[2] == 0 {
+		return fmt.Errorf("%w: zero detect multiplier",
+			errPacketIsInvalid,
+		)
+	}
+
+	*p = Packet{
+		Diag:  Diag(data[0] & 0x1f),
+		State: State(data[1] >> 6),
+
+		Poll:  flags&flagPoll != 0,
+		Final: flags&flagFinal != 0,
+
+		DetectMultiplier: data[2],
+
+		MyDiscriminator:   binary.BigEndian.Uint32(data[4:8]),
+		YourDiscriminator: binary.BigEndian.Uint32(data[8:12]),
+
+		DesiredMinTxInterval:      time.Duration(binary.BigEndian.Uint32(data[12:16])) * time.Microsecond,
+		RequiredMinRxInterval:     time.Duration(binary.BigEndian.Uint32(data[16:20])) * time.Microsecond,
+		RequiredMinEchoRxInterval: time.Duration(binary.BigEndian.Uint32(data[20:24])) * time.Microsecond,
+	}
+
+	if p.Poll && p.Final {
+		return fmt.Errorf("%w: both poll and final bits are set",
+			errPacketIsInvalid,
+		)
+	}
+
+	if p.MyDiscriminator == 0 {
+		return fmt.Errorf("%w: zero my discriminator",
+			errPacketIsInvalid,
+		)
+	}
+
+	return nil
+}
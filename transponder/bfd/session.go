@@ -0,0 +1,500 @@
+package bfd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/flashbots/vpnham/logutils"
+	"github.com/flashbots/vpnham/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	otelapi "go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+const (
+	// Port is the destination port of single-hop bfd control packets
+	// (rfc5881, section 4).
+	Port = 3784
+
+	// slowTxInterval is the minimum tx interval while the session is not up
+	// (rfc5880, section 6.8.3).
+	slowTxInterval = time.Second
+)
+
+type Config struct {
+	LocalAddr *net.UDPAddr // where the control packets are received
+	PeerAddr  *net.UDPAddr // where the control packets are sent to
+
+	DesiredMinTxInterval  time.Duration
+	RequiredMinRxInterval time.Duration
+	DetectMultiplier      uint8
+}
+
+// Session is the single-hop bfd session in asynchronous mode (rfc5880 and
+// rfc5881;  no authentication, no demand mode, no echo function).
+type Session struct {
+	name    string
+	ifsName string
+	cfg     Config
+
+	mx sync.Mutex
+
+	state State
+	diag  Diag
+
+	localDiscr  uint32
+	remoteDiscr uint32
+
+	remoteState            State
+	remoteDesiredMinTx     time.Duration
+	remoteRequiredMinRx    time.Duration
+	remoteDetectMultiplier uint8
+
+	desiredMinTx time.Duration // the one currently in effect
+	poll         bool          // poll sequence is in progress
+	final        bool          // final bit is due to be sent
+
+	rx        *rxConn
+	tx        *net.UDPConn
+	detection *time.Timer
+	kick      chan struct{}
+	done      chan struct{}
+	inflight  sync.WaitGroup
+	goingDown bool
+
+	// OnStateChange is invoked (if set) every time the session changes its
+	// state (except for when the session is going down).
+	OnStateChange func(from, to State, diag Diag)
+}
+
+var (
+	errSessionConfigIsInvalid = errors.New("bfd session configuration is invalid")
+	errSessionIsAlreadyUp     = errors.New("bfd session is already running")
+	errSessionIsNotUp         = errors.New("bfd session is not up")
+)
+
+func New(name, ifsName string, cfg Config) (*Session, error) {
+	if cfg.LocalAddr == nil || cfg.PeerAddr == nil {
+		return nil, fmt.Errorf("%w: local and peer addresses are required",
+			errSessionConfigIsInvalid,
+		)
+	}
+
+	if cfg.DesiredMinTxInterval <= 0 || cfg.RequiredMinRxInterval <= 0 || cfg.DetectMultiplier == 0 {
+		return nil, fmt.Errorf("%w: intervals and detect multiplier must be positive",
+			errSessionConfigIsInvalid,
+		)
+	}
+
+	discr := uint32(0)
+	for discr == 0 {
+		discr = rand.Uint32()
+	}
+
+	return &Session{
+		name:    name,
+		ifsName: ifsName,
+		cfg:     cfg,
+
+		state: StateDown,
+
+		localDiscr: discr,
+
+		remoteRequiredMinRx: time.Microsecond, // rfc5880, section 6.8.1
+
+		desiredMinTx: max(slowTxInterval, cfg.DesiredMinTxInterval),
+
+		kick: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}, nil
+}
+
+func (s *Session) InterfaceName() string {
+	return s.ifsName
+}
+
+func (s *Session) State() State {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return s.state
+}
+
+func (s *Session) Run(ctx context.Context, failureSink chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	if s.rx != nil {
+		failureSink <- fmt.Errorf("%s: %w", s.cfg.LocalAddr, errSessionIsAlreadyUp)
+		return
+	}
+
+	rx, err := listenRx(s.cfg.LocalAddr)
+	if err != nil {
+		failureSink <- fmt.Errorf("%s: %w", s.cfg.LocalAddr, err)
+		return
+	}
+	tx, err := dialTx(s.cfg.LocalAddr, s.cfg.PeerAddr)
+	if err != nil {
+		_ = rx.close()
+		failureSink <- fmt.Errorf("%s: %w", s.cfg.LocalAddr, err)
+		return
+	}
+	s.rx, s.tx = rx, tx
+
+	l.Info("VPN HA-monitor bfd session is going up...",
+		zap.String("bfd_local_addr", s.cfg.LocalAddr.String()),
+		zap.String("bfd_peer_addr", s.cfg.PeerAddr.String()),
+	)
+
+	go s.receive(ctx)
+	go s.transmit(ctx)
+}
+
+func (s *Session) Stop(ctx context.Context) {
+	l := logutils.LoggerFromContext(ctx)
+
+	s.mx.Lock()
+	if s.goingDown {
+		s.mx.Unlock()
+		return
+	}
+	s.goingDown = true
+	if s.detection != nil {
+		s.detection.Stop()
+	}
+	s.mx.Unlock()
+
+	close(s.done)
+
+	if s.rx == nil {
+		return
+	}
+
+	// let the peer know (best effort;  rfc5880, section 6.8.16)
+	s.setState(ctx, StateAdminDown, DiagAdministrativelyDown)
+	_ = s.send()
+
+	if err := errors.Join(s.rx.close(), s.tx.Close()); err != nil {
+		l.Error("VPN HA-monitor bfd session shutdown failed",
+			zap.Error(err),
+			zap.String("bfd_local_addr", s.cfg.LocalAddr.String()),
+		)
+	}
+
+	// wait for the state changes that are being reported at the moment
+	s.inflight.Wait()
+
+	l.Info("VPN HA-monitor bfd session is down",
+		zap.String("bfd_local_addr", s.cfg.LocalAddr.String()),
+	)
+}
+
+// Probe reports success if the session is up at the moment.
+func (s *Session) Probe(_ context.Context, _ uint64, done func(time.Duration, error)) {
+	s.mx.Lock()
+	state, diag := s.state, s.diag
+	s.mx.Unlock()
+
+	if state != StateUp {
+		done(0, fmt.Errorf("%w: %s (%s)",
+			errSessionIsNotUp, state, diag,
+		))
+		return
+	}
+
+	done(0, nil)
+}
+
+func (s *Session) transmit(ctx context.Context) {
+	l := logutils.LoggerFromContext(ctx)
+
+	timer := time.NewTimer(0) // send the first packet right away
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.kick:
+			if !timer.Stop() {
+				<-timer.C
+			}
+		case <-timer.C:
+		}
+
+		if s.periodic() {
+			if err := s.send(); errors.Is(err, syscall.ECONNREFUSED) {
+				// the peer is not listening (it's what the detection is for)
+				l.Debug("BFD control packet was refused",
+					zap.String("bfd_peer_addr", s.cfg.PeerAddr.String()),
+				)
+			} else if err != nil {
+				select {
+				case <-s.done:
+					return
+				default:
+				}
+				l.Error("Failed to send bfd control packet",
+					zap.Error(err),
+					zap.String("bfd_peer_addr", s.cfg.PeerAddr.String()),
+				)
+				metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
+					attribute.String(metrics.LabelBridge, s.name),
+					attribute.String(metrics.LabelErrorScope, metrics.ScopePeerProbing),
+				))
+			}
+		}
+		timer.Reset(s.txInterval())
+	}
+}
+
+func (s *Session) receive(ctx context.Context) {
+	l := logutils.LoggerFromContext(ctx)
+
+	buf := make([]byte, 64)
+	for {
+		n, src, hop, err := s.rx.read(buf)
+		if err != nil {
+			select {
+			case <-s.done:
+				return
+			default:
+			}
+			l.Error("Failed to receive bfd control packet",
+				zap.Error(err),
+				zap.String("bfd_local_addr", s.cfg.LocalAddr.String()),
+			)
+			metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
+				attribute.String(metrics.LabelBridge, s.name),
+				attribute.String(metrics.LabelErrorScope, metrics.ScopePeerProbing),
+			))
+			continue
+		}
+
+		if src == nil || !src.IP.Equal(s.cfg.PeerAddr.IP) || hop != ttl {
+			l.Debug("Dropping bfd control packet from unexpected source",
+				zap.Stringer("src", src),
+				zap.Int("ttl", hop),
+			)
+			continue
+		}
+
+		p := &Packet{}
+		if err := p.UnmarshalBinary(buf[:n]); err != nil {
+			l.Debug("Dropping invalid bfd control packet",
+				zap.Error(err),
+			)
+			continue
+		}
+
+		s.process(ctx, p)
+	}
+}
+
+// process handles the received control packet (rfc5880, section 6.8.6).
+func (s *Session) process(ctx context.Context, p *Packet) {
+	s.mx.Lock()
+
+	if s.goingDown {
+		s.mx.Unlock()
+		return
+	}
+
+	if p.YourDiscriminator != 0 && p.YourDiscriminator != s.localDiscr {
+		s.mx.Unlock()
+		return
+	}
+	if p.YourDiscriminator == 0 && s.state != StateDown && s.state != StateAdminDown {
+		s.mx.Unlock()
+		return
+	}
+
+	s.remoteDiscr = p.MyDiscriminator
+	s.remoteState = p.State
+	s.remoteDesiredMinTx = p.DesiredMinTxInterval
+	s.remoteRequiredMinRx = p.RequiredMinRxInterval
+	s.remoteDetectMultiplier = p.DetectMultiplier
+
+	if p.Final {
+		s.poll = false
+	}
+
+	if p.Poll {
+		s.final = true
+	}
+
+	if s.state == StateAdminDown {
+		s.mx.Unlock()
+		return
+	}
+
+	// (re-)arm the detection timer
+
+	detectionTime := time.Duration(s.remoteDetectMultiplier) * max(s.cfg.RequiredMinRxInterval, s.remoteDesiredMinTx)
+	if s.detection == nil {
+		s.detection = time.AfterFunc(detectionTime, func() { s.expire(ctx) })
+	} else {
+		s.detection.Reset(detectionTime)
+	}
+
+	to, diag := s.state, DiagNone
+	switch {
+	case p.State == StateAdminDown:
+		if s.state != StateDown {
+			to, diag = StateDown, DiagNeighborSignaledSessionDown
+		}
+	case s.state == StateDown && p.State == StateDown:
+		to = StateInit
+	case s.state == StateDown && p.State == StateInit:
+		to = StateUp
+	case s.state == StateInit && (p.State == StateInit || p.State == StateUp):
+		to = StateUp
+	case s.state == StateUp && p.State == StateDown:
+		to, diag = StateDown, DiagNeighborSignaledSessionDown
+	}
+
+	changed, final := to != s.state, s.final
+	s.mx.Unlock()
+
+	if changed {
+		s.setState(ctx, to, diag)
+	} else if final {
+		s.trigger()
+	}
+}
+
+// expire handles the expiry of the detection time.
+func (s *Session) expire(ctx context.Context) {
+	s.mx.Lock()
+	state, goingDown := s.state, s.goingDown
+	s.mx.Unlock()
+
+	if goingDown || (state != StateInit && state != StateUp) {
+		return
+	}
+
+	s.setState(ctx, StateDown, DiagControlDetectionTimeExpired)
+}
+
+func (s *Session) setState(ctx context.Context, to State, diag Diag) {
+	l := logutils.LoggerFromContext(ctx)
+
+	s.mx.Lock()
+	from := s.state
+	if from == to {
+		s.mx.Unlock()
+		return
+	}
+	s.state = to
+	s.diag = diag
+
+	switch to {
+	case StateUp:
+		if s.cfg.DesiredMinTxInterval < slowTxInterval {
+			// the faster rate takes effect right away, and the peer is
+			// notified via poll sequence (rfc5880, section 6.8.3)
+			s.poll = true
+		}
+	default:
+		s.poll = false
+		if to == StateDown {
+			s.remoteDiscr = 0
+		}
+	}
+	s.desiredMinTx = s.advertisedMinTx()
+	onStateChange := s.OnStateChange
+	if s.goingDown {
+		onStateChange = nil
+	}
+	if onStateChange != nil {
+		s.inflight.Add(1)
+	}
+	s.mx.Unlock()
+
+	l.Info("BFD session changed state",
+		zap.String("tunnel_interface", s.ifsName),
+		zap.String("from", from.String()),
+		zap.String("to", to.String()),
+		zap.String("diag", diag.String()),
+	)
+
+	if onStateChange != nil {
+		onStateChange(from, to, diag)
+		s.inflight.Done()
+	}
+
+	s.trigger()
+}
+
+// trigger makes the session send a control packet without waiting for the
+// tx interval to elapse.
+func (s *Session) trigger() {
+	select {
+	case s.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Session) send() error {
+	s.mx.Lock()
+	p := Packet{
+		Diag:  s.diag,
+		State: s.state,
+
+		Poll:  s.poll && !s.final, // both must never be set (rfc5880, section 6.5)
+		Final: s.final,
+
+		DetectMultiplier: s.cfg.DetectMultiplier,
+
+		MyDiscriminator:   s.localDiscr,
+		YourDiscriminator: s.remoteDiscr,
+
+		DesiredMinTxInterval:  s.advertisedMinTx(),
+		RequiredMinRxInterval: s.cfg.RequiredMinRxInterval,
+	}
+	s.final = false
+	s.mx.Unlock()
+
+	b, _ := p.MarshalBinary()
+	_, err := s.tx.Write(b)
+	return err
+}
+
+// advertisedMinTx returns the desired min tx interval to be advertised to the
+// peer.
+//
+// Must be called while holding the lock.
+func (s *Session) advertisedMinTx() time.Duration {
+	if s.state != StateUp {
+		return max(slowTxInterval, s.cfg.DesiredMinTxInterval)
+	}
+	return s.cfg.DesiredMinTxInterval
+}
+
+// periodic returns false if the peer asked for no periodic control packets
+// (but the pending final bit still must be sent).
+func (s *Session) periodic() bool {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return s.remoteDiscr == 0 || s.remoteRequiredMinRx > 0 || s.final
+}
+
+// txInterval returns the interval until the next control packet (jittered
+// according to rfc5880, section 6.8.7).
+func (s *Session) txInterval() time.Duration {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	interval := max(s.desiredMinTx, s.remoteRequiredMinRx)
+	if s.cfg.DetectMultiplier == 1 {
+		return interval * time.Duration(75+rand.IntN(16)) / 100
+	}
+	return interval * time.Duration(75+rand.IntN(26)) / 100
+}
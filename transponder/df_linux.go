@@ -0,0 +1,30 @@
+//go:build linux
+
+package transponder
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+// setDontFragment makes the kernel send the datagrams with DF bit set (and
+// refuse to send the ones that are larger than the known path mtu), so that
+// the padded probes can detect mtu black holes.
+func setDontFragment(conn *net.UDPConn, ipv6 bool) error {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var errSockopt error
+	err = raw.Control(func(fd uintptr) {
+		if ipv6 {
+			errSockopt = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_MTU_DISCOVER, syscall.IPV6_PMTUDISC_DO)
+		} else {
+			errSockopt = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_MTU_DISCOVER, syscall.IP_PMTUDISC_DO)
+		}
+	})
+
+	return errors.Join(err, errSockopt)
+}
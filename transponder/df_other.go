@@ -0,0 +1,11 @@
+//go:build !linux
+
+package transponder
+
+import "net"
+
+// setDontFragment is a noop on the platforms other than linux (so the padded
+// probes might get fragmented there).
+func setDontFragment(_ *net.UDPConn, _ bool) error {
+	return nil
+}
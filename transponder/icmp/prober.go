@@ -0,0 +1,316 @@
+package icmp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/flashbots/vpnham/logutils"
+	"github.com/flashbots/vpnham/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	otelapi "go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+const (
+	protocolICMP     = 1  // iana protocol number of icmp
+	protocolIPv6ICMP = 58 // iana protocol number of icmp for ipv6
+)
+
+// Prober checks the peer with icmp echo requests.  It uses unprivileged ping
+// sockets where available (on linux see `net.ipv4.ping_group_range` sysctl),
+// and falls back to raw sockets otherwise.
+type Prober struct {
+	name    string
+	ifsName string
+
+	localAddr net.IP
+	peerAddr  net.IP
+
+	conn       *icmp.PacketConn
+	privileged bool
+	id         int
+
+	mx        sync.Mutex
+	pending   map[uint64]*pending
+	inflight  sync.WaitGroup
+	goingDown bool
+	done      chan struct{}
+}
+
+type pending struct {
+	sent time.Time
+	done func(time.Duration, error)
+	stop func() bool
+}
+
+var (
+	errProberConfigIsInvalid = errors.New("icmp prober configuration is invalid")
+	errProberIsAlreadyUp     = errors.New("icmp prober is already running")
+	errProberIsNotUp         = errors.New("icmp prober is not running")
+)
+
+func New(name, ifsName string, localAddr, peerAddr net.IP) (*Prober, error) {
+	if localAddr == nil || peerAddr == nil {
+		return nil, fmt.Errorf("%w: local and peer addresses are required",
+			errProberConfigIsInvalid,
+		)
+	}
+
+	if (localAddr.To4() == nil) != (peerAddr.To4() == nil) {
+		return nil, fmt.Errorf("%w: local and peer addresses must be of the same family",
+			errProberConfigIsInvalid,
+		)
+	}
+
+	return &Prober{
+		name:    name,
+		ifsName: ifsName,
+
+		localAddr: localAddr,
+		peerAddr:  peerAddr,
+
+		id:      os.Getpid() & 0xffff,
+		pending: make(map[uint64]*pending),
+		done:    make(chan struct{}),
+	}, nil
+}
+
+func (p *Prober) InterfaceName() string {
+	return p.ifsName
+}
+
+func (p *Prober) Run(ctx context.Context, failureSink chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	if p.conn != nil {
+		failureSink <- fmt.Errorf("%s: %w", p.localAddr, errProberIsAlreadyUp)
+		return
+	}
+
+	conn, privileged, err := p.listen()
+	if err != nil {
+		failureSink <- fmt.Errorf("%s: %w", p.localAddr, err)
+		return
+	}
+	p.conn, p.privileged = conn, privileged
+
+	l.Info("VPN HA-monitor icmp prober is going up...",
+		zap.String("icmp_local_addr", p.localAddr.String()),
+		zap.String("icmp_peer_addr", p.peerAddr.String()),
+		zap.Bool("icmp_privileged", p.privileged),
+	)
+
+	go p.receive(ctx)
+}
+
+func (p *Prober) Stop(ctx context.Context) {
+	l := logutils.LoggerFromContext(ctx)
+
+	p.mx.Lock()
+	if p.goingDown {
+		p.mx.Unlock()
+		return
+	}
+	p.goingDown = true
+	// the outstanding echo requests are dropped without reporting
+	for sequence, req := range p.pending {
+		delete(p.pending, sequence)
+		req.stop()
+		p.inflight.Done()
+	}
+	p.mx.Unlock()
+
+	close(p.done)
+
+	if p.conn != nil {
+		if err := p.conn.Close(); err != nil {
+			l.Error("VPN HA-monitor icmp prober shutdown failed",
+				zap.Error(err),
+				zap.String("icmp_local_addr", p.localAddr.String()),
+			)
+		}
+	}
+
+	// wait for the outcomes that are being reported at the moment
+	p.inflight.Wait()
+
+	if p.conn != nil {
+		l.Info("VPN HA-monitor icmp prober is down",
+			zap.String("icmp_local_addr", p.localAddr.String()),
+		)
+	}
+}
+
+// Probe sends the echo request to the peer and reports the round-trip time
+// once the reply comes back.  The probe fails if there's no reply by the time
+// the context is done.
+func (p *Prober) Probe(ctx context.Context, sequence uint64, done func(time.Duration, error)) {
+	p.mx.Lock()
+	if p.conn == nil || p.goingDown {
+		p.mx.Unlock()
+		done(0, errProberIsNotUp)
+		return
+	}
+	req := &pending{
+		sent: time.Now(),
+		done: done,
+	}
+	req.stop = context.AfterFunc(ctx, func() {
+		if req := p.take(sequence); req != nil {
+			defer p.inflight.Done()
+			req.done(0, ctx.Err())
+		}
+	})
+	p.pending[sequence] = req
+	p.inflight.Add(1)
+	p.mx.Unlock()
+
+	if err := p.send(sequence); err != nil {
+		if req := p.take(sequence); req != nil {
+			defer p.inflight.Done()
+			req.stop()
+			req.done(0, err)
+		}
+	}
+}
+
+func (p *Prober) listen() (*icmp.PacketConn, bool, error) {
+	network, privileged := "udp4", "ip4:icmp"
+	if p.localAddr.To4() == nil {
+		network, privileged = "udp6", "ip6:ipv6-icmp"
+	}
+
+	conn, err := icmp.ListenPacket(network, p.localAddr.String())
+	if err == nil {
+		return conn, false, nil
+	}
+
+	conn, errPrivileged := icmp.ListenPacket(privileged, p.localAddr.String())
+	if errPrivileged == nil {
+		return conn, true, nil
+	}
+
+	return nil, false, errors.Join(err, errPrivileged)
+}
+
+func (p *Prober) send(sequence uint64) error {
+	var typ icmp.Type = ipv4.ICMPTypeEcho
+	if p.peerAddr.To4() == nil {
+		typ = ipv6.ICMPTypeEchoRequest
+	}
+
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, sequence)
+
+	msg := &icmp.Message{
+		Type: typ,
+		Body: &icmp.Echo{
+			ID:   p.id, // rewritten by the kernel for unprivileged sockets
+			Seq:  int(uint16(sequence)),
+			Data: data,
+		},
+	}
+
+	b, err := msg.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	var dst net.Addr = &net.IPAddr{IP: p.peerAddr}
+	if !p.privileged {
+		dst = &net.UDPAddr{IP: p.peerAddr}
+	}
+
+	_, err = p.conn.WriteTo(b, dst)
+	return err
+}
+
+func (p *Prober) receive(ctx context.Context) {
+	l := logutils.LoggerFromContext(ctx)
+
+	proto, typ := protocolICMP, icmp.Type(ipv4.ICMPTypeEchoReply)
+	if p.peerAddr.To4() == nil {
+		proto, typ = protocolIPv6ICMP, ipv6.ICMPTypeEchoReply
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, src, err := p.conn.ReadFrom(buf)
+		ts := time.Now()
+		if err != nil {
+			select {
+			case <-p.done:
+				return
+			default:
+			}
+			l.Error("Failed to receive icmp message",
+				zap.Error(err),
+				zap.String("icmp_local_addr", p.localAddr.String()),
+			)
+			metrics.Errors.Add(ctx, 1, otelapi.WithAttributes(
+				attribute.String(metrics.LabelBridge, p.name),
+				attribute.String(metrics.LabelErrorScope, metrics.ScopePeerProbing),
+			))
+			continue
+		}
+
+		if !p.peerAddr.Equal(addrIP(src)) {
+			continue
+		}
+
+		msg, err := icmp.ParseMessage(proto, buf[:n])
+		if err != nil || msg.Type != typ {
+			continue
+		}
+		echo, ok := msg.Body.(*icmp.Echo)
+		if !ok || len(echo.Data) < 8 || (p.privileged && echo.ID != p.id) {
+			continue
+		}
+
+		sequence := binary.BigEndian.Uint64(echo.Data)
+		if echo.Seq != int(uint16(sequence)) {
+			continue
+		}
+
+		if req := p.take(sequence); req != nil {
+			req.stop()
+			req.done(ts.Sub(req.sent), nil)
+			p.inflight.Done()
+		}
+	}
+}
+
+// take removes the pending echo request (so that its outcome is reported only
+// once).  The one who takes the request must mark it done with the inflight
+// wait group once the outcome is reported.
+func (p *Prober) take(sequence uint64) *pending {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	req, ok := p.pending[sequence]
+	if !ok {
+		return nil
+	}
+	delete(p.pending, sequence)
+	return req
+}
+
+func addrIP(addr net.Addr) net.IP {
+	switch addr := addr.(type) {
+	case *net.UDPAddr:
+		return addr.IP
+	case *net.IPAddr:
+		return addr.IP
+	default:
+		return nil
+	}
+}
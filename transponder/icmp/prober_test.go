@@ -0,0 +1,108 @@
+package icmp_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/transponder/icmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type result struct {
+	rtt time.Duration
+	err error
+}
+
+func newProber(t *testing.T, local, peer net.IP) *icmp.Prober {
+	p, err := icmp.New("test", "lo", local, peer)
+	require.NoError(t, err)
+
+	failureSink := make(chan error, 1)
+	p.Run(context.Background(), failureSink)
+	select {
+	case err := <-failureSink:
+		t.Skipf("icmp sockets are not available: %v", err)
+	default:
+	}
+	t.Cleanup(func() { p.Stop(context.Background()) })
+
+	return p
+}
+
+func probe(ctx context.Context, p *icmp.Prober, sequence uint64) <-chan result {
+	res := make(chan result, 2) // room for the unexpected 2nd outcome
+	p.Probe(ctx, sequence, func(rtt time.Duration, err error) {
+		res <- result{rtt: rtt, err: err}
+	})
+	return res
+}
+
+func await(t *testing.T, res <-chan result) result {
+	select {
+	case r := <-res:
+		return r
+	case <-time.After(2 * time.Second):
+		t.Fatal("probe did not complete")
+		return result{}
+	}
+}
+
+func TestProberReplies(t *testing.T) {
+	a := newProber(t, net.IPv4(127, 0, 0, 1), net.IPv4(127, 0, 0, 1))
+	b := newProber(t, net.IPv4(127, 0, 0, 1), net.IPv4(127, 0, 0, 2))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// same echo sequence (lower 16 bits), and the same sequence with the
+	// other peer: every probe must get the outcome of its own reply
+	resA1 := probe(ctx, a, 1)
+	resA2 := probe(ctx, a, 1<<16+1)
+	resB1 := probe(ctx, b, 1)
+
+	for _, res := range []<-chan result{resA1, resA2, resB1} {
+		r := await(t, res)
+		assert.NoError(t, r.err)
+		assert.Greater(t, r.rtt, time.Duration(0))
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	for _, res := range []<-chan result{resA1, resA2, resB1} {
+		assert.Empty(t, res, "outcome must be reported only once")
+	}
+}
+
+func TestProberTimeout(t *testing.T) {
+	// nobody answers at test-net-3 (rfc5737)
+	p := newProber(t, net.IPv4zero, net.IPv4(203, 0, 113, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	r := await(t, probe(ctx, p, 1))
+	if r.err != nil && !errors.Is(r.err, context.DeadlineExceeded) {
+		t.Skipf("test-net-3 is not routable: %v", r.err)
+	}
+	assert.ErrorIs(t, r.err, context.DeadlineExceeded)
+	assert.Zero(t, r.rtt)
+}
+
+func TestProberStop(t *testing.T) {
+	p := newProber(t, net.IPv4zero, net.IPv4(203, 0, 113, 1))
+
+	// the outstanding probe is dropped without reporting
+	res := probe(context.Background(), p, 1)
+	if len(res) > 0 {
+		t.Skipf("test-net-3 is not routable: %v", (<-res).err)
+	}
+	p.Stop(context.Background())
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, res)
+
+	r := await(t, probe(context.Background(), p, 2))
+	assert.Error(t, r.err)
+}
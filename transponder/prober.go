@@ -0,0 +1,19 @@
+package transponder
+
+import (
+	"context"
+	"time"
+)
+
+// Prober checks the liveness of the tunnel's peer by other means than the
+// vpnham probes (e.g. when there's no vpnham running on the other side).
+type Prober interface {
+	InterfaceName() string
+
+	Run(ctx context.Context, failureSink chan<- error)
+	Stop(ctx context.Context)
+
+	// Probe checks the peer and reports the outcome via the callback (the
+	// round-trip time is zero if the prober does not measure it).
+	Probe(ctx context.Context, sequence uint64, done func(rtt time.Duration, err error))
+}
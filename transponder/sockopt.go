@@ -0,0 +1,56 @@
+package transponder
+
+import (
+	"net"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// SocketOptions are the options of the transponder's socket.
+type SocketOptions struct {
+	// BindToDevice is the name of the network device that the socket is bound
+	// to (so that the probes and the replies never leave via another one).
+	BindToDevice string
+
+	// Mark is the firewall mark of the outgoing packets (for policy routing).
+	Mark int
+
+	// DSCP is the differentiated services code point of the outgoing packets.
+	DSCP int
+
+	// TTL is the time-to-live (hop limit) of the outgoing packets.
+	TTL int
+}
+
+// setIPOptions applies the ip-level options (that are portable) to the open
+// socket.
+func setIPOptions(conn *net.UDPConn, isIPv6 bool, opts SocketOptions) error {
+	if isIPv6 {
+		c := ipv6.NewConn(conn)
+		if opts.DSCP != 0 {
+			if err := c.SetTrafficClass(opts.DSCP << 2); err != nil {
+				return err
+			}
+		}
+		if opts.TTL != 0 {
+			if err := c.SetHopLimit(opts.TTL); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	c := ipv4.NewConn(conn)
+	if opts.DSCP != 0 {
+		if err := c.SetTOS(opts.DSCP << 2); err != nil {
+			return err
+		}
+	}
+	if opts.TTL != 0 {
+		if err := c.SetTTL(opts.TTL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+//go:build linux
+
+package transponder
+
+import (
+	"syscall"
+)
+
+// control sets the socket options that must be in place before the socket is
+// bound.
+func control(opts SocketOptions) func(string, string, syscall.RawConn) error {
+	return func(_, _ string, raw syscall.RawConn) error {
+		var errSockopt error
+		err := raw.Control(func(fd uintptr) {
+			if opts.BindToDevice != "" {
+				if errSockopt = syscall.SetsockoptString(int(fd), syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, opts.BindToDevice); errSockopt != nil {
+					return
+				}
+			}
+
+			if opts.Mark != 0 {
+				errSockopt = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_MARK, opts.Mark)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		return errSockopt
+	}
+}
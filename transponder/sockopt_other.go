@@ -0,0 +1,26 @@
+//go:build !linux
+
+package transponder
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+var (
+	errSocketOptionIsNotSupported = errors.New("socket option is not supported on this platform")
+)
+
+// control rejects the socket options that are linux-specific.
+func control(opts SocketOptions) func(string, string, syscall.RawConn) error {
+	return func(_, _ string, _ syscall.RawConn) error {
+		if opts.BindToDevice != "" {
+			return fmt.Errorf("%w: bind to device", errSocketOptionIsNotSupported)
+		}
+		if opts.Mark != 0 {
+			return fmt.Errorf("%w: mark", errSocketOptionIsNotSupported)
+		}
+		return nil
+	}
+}
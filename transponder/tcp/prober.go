@@ -0,0 +1,136 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/flashbots/vpnham/logutils"
+	"go.uber.org/zap"
+)
+
+// Prober checks the peer by establishing (and immediately closing) the tcp
+// connection to it.  Useful when the other side of the tunnel does not run
+// vpnham, but has some tcp port open (e.g. ssh or bgp).
+type Prober struct {
+	name    string
+	ifsName string
+
+	localAddr *net.TCPAddr
+	peerAddr  *net.TCPAddr
+
+	mx        sync.Mutex
+	inflight  sync.WaitGroup
+	goingDown bool
+	done      chan struct{}
+}
+
+var (
+	errProberConfigIsInvalid = errors.New("tcp prober configuration is invalid")
+	errProberIsGoingDown     = errors.New("tcp prober is going down")
+)
+
+func New(name, ifsName string, localAddr, peerAddr *net.TCPAddr) (*Prober, error) {
+	if localAddr == nil || peerAddr == nil {
+		return nil, fmt.Errorf("%w: local and peer addresses are required",
+			errProberConfigIsInvalid,
+		)
+	}
+
+	return &Prober{
+		name:    name,
+		ifsName: ifsName,
+
+		localAddr: &net.TCPAddr{IP: localAddr.IP}, // any source port
+		peerAddr:  peerAddr,
+
+		done: make(chan struct{}),
+	}, nil
+}
+
+func (p *Prober) InterfaceName() string {
+	return p.ifsName
+}
+
+func (p *Prober) Run(ctx context.Context, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	l.Info("VPN HA-monitor tcp prober is going up...",
+		zap.String("tcp_local_addr", p.localAddr.String()),
+		zap.String("tcp_peer_addr", p.peerAddr.String()),
+	)
+}
+
+func (p *Prober) Stop(ctx context.Context) {
+	l := logutils.LoggerFromContext(ctx)
+
+	p.mx.Lock()
+	if p.goingDown {
+		p.mx.Unlock()
+		return
+	}
+	p.goingDown = true
+	p.mx.Unlock()
+
+	// abort the connections that are being established at the moment (their
+	// outcomes are dropped without reporting), and wait for the ones that
+	// are being reported
+	close(p.done)
+	p.inflight.Wait()
+
+	l.Info("VPN HA-monitor tcp prober is down",
+		zap.String("tcp_local_addr", p.localAddr.String()),
+	)
+}
+
+// Probe connects to the peer and reports the time it took for the handshake
+// to complete.  The probe fails if the connection is not established before
+// the context is done.
+func (p *Prober) Probe(ctx context.Context, _ uint64, done func(time.Duration, error)) {
+	p.mx.Lock()
+	if p.goingDown {
+		p.mx.Unlock()
+		done(0, errProberIsGoingDown)
+		return
+	}
+	p.inflight.Add(1)
+	p.mx.Unlock()
+
+	go func() {
+		defer p.inflight.Done()
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			select {
+			case <-p.done:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		dialer := &net.Dialer{LocalAddr: p.localAddr}
+
+		start := time.Now()
+		conn, err := dialer.DialContext(ctx, "tcp", p.peerAddr.String())
+		rtt := time.Since(start)
+		if conn != nil {
+			_ = conn.Close()
+		}
+
+		select {
+		case <-p.done:
+			return
+		default:
+		}
+
+		if err != nil {
+			done(0, err)
+			return
+		}
+		done(rtt, nil)
+	}()
+}
@@ -0,0 +1,65 @@
+package tcp_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/transponder/tcp"
+	"github.com/stretchr/testify/assert"
+)
+
+type result struct {
+	rtt time.Duration
+	err error
+}
+
+func probe(t *testing.T, p *tcp.Prober) result {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res := make(chan result, 1)
+	p.Probe(ctx, 1, func(rtt time.Duration, err error) {
+		res <- result{rtt: rtt, err: err}
+	})
+
+	select {
+	case r := <-res:
+		return r
+	case <-time.After(2 * time.Second):
+		t.Fatal("probe did not complete")
+		return result{}
+	}
+}
+
+func TestProber(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	peerAddr := ln.Addr().(*net.TCPAddr)
+
+	p, err := tcp.New("test", "eth0", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}, peerAddr)
+	assert.NoError(t, err)
+
+	p.Run(context.Background(), nil)
+
+	{ // peer is listening
+		r := probe(t, p)
+		assert.NoError(t, r.err)
+		assert.Greater(t, r.rtt, time.Duration(0))
+	}
+
+	assert.NoError(t, ln.Close())
+
+	{ // peer is gone
+		r := probe(t, p)
+		assert.Error(t, r.err)
+	}
+
+	p.Stop(context.Background())
+
+	{ // prober is stopped
+		r := probe(t, p)
+		assert.Error(t, r.err)
+	}
+}
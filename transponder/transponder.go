@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"syscall"
 
 	"github.com/flashbots/vpnham/logutils"
 	"github.com/flashbots/vpnham/metrics"
@@ -19,6 +20,7 @@ type Transponder struct {
 
 	ifsAddr *net.UDPAddr
 	ifsName string
+	opts    SocketOptions
 
 	conn      *net.UDPConn
 	goingDown bool
@@ -36,7 +38,7 @@ var (
 	errTransponderReceiverIsNotSet    = errors.New("transponder receiver method is not set")
 )
 
-func New(name, ifsName string, ifsAddr types.Address) (*Transponder, error) {
+func New(name, ifsName string, ifsAddr types.Address, opts SocketOptions) (*Transponder, error) {
 	ip, port, err := ifsAddr.Parse()
 	if err != nil {
 		return nil, err
@@ -47,6 +49,7 @@ func New(name, ifsName string, ifsAddr types.Address) (*Transponder, error) {
 
 		ifsName: ifsName,
 		ifsAddr: &net.UDPAddr{IP: ip, Port: port},
+		opts:    opts,
 	}, nil
 }
 
@@ -105,18 +108,18 @@ func (tp *Transponder) SendProbe(probe *types.Probe, to *net.UDPAddr, finalise f
 	}
 
 	go func() {
-		if _, err := tp.conn.WriteToUDP(b, to); err != nil {
+		_, err := tp.conn.WriteToUDP(b, to)
+		if err != nil && !errors.Is(err, syscall.EMSGSIZE) {
 			// try to recover
 			if err := tp.handleErrorOnSend(err); err != nil {
 				finalise(err)
+				return
 			}
 			// try to resend
-			if _, err := tp.conn.WriteToUDP(b, to); err != nil {
-				// if it fails again, pass the error downstream
-				finalise(err)
-			}
+			_, err = tp.conn.WriteToUDP(b, to)
 		}
-		finalise(nil)
+		// if it fails again, pass the error downstream
+		finalise(err)
 	}()
 }
 
@@ -124,7 +127,7 @@ func (tp *Transponder) connect() error {
 	if tp.conn != nil {
 		return errTransponderIsAlreadyConnected
 	}
-	conn, err := net.ListenUDP("udp", tp.ifsAddr)
+	conn, err := tp.open()
 	if err != nil {
 		return err
 	}
@@ -137,7 +140,7 @@ func (tp *Transponder) reconnect() error {
 		// ignore errors since we are reconnecting anyway
 		_ = tp.conn.Close()
 	}
-	conn, err := net.ListenUDP("udp", tp.ifsAddr)
+	conn, err := tp.open()
 	if err != nil {
 		return err
 	}
@@ -145,6 +148,30 @@ func (tp *Transponder) reconnect() error {
 	return nil
 }
 
+func (tp *Transponder) open() (*net.UDPConn, error) {
+	isIPv6 := tp.ifsAddr.IP.To4() == nil
+
+	lc := net.ListenConfig{
+		Control: control(tp.opts),
+	}
+	pc, err := lc.ListenPacket(context.Background(), "udp", tp.ifsAddr.String())
+	if err != nil {
+		return nil, err
+	}
+	conn := pc.(*net.UDPConn)
+
+	if err := setDontFragment(conn, isIPv6); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	if err := setIPOptions(conn, isIPv6, tp.opts); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
 func (tp *Transponder) disconnect() error {
 	if tp.conn == nil {
 		return nil
@@ -169,7 +196,7 @@ func (tp *Transponder) listen(ctx context.Context, failureSink chan<- error) {
 		return
 	}
 
-	buf := make([]byte, types.ProbeSize())
+	buf := make([]byte, types.MaxProbeSize())
 
 	l.Info("VPN HA-monitor transponder is going up...",
 		zap.String("transponder_addr", tp.ifsAddr.String()),
@@ -10,6 +10,9 @@ type BridgeStatus struct {
 	// Role is the configured role of the bridge.
 	Role Role `json:"role"`
 
+	// Location is the configured probe location of the bridge.
+	Location string `json:"location"`
+
 	// Active indicates whether the bridge is currently in active state.
 	Active bool `json:"active"`
 
@@ -24,8 +27,16 @@ type BridgeStatus struct {
 	// (regardless whether true or false).
 	UpSince time.Time `json:"up_since"`
 
+	// Preempting indicates that the bridge is due to reclaim the active state
+	// from its partner, and is waiting for the partner to release the lease.
+	Preempting bool `json:"preempting,omitempty"`
+
 	// Interfaces is the dictionary with bridge interface statuses.
 	Interfaces map[string]*TunnelInterfaceStatus `json:"interfaces"`
+
+	// Topology is the view of the bridges on both sides of the tunnels (only
+	// filled in when the status is served).
+	Topology *Topology `json:"topology,omitempty"`
 }
 
 func (b *BridgeStatus) ActiveInterface() string {
@@ -2,6 +2,8 @@ package types
 
 import (
 	"net"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -12,8 +14,23 @@ type Peer struct {
 	probeAddr *net.UDPAddr
 	uuid      uuid.UUID
 
+	mx sync.Mutex
+
 	sequence        uint64
 	acknowledgement uint64
+
+	stats ProbeStats
+
+	farEnd         *FarEndStatus
+	farEndSequence uint64
+}
+
+type PeerStats struct {
+	LossRatio   float64
+	Jitter      time.Duration
+	RTT         time.Duration
+	SmoothedRTT time.Duration
+	ClockOffset time.Duration
 }
 
 func NewPeer(iname string, addr Address) (*Peer, error) {
@@ -44,22 +61,98 @@ func (p *Peer) InterfaceName() string {
 }
 
 func (p *Peer) Sequence() uint64 {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
 	return p.sequence
 }
 
 func (p *Peer) NextSequence() uint64 {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
 	p.sequence += 1
+	p.stats.RegisterSent(p.sequence)
 	return p.sequence
 }
 
+// SetAcknowledgement moves the acknowledgement forward (it never goes back, so
+// that late probes do not cause the already missed ones to be reported again).
 func (p *Peer) SetAcknowledgement(ack uint64) {
-	p.acknowledgement = ack
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	if ack > p.acknowledgement {
+		p.acknowledgement = ack
+	}
 }
 
 func (p *Peer) Acknowledgement() uint64 {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
 	return p.acknowledgement
 }
 
+func (p *Peer) RegisterReturnedProbe(probe *Probe, ts time.Time) ProbeReturn {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	return p.stats.RegisterReturned(probe, ts)
+}
+
+func (p *Peer) RegisterProbeResult(sequence uint64, rtt time.Duration) ProbeReturn {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	return p.stats.RegisterResult(sequence, rtt)
+}
+
+// SetFarEnd remembers the state of the bridge at the far end of the tunnel
+// (unless the probe that reported it is older than the one we have already).
+func (p *Peer) SetFarEnd(sequence uint64, location Location, bridge *ProbeBridge, ts time.Time) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	if sequence < p.farEndSequence {
+		return
+	}
+
+	p.farEndSequence = sequence
+	p.farEnd = &FarEndStatus{
+		Location:        location.String(),
+		Active:          bridge.Active,
+		ActiveInterface: bridge.ActiveInterface,
+		UpdatedAt:       ts,
+	}
+}
+
+// FarEnd returns the latest known state of the bridge at the far end of the
+// tunnel (or nil if it was never reported).
+func (p *Peer) FarEnd() *FarEndStatus {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	if p.farEnd == nil {
+		return nil
+	}
+	farEnd := *p.farEnd
+	return &farEnd
+}
+
+func (p *Peer) Stats() PeerStats {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	return PeerStats{
+		LossRatio:   p.stats.LossRatio(),
+		Jitter:      p.stats.Jitter(),
+		RTT:         p.stats.RTT(),
+		SmoothedRTT: p.stats.SmoothedRTT(),
+		ClockOffset: p.stats.ClockOffset(),
+	}
+}
+
 func (p *Peer) ProbeAddr() *net.UDPAddr {
 	return p.probeAddr
 }
@@ -19,13 +19,48 @@ type Probe struct {
 	DstUUID      uuid.UUID
 	DstTimestamp time.Time
 	DstLocation  Location
+
+	// DstBridge is the state of the responder's bridge.  It's filled in by
+	// the responder into the padding (so the sender must reserve at least
+	// ProbeBridgeSize bytes for it), and is nil if the responder does not
+	// report it.
+	DstBridge *ProbeBridge
+
+	// Padding is the count of zero bytes appended to the probe (so that it
+	// can be used to check the path mtu).
+	Padding int
+}
+
+// ProbeBridge is the state of the bridge that responded to the probe.
+type ProbeBridge struct {
+	Active          bool
+	ActiveInterface string
 }
 
 func ProbeSize() int {
 	return 142
 }
 
+// ProbeBridgeSize is the size of the responder's bridge state at the start of
+// the padding.
+func ProbeBridgeSize() int {
+	return 20
+}
+
+var probeBridgeMagic = [2]byte{'v', 'h'}
+
+const (
+	probeBridgeFlagActive = 1 << iota
+)
+
+// MaxProbeSize is the max size of the probe with the padding (i.e. the max
+// payload of udp datagram).
+func MaxProbeSize() int {
+	return 65507
+}
+
 var (
+	errProbeBridgeActiveInterfaceIsTooLong     = errors.New("probe bridge active interface name is too long")
 	errProbeFailedToEncodeBinaryRepresentation = errors.New("failed to encode probe into its binary representation")
 	errProbeFailedToDecodeBinaryRepresentation = errors.New("failed to decode probe from its binary representation")
 )
@@ -44,7 +79,13 @@ func (p Probe) MarshalBinary() ([]byte, error) {
 		)
 	}
 
-	data := make([]byte, ProbeSize())
+	if p.Padding < 0 || ProbeSize()+p.Padding > MaxProbeSize() {
+		return nil, fmt.Errorf("%w: invalid padding: %d",
+			errProbeFailedToEncodeBinaryRepresentation, p.Padding,
+		)
+	}
+
+	data := make([]byte, ProbeSize()+p.Padding)
 
 	binary.LittleEndian.PutUint64(data[0:8], p.Sequence) // 000..007  :  8 bytes
 	copy(data[8:24], p.SrcUUID[:])                       // 008..023  : 16 bytes
@@ -54,13 +95,29 @@ func (p Probe) MarshalBinary() ([]byte, error) {
 	copy(data[91:106], rawDstTimestamp)                  // 091..105  : 15 bytes
 	copy(data[106:142], p.DstLocation[:])                // 106..142  : 36 bytes
 
+	if p.DstBridge != nil && p.Padding >= ProbeBridgeSize() {
+		if len(p.DstBridge.ActiveInterface) > 16 {
+			return nil, fmt.Errorf("%w: %w: %s",
+				errProbeFailedToEncodeBinaryRepresentation, errProbeBridgeActiveInterfaceIsTooLong, p.DstBridge.ActiveInterface,
+			)
+		}
+		var flags byte
+		if p.DstBridge.Active {
+			flags |= probeBridgeFlagActive
+		}
+		copy(data[142:144], probeBridgeMagic[:])                 // 142..143  :  2 bytes
+		data[144] = flags                                        // 144       :  1 byte
+		data[145] = byte(len(p.DstBridge.ActiveInterface))       // 145       :  1 byte
+		copy(data[146:162], []byte(p.DstBridge.ActiveInterface)) // 146..161  : 16 bytes
+	}
+
 	return data, nil
 }
 
 func (p *Probe) UnmarshalBinary(data []byte) error {
-	if len(data) != ProbeSize() {
-		return fmt.Errorf("%w: invalid binary length: expected %d, got %d",
-			errProbeFailedToDecodeBinaryRepresentation, ProbeSize(), len(data),
+	if len(data) < ProbeSize() || len(data) > MaxProbeSize() {
+		return fmt.Errorf("%w: invalid binary length: expected %d..%d, got %d",
+			errProbeFailedToDecodeBinaryRepresentation, ProbeSize(), MaxProbeSize(), len(data),
 		)
 	}
 
@@ -98,6 +155,16 @@ func (p *Probe) UnmarshalBinary(data []byte) error {
 	dstLocation := Location{}
 	copy(dstLocation[:], data[106:142])
 
+	var dstBridge *ProbeBridge
+	if len(data) >= ProbeSize()+ProbeBridgeSize() && [2]byte(data[142:144]) == probeBridgeMagic {
+		flags := data[144]
+		n := min(int(data[145]), 16)
+		dstBridge = &ProbeBridge{
+			Active:          flags&probeBridgeFlagActive != 0,
+			ActiveInterface: string(data[146 : 146+n]),
+		}
+	}
+
 	*p = Probe{
 		Sequence:     binary.LittleEndian.Uint64(data[:8]),
 		SrcUUID:      srcUUID,
@@ -106,6 +173,8 @@ func (p *Probe) UnmarshalBinary(data []byte) error {
 		DstUUID:      dstUUID,
 		DstTimestamp: *dstTimestamp,
 		DstLocation:  dstLocation,
+		DstBridge:    dstBridge,
+		Padding:      len(data) - ProbeSize(),
 	}
 
 	return nil
@@ -0,0 +1,148 @@
+package types
+
+import "time"
+
+// ProbeStatsWindow is the count of the latest probes that the loss ratio is
+// computed over (and that are tracked for duplicates).
+const ProbeStatsWindow = 64
+
+// ProbeStats keeps the rolling statistics of the probes sent to (and returned
+// from) a peer.
+type ProbeStats struct {
+	sent     uint64
+	received [ProbeStatsWindow]bool
+
+	highestReturned uint64
+
+	rtt         time.Duration
+	srtt        float64
+	jitter      float64
+	clockOffset time.Duration
+}
+
+// ProbeReturn is the outcome of the registration of a returned probe.
+type ProbeReturn struct {
+	Duplicate  bool
+	OutOfOrder bool
+
+	RTT         time.Duration
+	ClockOffset time.Duration
+}
+
+// RegisterSent marks the probe with the sequence as being in flight.
+func (s *ProbeStats) RegisterSent(sequence uint64) {
+	s.sent = sequence
+	s.received[sequence%ProbeStatsWindow] = false
+}
+
+// RegisterReturned accounts for the probe that came back at the timestamp.
+//
+// The round-trip time is measured with the local clock only (send → receive),
+// while the clock offset of the peer is estimated under the assumption that
+// the forward and the return trips take the same amount of time.
+func (s *ProbeStats) RegisterReturned(probe *Probe, ts time.Time) ProbeReturn {
+	rtt := ts.Sub(probe.SrcTimestamp)
+	clockOffset := probe.DstTimestamp.Sub(probe.SrcTimestamp.Add(rtt / 2))
+	return s.register(probe.Sequence, rtt, &clockOffset)
+}
+
+// RegisterResult accounts for the probe with the sequence that succeeded
+// (with zero round-trip time meaning that it is not known).
+func (s *ProbeStats) RegisterResult(sequence uint64, rtt time.Duration) ProbeReturn {
+	return s.register(sequence, rtt, nil)
+}
+
+func (s *ProbeStats) register(sequence uint64, rtt time.Duration, clockOffset *time.Duration) ProbeReturn {
+	res := ProbeReturn{
+		RTT: rtt,
+	}
+	if clockOffset != nil {
+		res.ClockOffset = *clockOffset
+	}
+
+	if sequence+ProbeStatsWindow <= s.sent {
+		// too late to tell whether it's a duplicate
+		res.OutOfOrder = true
+		return res
+	}
+
+	if sequence <= s.sent {
+		idx := sequence % ProbeStatsWindow
+		if s.received[idx] {
+			res.Duplicate = true
+			return res
+		}
+		s.received[idx] = true
+	}
+
+	if sequence < s.highestReturned {
+		res.OutOfOrder = true
+	} else {
+		s.highestReturned = sequence
+	}
+
+	if rtt == 0 {
+		return res
+	}
+
+	// rfc3550 (section 6.4.1) with rtt in place of the one-way transit time
+	if s.rtt != 0 {
+		d := float64(res.RTT - s.rtt)
+		if d < 0 {
+			d = -d
+		}
+		s.jitter += (d - s.jitter) / 16
+		s.srtt += (float64(res.RTT) - s.srtt) / 8 // rfc6298
+	} else {
+		s.srtt = float64(res.RTT)
+	}
+	s.rtt = res.RTT
+	if clockOffset != nil {
+		s.clockOffset = *clockOffset
+	}
+
+	return res
+}
+
+// LossRatio returns the ratio of the probes that did not come back among the
+// latest ones (the one that is still in flight is not accounted for).
+func (s *ProbeStats) LossRatio() float64 {
+	if s.sent < 2 {
+		return 0
+	}
+
+	first := uint64(1)
+	if s.sent > ProbeStatsWindow {
+		first = s.sent - ProbeStatsWindow + 1
+	}
+
+	lost, total := 0, 0
+	for sequence := first; sequence < s.sent; sequence++ {
+		if !s.received[sequence%ProbeStatsWindow] {
+			lost++
+		}
+		total++
+	}
+
+	return float64(lost) / float64(total)
+}
+
+// Jitter returns the smoothed variation of the round-trip time.
+func (s *ProbeStats) Jitter() time.Duration {
+	return time.Duration(s.jitter)
+}
+
+// RTT returns the round-trip time of the latest returned probe.
+func (s *ProbeStats) RTT() time.Duration {
+	return s.rtt
+}
+
+// SmoothedRTT returns the moving average of the round-trip time.
+func (s *ProbeStats) SmoothedRTT() time.Duration {
+	return time.Duration(s.srtt)
+}
+
+// ClockOffset returns the latest estimate of the peer's clock offset.
+func (s *ProbeStats) ClockOffset() time.Duration {
+	return s.clockOffset
+}
@@ -0,0 +1,87 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeStats(t *testing.T) {
+	stats := &types.ProbeStats{}
+	start := time.Now()
+
+	probe := func(sequence uint64) *types.Probe {
+		src := start.Add(time.Duration(sequence) * time.Second)
+		return &types.Probe{
+			Sequence:     sequence,
+			SrcTimestamp: src,
+			DstTimestamp: src.Add(15 * time.Millisecond), // peer's clock is 5ms ahead
+		}
+	}
+
+	for sequence := uint64(1); sequence <= 4; sequence++ {
+		stats.RegisterSent(sequence)
+	}
+
+	{ // in order
+		ret := stats.RegisterReturned(probe(1), probe(1).SrcTimestamp.Add(20*time.Millisecond))
+		assert.False(t, ret.Duplicate)
+		assert.False(t, ret.OutOfOrder)
+		assert.Equal(t, 20*time.Millisecond, ret.RTT)
+		assert.Equal(t, 5*time.Millisecond, ret.ClockOffset)
+		assert.Equal(t, time.Duration(0), stats.Jitter())
+	}
+
+	{ // skipping #2
+		ret := stats.RegisterReturned(probe(3), probe(3).SrcTimestamp.Add(36*time.Millisecond))
+		assert.False(t, ret.Duplicate)
+		assert.False(t, ret.OutOfOrder)
+		assert.Equal(t, time.Millisecond, stats.Jitter())         // (16ms - 0) / 16
+		assert.Equal(t, 22*time.Millisecond, stats.SmoothedRTT()) // 20ms + (36ms - 20ms) / 8
+	}
+
+	{ // late #2
+		ret := stats.RegisterReturned(probe(2), probe(2).SrcTimestamp.Add(20*time.Millisecond))
+		assert.False(t, ret.Duplicate)
+		assert.True(t, ret.OutOfOrder)
+	}
+
+	{ // duplicate #3
+		ret := stats.RegisterReturned(probe(3), probe(3).SrcTimestamp.Add(40*time.Millisecond))
+		assert.True(t, ret.Duplicate)
+	}
+
+	// #1..#3 came back, #4 is still in flight
+	assert.Equal(t, 0.0, stats.LossRatio())
+
+	stats.RegisterSent(5)
+	assert.Equal(t, 0.25, stats.LossRatio()) // #4 is lost
+}
+
+func TestProbeStatsWindow(t *testing.T) {
+	stats := &types.ProbeStats{}
+	start := time.Now()
+
+	for sequence := uint64(1); sequence <= 2*types.ProbeStatsWindow; sequence++ {
+		stats.RegisterSent(sequence)
+		if sequence%2 == 0 {
+			stats.RegisterReturned(&types.Probe{
+				Sequence:     sequence,
+				SrcTimestamp: start,
+				DstTimestamp: start,
+			}, start)
+		}
+	}
+	stats.RegisterSent(2*types.ProbeStatsWindow + 1)
+	assert.InDelta(t, 0.5, stats.LossRatio(), 0.01)
+
+	ret := stats.RegisterReturned(&types.Probe{
+		Sequence:     1,
+		SrcTimestamp: start,
+		DstTimestamp: start,
+	}, start)
+	assert.True(t, ret.OutOfOrder)
+	assert.False(t, ret.Duplicate)
+}
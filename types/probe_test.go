@@ -0,0 +1,90 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbe(t *testing.T) {
+	for _, padding := range []int{0, 1, 1500 - 28 - types.ProbeSize()} {
+		src := types.Probe{
+			Sequence:     42,
+			SrcUUID:      uuid.New(),
+			SrcTimestamp: time.Unix(1, 2).UTC(),
+			DstUUID:      uuid.New(),
+			Padding:      padding,
+		}
+
+		b, err := src.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Len(t, b, types.ProbeSize()+padding)
+
+		dst := types.Probe{}
+		assert.NoError(t, dst.UnmarshalBinary(b))
+		assert.Equal(t, src, dst)
+	}
+
+	_, err := types.Probe{Padding: types.MaxProbeSize()}.MarshalBinary()
+	assert.Error(t, err)
+
+	assert.Error(t, (&types.Probe{}).UnmarshalBinary(make([]byte, types.ProbeSize()-1)))
+}
+
+func TestProbeBridge(t *testing.T) {
+	src := types.Probe{
+		Sequence:     42,
+		SrcUUID:      uuid.New(),
+		SrcTimestamp: time.Unix(1, 2).UTC(),
+		DstUUID:      uuid.New(),
+		DstTimestamp: time.Unix(3, 4).UTC(),
+		DstBridge: &types.ProbeBridge{
+			Active:          true,
+			ActiveInterface: "wg0",
+		},
+		Padding: types.ProbeBridgeSize(),
+	}
+
+	{ // round trip
+		b, err := src.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Len(t, b, types.ProbeSize()+types.ProbeBridgeSize())
+
+		dst := types.Probe{}
+		assert.NoError(t, dst.UnmarshalBinary(b))
+		assert.Equal(t, src, dst)
+	}
+
+	{ // no room reserved by the sender => not reported
+		src := src
+		src.Padding = 0
+		b, err := src.MarshalBinary()
+		assert.NoError(t, err)
+
+		dst := types.Probe{}
+		assert.NoError(t, dst.UnmarshalBinary(b))
+		assert.Nil(t, dst.DstBridge)
+	}
+
+	{ // responders that do not report it leave the padding zeroed
+		src := src
+		src.DstBridge = nil
+		b, err := src.MarshalBinary()
+		assert.NoError(t, err)
+
+		dst := types.Probe{}
+		assert.NoError(t, dst.UnmarshalBinary(b))
+		assert.Nil(t, dst.DstBridge)
+		assert.Equal(t, types.ProbeBridgeSize(), dst.Padding)
+	}
+
+	{ // interface names are bounded
+		src := src
+		src.DstBridge = &types.ProbeBridge{ActiveInterface: "this-is-too-long-for-ifname"}
+		_, err := src.MarshalBinary()
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SignedBridgeStatus is the bridge status document that carries the signature
+// (so that the partner can tell that it's authentic and fresh).
+type SignedBridgeStatus struct {
+	// Status is the canonical json encoding of the BridgeStatus (exactly as
+	// it was signed).
+	Status json.RawMessage `json:"status"`
+
+	// Timestamp is when the document was signed.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Sequence increases with every document served by the bridge (including
+	// across the restarts).
+	Sequence uint64 `json:"sequence"`
+
+	// Nonce is the one provided by the partner with its poll request.
+	Nonce string `json:"nonce"`
+
+	// Signature is over all of the above.
+	Signature []byte `json:"signature"`
+}
@@ -0,0 +1,163 @@
+package types
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Topology is the view of the bridges on both sides of the tunnels: ours and
+// our partner's (as per their statuses), and the ones on the other side (as
+// reported in the probe responses over our and our partner's tunnels).
+type Topology struct {
+	Nodes []*TopologyNode `json:"nodes"`
+	Links []*TopologyLink `json:"links"`
+}
+
+type TopologyNode struct {
+	// Location is the probe location of the bridge (or its role, if the
+	// location is not configured on our side).
+	Location string `json:"location"`
+
+	// Side is either `local` (us and our partner) or `remote`.
+	Side string `json:"side"`
+
+	// Role is the configured role of the bridge (only known on our side).
+	Role Role `json:"role,omitempty"`
+
+	// Up indicates whether the bridge is up (only known on our side).
+	Up bool `json:"up"`
+
+	Active          bool   `json:"active"`
+	ActiveInterface string `json:"active_interface,omitempty"`
+
+	// UpdatedAt is the timestamp of the latest probe response that reported
+	// the state of the bridge (only on the remote side).
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+	// Stale indicates that the latest report is out of date (only on the
+	// remote side).
+	Stale bool `json:"stale,omitempty"`
+}
+
+type TopologyLink struct {
+	// Kind is either `tunnel` or `partner` (the link over which we poll our
+	// partner's status).
+	Kind string `json:"kind"`
+
+	// From is the location of the bridge on our side.
+	From string `json:"from"`
+
+	// Interface is the name of the tunnel interface on our side (empty for
+	// the partner link).
+	Interface string `json:"interface,omitempty"`
+
+	// To is the location of the bridge at the other end (empty if unknown).
+	To string `json:"to,omitempty"`
+
+	Up     bool `json:"up"`
+	Active bool `json:"active"`
+
+	// LossRatio and RTTUs are the probe stats of the tunnel.
+	LossRatio float64 `json:"loss_ratio"`
+	RTTUs     int64   `json:"rtt_us"`
+}
+
+const (
+	TopologyLinkPartner = "partner"
+	TopologyLinkTunnel  = "tunnel"
+
+	TopologySideLocal  = "local"
+	TopologySideRemote = "remote"
+)
+
+// NewTopology puts together the topology out of our status and the status of
+// our partner (which is nil if it is unknown).  The partner link is up while
+// the partner is reachable.
+func NewTopology(local, partner *BridgeStatus, partnerReachable bool) *Topology {
+	t := &Topology{
+		Nodes: make([]*TopologyNode, 0, 4),
+		Links: make([]*TopologyLink, 0, 2*len(local.Interfaces)+1),
+	}
+
+	remote := make(map[string]*TopologyNode)
+
+	add := func(b *BridgeStatus) string {
+		location := b.Location
+		if location == "" {
+			location = string(b.Role)
+		}
+
+		t.Nodes = append(t.Nodes, &TopologyNode{
+			Location:        location,
+			Side:            TopologySideLocal,
+			Role:            b.Role,
+			Up:              b.Up,
+			Active:          b.Active,
+			ActiveInterface: b.ActiveInterface(),
+		})
+
+		for ifsName, ifs := range b.Interfaces {
+			link := &TopologyLink{
+				Kind:      TopologyLinkTunnel,
+				From:      location,
+				Interface: ifsName,
+				Up:        ifs.Up,
+				Active:    ifs.Active,
+				LossRatio: ifs.LossRatio,
+				RTTUs:     ifs.RTTUs,
+			}
+			t.Links = append(t.Links, link)
+
+			if ifs.FarEnd == nil {
+				continue
+			}
+			link.To = ifs.FarEnd.Location
+
+			if node, known := remote[ifs.FarEnd.Location]; known && !node.UpdatedAt.Before(ifs.FarEnd.UpdatedAt) {
+				continue // we have more recent report already
+			}
+			updatedAt := ifs.FarEnd.UpdatedAt
+			remote[ifs.FarEnd.Location] = &TopologyNode{
+				Location:        ifs.FarEnd.Location,
+				Side:            TopologySideRemote,
+				Active:          ifs.FarEnd.Active && !ifs.FarEnd.Stale,
+				ActiveInterface: ifs.FarEnd.ActiveInterface,
+				UpdatedAt:       &updatedAt,
+				Stale:           ifs.FarEnd.Stale,
+			}
+		}
+
+		return location
+	}
+
+	from := add(local)
+	if partner != nil {
+		to := add(partner)
+		t.Links = append(t.Links, &TopologyLink{
+			Kind: TopologyLinkPartner,
+			From: from,
+			To:   to,
+			Up:   partnerReachable,
+		})
+	}
+
+	localCount := len(t.Nodes)
+	for _, node := range remote {
+		t.Nodes = append(t.Nodes, node)
+	}
+	slices.SortStableFunc(t.Nodes[localCount:], func(a, b *TopologyNode) int {
+		return cmp.Compare(a.Location, b.Location)
+	})
+	slices.SortStableFunc(t.Links, func(a, b *TopologyLink) int {
+		if c := cmp.Compare(b.Kind, a.Kind); c != 0 {
+			return c // tunnels first
+		}
+		if c := cmp.Compare(a.From, b.From); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Interface, b.Interface)
+	})
+
+	return t
+}
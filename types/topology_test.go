@@ -0,0 +1,75 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopology(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// left has promoted its standby, while right stays on its active
+	local := &types.BridgeStatus{
+		Role:     types.RoleStandby,
+		Location: "left/standby",
+		Active:   true,
+		Interfaces: map[string]*types.TunnelInterfaceStatus{
+			"wg0": {Up: true, FarEnd: &types.FarEndStatus{Location: "right/standby", UpdatedAt: ts}},
+			"wg1": {Up: true, Active: true, FarEnd: &types.FarEndStatus{Location: "right/active", Active: true, ActiveInterface: "wg1", UpdatedAt: ts}},
+		},
+	}
+	partner := &types.BridgeStatus{
+		Role:     types.RoleActive,
+		Location: "left/active",
+		Interfaces: map[string]*types.TunnelInterfaceStatus{
+			"wg0": {Up: false, FarEnd: &types.FarEndStatus{Location: "right/active", UpdatedAt: ts.Add(-time.Minute)}},
+			"wg1": {Up: false},
+		},
+	}
+
+	topology := types.NewTopology(local, partner, true)
+
+	locations := make([]string, 0, len(topology.Nodes))
+	for _, node := range topology.Nodes {
+		locations = append(locations, node.Location)
+	}
+	assert.Equal(t, []string{"left/standby", "left/active", "right/active", "right/standby"}, locations)
+
+	assert.Equal(t, "wg1", topology.Nodes[0].ActiveInterface)
+	assert.True(t, topology.Nodes[2].Active, "more recent report wins")
+	assert.Equal(t, types.TopologySideRemote, topology.Nodes[2].Side)
+
+	assert.Len(t, topology.Links, 5)
+	assert.Equal(t, &types.TopologyLink{Kind: types.TopologyLinkTunnel, From: "left/active", Interface: "wg0", To: "right/active"}, topology.Links[0])
+	assert.Equal(t, &types.TopologyLink{Kind: types.TopologyLinkTunnel, From: "left/active", Interface: "wg1"}, topology.Links[1])
+	assert.Equal(t, &types.TopologyLink{Kind: types.TopologyLinkTunnel, From: "left/standby", Interface: "wg1", To: "right/active", Up: true, Active: true}, topology.Links[3])
+	assert.Equal(t, &types.TopologyLink{Kind: types.TopologyLinkPartner, From: "left/standby", To: "left/active", Up: true}, topology.Links[4])
+
+	// partner unknown
+	topology = types.NewTopology(local, nil, false)
+	assert.Len(t, topology.Nodes, 3)
+	assert.Len(t, topology.Links, 2)
+}
+
+func TestTopologyStaleFarEnd(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	local := &types.BridgeStatus{
+		Role:     types.RoleActive,
+		Location: "left/active",
+		Interfaces: map[string]*types.TunnelInterfaceStatus{
+			"wg0": {Up: false, FarEnd: &types.FarEndStatus{Location: "right/active", Active: true, UpdatedAt: ts, Stale: true}},
+		},
+	}
+
+	topology := types.NewTopology(local, nil, false)
+
+	assert.Len(t, topology.Nodes, 2)
+	assert.Equal(t, "right/active", topology.Nodes[1].Location)
+	assert.False(t, topology.Nodes[1].Active, "stale far end is not active")
+	assert.True(t, topology.Nodes[1].Stale)
+	assert.Equal(t, "right/active", topology.Links[0].To)
+}
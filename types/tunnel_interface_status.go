@@ -16,4 +16,73 @@ type TunnelInterfaceStatus struct {
 	// UpSince is the timestamp of most recent update to the Up state
 	// (regardless whether true or false).
 	UpSince time.Time `json:"up_since"`
+
+	// Priority is the configured priority of the tunnel (the higher the more
+	// preferred).
+	Priority int `json:"priority"`
+
+	// SelectionPolicy is the policy by which the active tunnel is chosen
+	// (`role` or `quality`).
+	SelectionPolicy string `json:"selection_policy"`
+
+	// Score is the quality score of the tunnel (from 0 to 100, the higher the
+	// better;  only computed while the tunnel is up).
+	Score float64 `json:"score"`
+
+	// LossRatio is the ratio of the lost probes among the latest ones.
+	LossRatio float64 `json:"loss_ratio"`
+
+	// RTTUs is the smoothed round-trip time of the probes in microseconds.
+	RTTUs int64 `json:"rtt_us"`
+
+	// JitterUs is the jitter of the probes round-trip time in microseconds.
+	JitterUs int64 `json:"jitter_us"`
+
+	// Penalty is the flap penalty of the tunnel (only tracked if dampening is
+	// configured).
+	Penalty float64 `json:"penalty"`
+
+	// Suppressed indicates whether the tunnel is ineligible for activation
+	// due to flapping.
+	Suppressed bool `json:"suppressed"`
+
+	// FastProbing indicates whether the tunnel is probed at the fast rate
+	// (i.e. it has missed a probe and is not deemed healthy again yet).
+	FastProbing bool `json:"fast_probing"`
+
+	// PMTU is the largest packet size that made it through the tunnel and
+	// back during the latest path mtu check (only if the check is configured;
+	// 0 if none did).
+	PMTU int `json:"pmtu"`
+
+	// MTUDegraded indicates whether the path mtu of the tunnel is below the
+	// configured minimum.
+	MTUDegraded bool `json:"mtu_degraded"`
+
+	// LinkDown indicates whether the tunnel's link is down (according to the
+	// link state or to the wireguard handshakes, if watched for).
+	LinkDown bool `json:"link_down"`
+
+	// FarEnd is the state of the bridge at the other end of the tunnel (as
+	// reported in its probe responses;  nil if it does not report it).
+	FarEnd *FarEndStatus `json:"far_end,omitempty"`
+}
+
+type FarEndStatus struct {
+	// Location is the probe location of the bridge at the far end.
+	Location string `json:"location"`
+
+	// Active indicates whether the bridge at the far end is active.
+	Active bool `json:"active"`
+
+	// ActiveInterface is the far end bridge's active tunnel interface.
+	ActiveInterface string `json:"active_interface"`
+
+	// UpdatedAt is the timestamp of the probe response that reported it.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Stale indicates that the state is out of date (the tunnel is down, or
+	// there were no probe responses for a while).  The stale far end is not
+	// treated as active.
+	Stale bool `json:"stale,omitempty"`
 }
@@ -0,0 +1,189 @@
+package vici
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// Client talks to the IKE daemon (e.g. strongSwan's charon) over its vici
+// socket.
+type Client struct {
+	conn net.Conn
+	mx   sync.Mutex
+}
+
+const (
+	packetCmdRequest      = 0
+	packetCmdResponse     = 1
+	packetCmdUnknown      = 2
+	packetEventRegister   = 3
+	packetEventUnregister = 4
+	packetEventConfirm    = 5
+	packetEventUnknown    = 6
+	packetEvent           = 7
+)
+
+const (
+	maxPacketSize = 512 * 1024
+)
+
+var (
+	errCommandIsUnknown   = errors.New("vici command is unknown")
+	errEventIsUnknown     = errors.New("vici event is unknown")
+	errPacketIsInvalid    = errors.New("vici packet is invalid")
+	errPacketIsUnexpected = errors.New("vici packet is unexpected")
+)
+
+func Dial(ctx context.Context, socket string) (*Client, error) {
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "unix", socket)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Request sends the command and returns the response to it.
+func (c *Client) Request(ctx context.Context, cmd string, req Message) (Message, error) {
+	_, res, err := c.StreamedRequest(ctx, cmd, "", req)
+	return res, err
+}
+
+// StreamedRequest sends the command and collects the events that the daemon
+// streams back while processing it (e.g. `list-sa` events of `list-sas`
+// command).
+func (c *Client) StreamedRequest(ctx context.Context, cmd, evt string, req Message) ([]Message, Message, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.conn.SetDeadline(deadline)
+		defer c.conn.SetDeadline(time.Time{}) //nolint:errcheck
+	}
+
+	if evt != "" {
+		if err := c.register(packetEventRegister, evt); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	body, err := req.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.write(packetCmdRequest, cmd, body); err != nil {
+		return nil, nil, err
+	}
+
+	events := []Message{}
+	var res Message
+	for res == nil {
+		typ, name, body, err := c.read()
+		if err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case typ == packetCmdUnknown:
+			return nil, nil, fmt.Errorf("%w: %s", errCommandIsUnknown, cmd)
+		case typ == packetEvent && name == evt:
+			msg := Message{}
+			if err := msg.UnmarshalBinary(body); err != nil {
+				return nil, nil, err
+			}
+			events = append(events, msg)
+		case typ == packetCmdResponse:
+			res = Message{}
+			if err := res.UnmarshalBinary(body); err != nil {
+				return nil, nil, err
+			}
+		default:
+			return nil, nil, fmt.Errorf("%w: %d", errPacketIsUnexpected, typ)
+		}
+	}
+
+	if evt != "" {
+		if err := c.register(packetEventUnregister, evt); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return events, res, nil
+}
+
+func (c *Client) register(typ byte, evt string) error {
+	if err := c.write(typ, evt, nil); err != nil {
+		return err
+	}
+
+	for {
+		t, _, _, err := c.read()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case packetEventConfirm:
+			return nil
+		case packetEventUnknown:
+			return fmt.Errorf("%w: %s", errEventIsUnknown, evt)
+		case packetEvent:
+			// leftovers of the previous registration
+		default:
+			return fmt.Errorf("%w: %d", errPacketIsUnexpected, t)
+		}
+	}
+}
+
+func (c *Client) write(typ byte, name string, body []byte) error {
+	size := 1 + 1 + len(name) + len(body)
+	if size > maxPacketSize {
+		return fmt.Errorf("%w: too large: %d", errPacketIsInvalid, size)
+	}
+
+	packet := make([]byte, 0, 4+size)
+	packet = binary.BigEndian.AppendUint32(packet, uint32(size))
+	packet = append(packet, typ, byte(len(name)))
+	packet = append(packet, name...)
+	packet = append(packet, body...)
+
+	_, err := c.conn.Write(packet)
+	return err
+}
+
+func (c *Client) read() (byte, string, []byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(c.conn, header); err != nil {
+		return 0, "", nil, err
+	}
+	size := binary.BigEndian.Uint32(header)
+	if size < 1 || size > maxPacketSize {
+		return 0, "", nil, fmt.Errorf("%w: invalid size: %d", errPacketIsInvalid, size)
+	}
+
+	packet := make([]byte, size)
+	if _, err := io.ReadFull(c.conn, packet); err != nil {
+		return 0, "", nil, err
+	}
+
+	typ, packet := packet[0], packet[1:]
+	switch typ {
+	case packetCmdRequest, packetEventRegister, packetEventUnregister, packetEvent:
+		// named packets
+		if len(packet) < 1 || len(packet) < 1+int(packet[0]) {
+			return 0, "", nil, fmt.Errorf("%w: truncated name", errPacketIsInvalid)
+		}
+		nameSize := int(packet[0])
+		return typ, string(packet[1 : 1+nameSize]), packet[1+nameSize:], nil
+	}
+
+	return typ, "", packet, nil
+}
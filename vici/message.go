@@ -0,0 +1,243 @@
+package vici
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Message is the vici message.  Its values are either strings, lists of
+// strings, or nested messages (sections).
+type Message map[string]any
+
+const (
+	elementSectionStart = 1
+	elementSectionEnd   = 2
+	elementKeyValue     = 3
+	elementListStart    = 4
+	elementListItem     = 5
+	elementListEnd      = 6
+)
+
+var (
+	errMessageIsInvalid = errors.New("vici message is invalid")
+)
+
+// Get returns the string value at the path of keys (or empty string if there's
+// no such value).
+func (m Message) Get(keys ...string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+
+	section := m
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := section[key].(Message)
+		if !ok {
+			return ""
+		}
+		section = next
+	}
+
+	value, _ := section[keys[len(keys)-1]].(string)
+	return value
+}
+
+// Section returns the nested message at the key (or nil if there's none).
+func (m Message) Section(key string) Message {
+	section, _ := m[key].(Message)
+	return section
+}
+
+func (m Message) MarshalBinary() ([]byte, error) {
+	b := &bytes.Buffer{}
+	if err := m.encode(b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (m *Message) UnmarshalBinary(data []byte) error {
+	msg, _, err := decode(data, 0)
+	if err != nil {
+		return err
+	}
+	*m = msg
+	return nil
+}
+
+func (m Message) encode(b *bytes.Buffer) error {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch value := m[key].(type) {
+		case string:
+			b.WriteByte(elementKeyValue)
+			if err := encodeName(b, key); err != nil {
+				return err
+			}
+			if err := encodeValue(b, value); err != nil {
+				return err
+			}
+		case []string:
+			b.WriteByte(elementListStart)
+			if err := encodeName(b, key); err != nil {
+				return err
+			}
+			for _, item := range value {
+				b.WriteByte(elementListItem)
+				if err := encodeValue(b, item); err != nil {
+					return err
+				}
+			}
+			b.WriteByte(elementListEnd)
+		case Message:
+			b.WriteByte(elementSectionStart)
+			if err := encodeName(b, key); err != nil {
+				return err
+			}
+			if err := value.encode(b); err != nil {
+				return err
+			}
+			b.WriteByte(elementSectionEnd)
+		default:
+			return fmt.Errorf("%w: %s: unsupported value type: %T",
+				errMessageIsInvalid, key, value,
+			)
+		}
+	}
+
+	return nil
+}
+
+func encodeName(b *bytes.Buffer, name string) error {
+	if len(name) == 0 || len(name) > math.MaxUint8 {
+		return fmt.Errorf("%w: invalid name length: %d",
+			errMessageIsInvalid, len(name),
+		)
+	}
+	b.WriteByte(byte(len(name)))
+	b.WriteString(name)
+	return nil
+}
+
+func encodeValue(b *bytes.Buffer, value string) error {
+	if len(value) > math.MaxUint16 {
+		return fmt.Errorf("%w: invalid value length: %d",
+			errMessageIsInvalid, len(value),
+		)
+	}
+	b.Write(binary.BigEndian.AppendUint16(nil, uint16(len(value))))
+	b.WriteString(value)
+	return nil
+}
+
+// decode decodes the elements until the end of data (or until the end of the
+// section if depth > 0), and returns the rest of the data.
+func decode(data []byte, depth int) (Message, []byte, error) {
+	msg := make(Message)
+
+	var (
+		name string
+		err  error
+	)
+
+	for len(data) > 0 {
+		element := data[0]
+		data = data[1:]
+
+		switch element {
+		case elementSectionStart:
+			if name, data, err = decodeName(data); err != nil {
+				return nil, nil, err
+			}
+			var section Message
+			if section, data, err = decode(data, depth+1); err != nil {
+				return nil, nil, err
+			}
+			msg[name] = section
+
+		case elementSectionEnd:
+			if depth == 0 {
+				return nil, nil, fmt.Errorf("%w: unexpected section end",
+					errMessageIsInvalid,
+				)
+			}
+			return msg, data, nil
+
+		case elementKeyValue:
+			if name, data, err = decodeName(data); err != nil {
+				return nil, nil, err
+			}
+			var value string
+			if value, data, err = decodeValue(data); err != nil {
+				return nil, nil, err
+			}
+			msg[name] = value
+
+		case elementListStart:
+			if name, data, err = decodeName(data); err != nil {
+				return nil, nil, err
+			}
+			list := []string{}
+			for {
+				if len(data) == 0 {
+					return nil, nil, fmt.Errorf("%w: unterminated list: %s",
+						errMessageIsInvalid, name,
+					)
+				}
+				if data[0] == elementListEnd {
+					data = data[1:]
+					break
+				}
+				if data[0] != elementListItem {
+					return nil, nil, fmt.Errorf("%w: unexpected element in the list %s: %d",
+						errMessageIsInvalid, name, data[0],
+					)
+				}
+				var item string
+				if item, data, err = decodeValue(data[1:]); err != nil {
+					return nil, nil, err
+				}
+				list = append(list, item)
+			}
+			msg[name] = list
+
+		default:
+			return nil, nil, fmt.Errorf("%w: unexpected element: %d",
+				errMessageIsInvalid, element,
+			)
+		}
+	}
+
+	if depth > 0 {
+		return nil, nil, fmt.Errorf("%w: unterminated section",
+			errMessageIsInvalid,
+		)
+	}
+
+	return msg, nil, nil
+}
+
+func decodeName(data []byte) (string, []byte, error) {
+	if len(data) < 1 || len(data) < 1+int(data[0]) {
+		return "", nil, fmt.Errorf("%w: truncated name", errMessageIsInvalid)
+	}
+	size := int(data[0])
+	return string(data[1 : 1+size]), data[1+size:], nil
+}
+
+func decodeValue(data []byte) (string, []byte, error) {
+	if len(data) < 2 || len(data) < 2+int(binary.BigEndian.Uint16(data)) {
+		return "", nil, fmt.Errorf("%w: truncated value", errMessageIsInvalid)
+	}
+	size := int(binary.BigEndian.Uint16(data))
+	return string(data[2 : 2+size]), data[2+size:], nil
+}
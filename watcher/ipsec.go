@@ -0,0 +1,235 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/flashbots/vpnham/logutils"
+	"github.com/flashbots/vpnham/vici"
+	"go.uber.org/zap"
+)
+
+// IPsec watches the state of the CHILD_SA of the strongSwan connection (via
+// the vici socket).  The tunnel interface is deemed down while the IKE_SA is
+// not established, or while the CHILD_SA is missing or is not installed
+// (e.g. after its rekeying failed).
+//
+// When the CHILD_SA is initiated on demand, the one that is missing (or is
+// still being negotiated) is merely not established yet, and doesn't count as
+// the link being down.
+type IPsec struct {
+	ifsName    string
+	socket     string
+	connection string
+	child      string
+	initiate   bool
+	interval   time.Duration
+	onChange   OnChange
+
+	up bool
+
+	done    chan struct{}
+	once    sync.Once
+	running sync.WaitGroup
+}
+
+var (
+	errIPsecInitiateFailed = errors.New("failed to initiate ipsec sa")
+)
+
+func NewIPsec(ifsName, socket, connection, child string, initiate bool, interval time.Duration, onChange OnChange) (*IPsec, error) {
+	return &IPsec{
+		ifsName:    ifsName,
+		socket:     socket,
+		connection: connection,
+		child:      child,
+		initiate:   initiate,
+		interval:   interval,
+		onChange:   onChange,
+
+		up:   true,
+		done: make(chan struct{}),
+	}, nil
+}
+
+func (w *IPsec) Run(ctx context.Context, _ chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	l.Info("VPN HA-monitor ipsec watcher is going up...",
+		zap.String("tunnel_interface", w.ifsName),
+		zap.String("ipsec_connection", w.connection),
+		zap.String("ipsec_child", w.child),
+	)
+
+	w.running.Add(1)
+	go func() {
+		defer w.running.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.poll(ctx)
+
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (w *IPsec) Stop(ctx context.Context) {
+	l := logutils.LoggerFromContext(ctx)
+
+	w.once.Do(func() {
+		close(w.done)
+		w.running.Wait()
+		l.Info("VPN HA-monitor ipsec watcher is down",
+			zap.String("tunnel_interface", w.ifsName),
+		)
+	})
+}
+
+// Initiate asks the daemon to establish the CHILD_SA of the connection (and
+// waits until it's done).  It returns false if the CHILD_SA is established
+// already (so that it's not duplicated).
+func (w *IPsec) Initiate(ctx context.Context, timeout time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := vici.Dial(ctx, w.socket)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", errIPsecInitiateFailed, err)
+	}
+	defer client.Close()
+
+	sas, _, err := client.StreamedRequest(ctx, "list-sas", "list-sa", vici.Message{
+		"ike":     w.connection,
+		"noblock": "yes",
+	})
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", errIPsecInitiateFailed, err)
+	}
+	if state, _ := childSAState(sas, w.connection, w.child); state == saUp {
+		return false, nil
+	}
+
+	res, err := client.Request(ctx, "initiate", vici.Message{
+		"child":       w.child,
+		"ike":         w.connection,
+		"timeout":     strconv.FormatInt(timeout.Milliseconds(), 10),
+		"init-limits": "no",
+	})
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", errIPsecInitiateFailed, err)
+	}
+	if res.Get("success") != "yes" {
+		return false, fmt.Errorf("%w: %s", errIPsecInitiateFailed, res.Get("errmsg"))
+	}
+
+	return true, nil
+}
+
+func (w *IPsec) poll(ctx context.Context) {
+	l := logutils.LoggerFromContext(ctx)
+
+	state, reason, err := w.query(ctx)
+	if err != nil {
+		// the daemon might be restarting, the probes will tell
+		l.Warn("Failed to query ipsec sa state",
+			zap.Error(err),
+			zap.String("tunnel_interface", w.ifsName),
+		)
+		return
+	}
+
+	up := state == saUp || (state == saNotEstablished && w.initiate)
+	if up == w.up {
+		return
+	}
+	w.up = up
+
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+
+	w.onChange(w.ifsName, up, reason)
+}
+
+func (w *IPsec) query(ctx context.Context) (saState, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.interval)
+	defer cancel()
+
+	client, err := vici.Dial(ctx, w.socket)
+	if err != nil {
+		return saDown, "", err
+	}
+	defer client.Close()
+
+	sas, _, err := client.StreamedRequest(ctx, "list-sas", "list-sa", vici.Message{
+		"ike":     w.connection,
+		"noblock": "yes",
+	})
+	if err != nil {
+		return saDown, "", err
+	}
+
+	state, reason := childSAState(sas, w.connection, w.child)
+	return state, reason, nil
+}
+
+type saState int
+
+const (
+	saDown           saState = iota // failed or being torn down
+	saNotEstablished                // missing or still being negotiated
+	saUp
+)
+
+// childSAState interprets the `list-sa` events and tells whether the CHILD_SA
+// is up (or why it's not).
+func childSAState(sas []vici.Message, connection, child string) (saState, string) {
+	res, reason := saNotEstablished, "ipsec ike sa is missing"
+
+	for _, sa := range sas {
+		ike := sa.Section(connection)
+		if ike == nil {
+			continue
+		}
+		switch state := ike.Get("state"); state {
+		case "ESTABLISHED":
+		case "CREATED", "CONNECTING":
+			res, reason = saNotEstablished, "ipsec ike sa is "+state
+			continue
+		default:
+			res, reason = saDown, "ipsec ike sa is "+state
+			continue
+		}
+
+		res, reason = saNotEstablished, "ipsec child sa is missing"
+		for _, c := range ike.Section("child-sas") {
+			c, ok := c.(vici.Message)
+			if !ok || c.Get("name") != child {
+				continue
+			}
+			switch state := c.Get("state"); state {
+			case "INSTALLED", "REKEYING", "REKEYED":
+				return saUp, ""
+			case "CREATED", "ROUTED", "INSTALLING":
+				res, reason = saNotEstablished, "ipsec child sa is "+state
+			default:
+				res, reason = saDown, "ipsec child sa is "+state
+			}
+		}
+	}
+
+	return res, reason
+}
@@ -0,0 +1,220 @@
+package watcher_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/vici"
+	"github.com/flashbots/vpnham/watcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCharon is the minimal vici server that knows of `list-sas` and
+// `initiate` commands.
+type fakeCharon struct {
+	mx          sync.Mutex
+	child       string // state of the child sa (empty if there's none)
+	initiate    vici.Message
+	initiates   int
+	initiateErr string // installs the child sa on initiate if empty
+}
+
+func (f *fakeCharon) setChild(state string) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.child = state
+}
+
+func (f *fakeCharon) serve(t *testing.T) string {
+	socket := filepath.Join(t.TempDir(), "charon.vici")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.handle(conn)
+		}
+	}()
+
+	return socket
+}
+
+func (f *fakeCharon) handle(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		packet := make([]byte, binary.BigEndian.Uint32(header))
+		if _, err := io.ReadFull(conn, packet); err != nil {
+			return
+		}
+		typ, name := packet[0], string(packet[2:2+packet[1]])
+
+		switch typ {
+		case 3, 4: // event (un)register
+			write(conn, 5, "", nil) // event confirm
+		case 0: // command request
+			switch name {
+			case "list-sas":
+				f.mx.Lock()
+				sa := vici.Message{"state": "ESTABLISHED"}
+				if f.child != "" {
+					sa["child-sas"] = vici.Message{
+						"tunnel-1": vici.Message{"name": "tunnel", "state": f.child},
+					}
+				}
+				f.mx.Unlock()
+				write(conn, 7, "list-sa", vici.Message{"vpn": sa})
+				write(conn, 1, "", vici.Message{})
+			case "initiate":
+				req := vici.Message{}
+				_ = req.UnmarshalBinary(packet[2+packet[1]:])
+				f.mx.Lock()
+				f.initiate = req
+				f.initiates++
+				res := vici.Message{"success": "no", "errmsg": f.initiateErr}
+				if f.initiateErr == "" {
+					f.child = "INSTALLED"
+					res = vici.Message{"success": "yes"}
+				}
+				f.mx.Unlock()
+				write(conn, 1, "", res)
+			default:
+				write(conn, 2, "", nil) // command unknown
+			}
+		}
+	}
+}
+
+func write(conn net.Conn, typ byte, name string, msg vici.Message) {
+	body, _ := msg.MarshalBinary()
+	packet := []byte{typ}
+	if name != "" {
+		packet = append(packet, byte(len(name)))
+		packet = append(packet, name...)
+	}
+	packet = append(packet, body...)
+	_, _ = conn.Write(append(binary.BigEndian.AppendUint32(nil, uint32(len(packet))), packet...))
+}
+
+func TestIPsec(t *testing.T) {
+	charon := &fakeCharon{initiateErr: "peer is unreachable"}
+	socket := charon.serve(t)
+
+	type change struct {
+		up     bool
+		reason string
+	}
+	changes := make(chan change, 8)
+
+	w, err := watcher.NewIPsec("eth1", socket, "vpn", "tunnel", false, 10*time.Millisecond,
+		func(_ string, up bool, reason string) {
+			changes <- change{up: up, reason: reason}
+		},
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	w.Run(ctx, nil)
+	defer w.Stop(ctx)
+
+	next := func() change {
+		select {
+		case c := <-changes:
+			return c
+		case <-time.After(time.Second):
+			t.Fatal("no state change")
+			return change{}
+		}
+	}
+
+	assert.Equal(t, change{up: false, reason: "ipsec child sa is missing"}, next())
+
+	charon.setChild("INSTALLED")
+	assert.Equal(t, change{up: true}, next())
+
+	charon.setChild("DELETING")
+	assert.Equal(t, change{up: false, reason: "ipsec child sa is DELETING"}, next())
+
+	initiated, err := w.Initiate(ctx, time.Second)
+	assert.ErrorContains(t, err, "peer is unreachable")
+	assert.False(t, initiated)
+
+	charon.mx.Lock()
+	defer charon.mx.Unlock()
+	assert.Equal(t, vici.Message{
+		"child":       "tunnel",
+		"ike":         "vpn",
+		"init-limits": "no",
+		"timeout":     "1000",
+	}, charon.initiate)
+}
+
+func TestIPsecInitiate(t *testing.T) {
+	charon := &fakeCharon{} // the standby has no sa until it's initiated
+	socket := charon.serve(t)
+
+	changes := make(chan bool, 8)
+
+	w, err := watcher.NewIPsec("eth1", socket, "vpn", "tunnel", true, 10*time.Millisecond,
+		func(_ string, up bool, _ string) {
+			changes <- up
+		},
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	w.Run(ctx, nil)
+	defer w.Stop(ctx)
+
+	next := func() bool {
+		select {
+		case up := <-changes:
+			return up
+		case <-time.After(time.Second):
+			t.Fatal("no state change")
+			return false
+		}
+	}
+
+	// the missing sa is not established yet (the link is not down)
+	select {
+	case up := <-changes:
+		t.Fatalf("unexpected state change: up=%v", up)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	initiated, err := w.Initiate(ctx, time.Second)
+	require.NoError(t, err)
+	assert.True(t, initiated)
+
+	// the established sa is not initiated again
+	initiated, err = w.Initiate(ctx, time.Second)
+	require.NoError(t, err)
+	assert.False(t, initiated)
+
+	charon.setChild("DELETING")
+	assert.False(t, next())
+
+	charon.setChild("")
+	assert.True(t, next())
+
+	charon.mx.Lock()
+	defer charon.mx.Unlock()
+	assert.Equal(t, 1, charon.initiates)
+}
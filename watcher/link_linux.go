@@ -0,0 +1,246 @@
+//go:build linux
+
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/flashbots/vpnham/logutils"
+	"github.com/vishvananda/netlink"
+	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+)
+
+// LinkSubscribe subscribes to the link updates (until done is closed or the
+// subscription fails, in which case the updates channel is closed).
+type LinkSubscribe = func(ch chan<- netlink.LinkUpdate, done <-chan struct{}, options netlink.LinkSubscribeOptions) error
+
+// Link watches the link state of the network interfaces (via netlink
+// subscription to the link updates).
+type Link struct {
+	ifsNames  map[string]struct{}
+	onChange  OnChange
+	subscribe LinkSubscribe
+
+	mx    sync.Mutex
+	state map[string]bool
+
+	done    chan struct{}
+	once    sync.Once
+	running sync.WaitGroup
+}
+
+const (
+	linkResubscribeAttempts   = 8
+	linkResubscribeBackoff    = time.Second
+	linkResubscribeBackoffMax = 30 * time.Second
+)
+
+var (
+	errLinkSubscriptionFailed = errors.New("failed to subscribe to link updates")
+)
+
+func NewLink(ifsNames []string, onChange OnChange) (*Link, error) {
+	return NewLinkWithSubscribe(ifsNames, netlink.LinkSubscribeWithOptions, onChange)
+}
+
+// NewLinkWithSubscribe creates the link watcher that receives the link
+// updates via custom subscription.
+func NewLinkWithSubscribe(ifsNames []string, subscribe LinkSubscribe, onChange OnChange) (*Link, error) {
+	names := make(map[string]struct{}, len(ifsNames))
+	for _, ifsName := range ifsNames {
+		names[ifsName] = struct{}{}
+	}
+
+	return &Link{
+		ifsNames:  names,
+		onChange:  onChange,
+		subscribe: subscribe,
+
+		state: make(map[string]bool, len(ifsNames)),
+		done:  make(chan struct{}),
+	}, nil
+}
+
+func (w *Link) Run(ctx context.Context, failureSink chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	updates, stop, err := w.subscribeOnce(ctx)
+	if err != nil {
+		failureSink <- fmt.Errorf("link watcher: %w", err)
+		return
+	}
+
+	l.Info("VPN HA-monitor link watcher is going up...")
+
+	w.running.Add(1)
+	go func() {
+		defer w.running.Done()
+
+		for {
+			w.watch(updates, stop)
+
+			select {
+			case <-w.done:
+				return
+			default:
+			}
+
+			updates, stop, err = w.resubscribe(ctx)
+			if err != nil {
+				select {
+				case failureSink <- fmt.Errorf("link watcher: %w", err):
+				case <-w.done:
+				}
+				return
+			}
+		}
+	}()
+}
+
+func (w *Link) Stop(ctx context.Context) {
+	l := logutils.LoggerFromContext(ctx)
+
+	w.once.Do(func() {
+		close(w.done)
+		w.running.Wait()
+		l.Info("VPN HA-monitor link watcher is down")
+	})
+}
+
+func (w *Link) subscribeOnce(ctx context.Context) (<-chan netlink.LinkUpdate, chan struct{}, error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	updates := make(chan netlink.LinkUpdate, 16)
+	stop := make(chan struct{})
+
+	err := w.subscribe(updates, stop, netlink.LinkSubscribeOptions{
+		ListExisting: true, // re-sync the state of the links we might have missed
+		ErrorCallback: func(err error) {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			l.Error("Link watcher failed to receive link update",
+				zap.Error(err),
+			)
+		},
+	})
+	if err != nil {
+		close(stop)
+		return nil, nil, fmt.Errorf("%w: %w", errLinkSubscriptionFailed, err)
+	}
+
+	return updates, stop, nil
+}
+
+// resubscribe re-establishes the subscription to the link updates (with
+// exponential backoff between the attempts).
+func (w *Link) resubscribe(ctx context.Context) (<-chan netlink.LinkUpdate, chan struct{}, error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	backoff := linkResubscribeBackoff
+	var err error
+	for attempt := 1; attempt <= linkResubscribeAttempts; attempt++ {
+		l.Warn("Link watcher lost its subscription to link updates; resubscribing...",
+			zap.Duration("backoff", backoff),
+			zap.Int("attempt", attempt),
+		)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-w.done:
+			timer.Stop()
+			return nil, nil, nil
+		case <-timer.C:
+		}
+
+		var (
+			updates <-chan netlink.LinkUpdate
+			stop    chan struct{}
+		)
+		updates, stop, err = w.subscribeOnce(ctx)
+		if err == nil {
+			l.Info("Link watcher resubscribed to link updates")
+			return updates, stop, nil
+		}
+
+		l.Error("Link watcher failed to resubscribe to link updates",
+			zap.Error(err),
+			zap.Int("attempt", attempt),
+		)
+
+		backoff = min(2*backoff, linkResubscribeBackoffMax)
+	}
+
+	return nil, nil, err
+}
+
+// watch processes the link updates until the subscription ends (or until the
+// watcher is stopped).
+func (w *Link) watch(updates <-chan netlink.LinkUpdate, stop chan struct{}) {
+	if updates == nil {
+		return
+	}
+
+	defer func() {
+		close(stop)
+		for range updates { // unblock the subscription so that it could end
+		}
+	}()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case u, ok := <-updates:
+			if !ok {
+				return
+			}
+			w.process(u)
+		}
+	}
+}
+
+func (w *Link) process(u netlink.LinkUpdate) {
+	attrs := u.Attrs()
+	if _, watched := w.ifsNames[attrs.Name]; !watched {
+		return
+	}
+
+	up, reason := true, ""
+	switch {
+	case u.Header.Type == unix.RTM_DELLINK:
+		up, reason = false, "link is deleted"
+	case attrs.Flags&net.FlagUp == 0:
+		up, reason = false, "link is administratively down"
+	case attrs.OperState == netlink.OperDown ||
+		attrs.OperState == netlink.OperLowerLayerDown ||
+		attrs.OperState == netlink.OperNotPresent:
+		// tunnels (e.g. wireguard) report "unknown" operational state
+		up, reason = false, "link is "+attrs.OperState.String()
+	}
+
+	w.mx.Lock()
+	was, known := w.state[attrs.Name]
+	w.state[attrs.Name] = up
+	w.mx.Unlock()
+
+	if (known && was == up) || (!known && up) {
+		return
+	}
+
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+
+	w.onChange(attrs.Name, up, reason)
+}
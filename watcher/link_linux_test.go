@@ -0,0 +1,158 @@
+//go:build linux
+
+package watcher_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/watcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// fakeSubscription stands in for the netlink subscription to link updates.
+type fakeSubscription struct {
+	updates chan<- netlink.LinkUpdate
+	once    sync.Once
+}
+
+func (s *fakeSubscription) fail() {
+	s.once.Do(func() { close(s.updates) })
+}
+
+type fakeNetlink struct {
+	subscriptions chan *fakeSubscription
+	err           error
+}
+
+func newFakeNetlink() *fakeNetlink {
+	return &fakeNetlink{
+		subscriptions: make(chan *fakeSubscription, 4),
+	}
+}
+
+func (f *fakeNetlink) subscribe(ch chan<- netlink.LinkUpdate, done <-chan struct{}, _ netlink.LinkSubscribeOptions) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	s := &fakeSubscription{updates: ch}
+	go func() {
+		<-done
+		s.fail()
+	}()
+	f.subscriptions <- s
+
+	return nil
+}
+
+func linkUpdate(typ uint16, name string, flags net.Flags, state netlink.LinkOperState) netlink.LinkUpdate {
+	return netlink.LinkUpdate{
+		Header: unix.NlMsghdr{Type: typ},
+		Link: &netlink.Device{LinkAttrs: netlink.LinkAttrs{
+			Name:      name,
+			Flags:     flags,
+			OperState: state,
+		}},
+	}
+}
+
+func nextSubscription(t *testing.T, f *fakeNetlink, timeout time.Duration) *fakeSubscription {
+	t.Helper()
+
+	select {
+	case s := <-f.subscriptions:
+		return s
+	case <-time.After(timeout):
+		require.FailNow(t, "timed out waiting for the subscription")
+		return nil
+	}
+}
+
+func TestLinkTranslatesUpdates(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeNetlink()
+	onChange, changes := recordChanges()
+
+	w, err := watcher.NewLinkWithSubscribe([]string{"wg0", "wg1"}, f.subscribe, onChange)
+	require.NoError(t, err)
+
+	failureSink := make(chan error, 1)
+	w.Run(ctx, failureSink)
+	defer w.Stop(ctx)
+
+	s := nextSubscription(t, f, time.Second)
+
+	// links that are first seen up, and links that are not watched, are quiet
+	s.updates <- linkUpdate(unix.RTM_NEWLINK, "wg0", net.FlagUp, netlink.OperUnknown)
+	s.updates <- linkUpdate(unix.RTM_NEWLINK, "eth0", 0, netlink.OperDown)
+	s.updates <- linkUpdate(unix.RTM_NEWLINK, "wg1", net.FlagUp, netlink.OperLowerLayerDown)
+	assert.Equal(t, change{"wg1", false, "link is lower-layer-down"}, nextChange(t, changes, time.Second))
+
+	s.updates <- linkUpdate(unix.RTM_NEWLINK, "wg0", 0, netlink.OperDown)
+	assert.Equal(t, change{"wg0", false, "link is administratively down"}, nextChange(t, changes, time.Second))
+
+	// the link that is already down stays quiet
+	s.updates <- linkUpdate(unix.RTM_NEWLINK, "wg0", net.FlagUp, netlink.OperDown)
+	s.updates <- linkUpdate(unix.RTM_NEWLINK, "wg0", net.FlagUp, netlink.OperUnknown)
+	assert.Equal(t, change{"wg0", true, ""}, nextChange(t, changes, time.Second))
+
+	s.updates <- linkUpdate(unix.RTM_DELLINK, "wg0", net.FlagUp, netlink.OperUnknown)
+	assert.Equal(t, change{"wg0", false, "link is deleted"}, nextChange(t, changes, time.Second))
+
+	assert.Empty(t, changes)
+	assert.Empty(t, failureSink)
+}
+
+func TestLinkResubscribes(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeNetlink()
+	onChange, changes := recordChanges()
+
+	w, err := watcher.NewLinkWithSubscribe([]string{"wg0"}, f.subscribe, onChange)
+	require.NoError(t, err)
+
+	failureSink := make(chan error, 1)
+	w.Run(ctx, failureSink)
+	defer w.Stop(ctx)
+
+	s := nextSubscription(t, f, time.Second)
+	s.updates <- linkUpdate(unix.RTM_NEWLINK, "wg0", 0, netlink.OperDown)
+	assert.Equal(t, change{"wg0", false, "link is administratively down"}, nextChange(t, changes, time.Second))
+
+	s.fail() // e.g. netlink socket overrun
+
+	s = nextSubscription(t, f, 5*time.Second)
+	s.updates <- linkUpdate(unix.RTM_NEWLINK, "wg0", net.FlagUp, netlink.OperUp)
+	assert.Equal(t, change{"wg0", true, ""}, nextChange(t, changes, time.Second))
+
+	assert.Empty(t, failureSink)
+}
+
+func TestLinkFailsToSubscribe(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeNetlink()
+	f.err = errors.New("operation not permitted")
+	onChange, _ := recordChanges()
+
+	w, err := watcher.NewLinkWithSubscribe([]string{"wg0"}, f.subscribe, onChange)
+	require.NoError(t, err)
+
+	failureSink := make(chan error, 1)
+	w.Run(ctx, failureSink)
+	defer w.Stop(ctx)
+
+	select {
+	case err := <-failureSink:
+		assert.ErrorContains(t, err, "operation not permitted")
+	default:
+		assert.Fail(t, "expected the failure to be reported")
+	}
+}
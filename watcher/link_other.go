@@ -0,0 +1,22 @@
+//go:build !linux
+
+package watcher
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	errLinkWatcherIsNotSupported = errors.New("link watcher is not supported on this platform")
+)
+
+type Link struct{}
+
+func NewLink(_ []string, _ OnChange) (*Link, error) {
+	return nil, errLinkWatcherIsNotSupported
+}
+
+func (w *Link) Run(_ context.Context, _ chan<- error) {}
+
+func (w *Link) Stop(_ context.Context) {}
@@ -0,0 +1,11 @@
+package watcher
+
+// OnChange is invoked every time the watched interface changes its state
+// (with the reason of the change if the interface went down).
+type OnChange = func(ifsName string, up bool, reason string)
+
+const (
+	SourceIPsec     = "ipsec"
+	SourceLink      = "link"
+	SourceWireGuard = "wireguard"
+)
@@ -0,0 +1,166 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flashbots/vpnham/logutils"
+	"go.uber.org/zap"
+	"golang.zx2c4.com/wireguard/wgctrl"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+// WireGuardClient queries the state of the wireguard devices.
+type WireGuardClient interface {
+	Device(name string) (*wgtypes.Device, error)
+	Close() error
+}
+
+// WireGuard watches the age of the latest handshake of the wireguard
+// interface (the interface is deemed down once the latest handshake with any
+// of its peers is older than the timeout).
+type WireGuard struct {
+	ifsName  string
+	interval time.Duration
+	timeout  time.Duration
+	onChange OnChange
+
+	dial   func() (WireGuardClient, error)
+	client WireGuardClient
+	up     bool
+
+	done    chan struct{}
+	once    sync.Once
+	running sync.WaitGroup
+}
+
+func NewWireGuard(ifsName string, interval, timeout time.Duration, onChange OnChange) (*WireGuard, error) {
+	dial := func() (WireGuardClient, error) {
+		return wgctrl.New()
+	}
+	return newWireGuard(ifsName, dial, interval, timeout, onChange), nil
+}
+
+// NewWireGuardWithClient creates the wireguard watcher that queries the
+// devices via custom client.
+func NewWireGuardWithClient(ifsName string, client WireGuardClient, interval, timeout time.Duration, onChange OnChange) (*WireGuard, error) {
+	dial := func() (WireGuardClient, error) {
+		return client, nil
+	}
+	return newWireGuard(ifsName, dial, interval, timeout, onChange), nil
+}
+
+func newWireGuard(ifsName string, dial func() (WireGuardClient, error), interval, timeout time.Duration, onChange OnChange) *WireGuard {
+	return &WireGuard{
+		ifsName:  ifsName,
+		interval: interval,
+		timeout:  timeout,
+		onChange: onChange,
+		dial:     dial,
+
+		up:   true,
+		done: make(chan struct{}),
+	}
+}
+
+func (w *WireGuard) Run(ctx context.Context, failureSink chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	client, err := w.dial()
+	if err != nil {
+		failureSink <- fmt.Errorf("%s: wireguard watcher: %w", w.ifsName, err)
+		return
+	}
+	w.client = client
+
+	l.Info("VPN HA-monitor wireguard watcher is going up...",
+		zap.String("tunnel_interface", w.ifsName),
+	)
+
+	w.running.Add(1)
+	go func() {
+		defer w.running.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.poll(ctx)
+
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (w *WireGuard) Stop(ctx context.Context) {
+	l := logutils.LoggerFromContext(ctx)
+
+	w.once.Do(func() {
+		close(w.done)
+		w.running.Wait()
+		if w.client == nil {
+			return
+		}
+		if err := w.client.Close(); err != nil {
+			l.Error("VPN HA-monitor wireguard watcher shutdown failed",
+				zap.Error(err),
+				zap.String("tunnel_interface", w.ifsName),
+			)
+		}
+		l.Info("VPN HA-monitor wireguard watcher is down",
+			zap.String("tunnel_interface", w.ifsName),
+		)
+	})
+}
+
+func (w *WireGuard) poll(ctx context.Context) {
+	l := logutils.LoggerFromContext(ctx)
+
+	dev, err := w.client.Device(w.ifsName)
+	if err != nil {
+		// the link watcher (if any) is there for the missing interfaces
+		l.Warn("Failed to query wireguard device",
+			zap.Error(err),
+			zap.String("tunnel_interface", w.ifsName),
+		)
+		return
+	}
+
+	latest := time.Time{}
+	for _, peer := range dev.Peers {
+		if peer.LastHandshakeTime.After(latest) {
+			latest = peer.LastHandshakeTime
+		}
+	}
+
+	age := time.Since(latest)
+	up := !latest.IsZero() && age < w.timeout
+	if up == w.up {
+		return
+	}
+	w.up = up
+
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+
+	reason := ""
+	switch {
+	case up:
+		// noop
+	case latest.IsZero():
+		reason = "no wireguard handshake yet"
+	default:
+		reason = fmt.Sprintf("latest wireguard handshake is %s old", age.Truncate(time.Second))
+	}
+
+	w.onChange(w.ifsName, up, reason)
+}
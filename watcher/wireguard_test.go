@@ -0,0 +1,119 @@
+package watcher_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/watcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+type change struct {
+	ifsName string
+	up      bool
+	reason  string
+}
+
+func recordChanges() (watcher.OnChange, chan change) {
+	changes := make(chan change, 16)
+	return func(ifsName string, up bool, reason string) {
+		changes <- change{ifsName: ifsName, up: up, reason: reason}
+	}, changes
+}
+
+func nextChange(t *testing.T, changes chan change, timeout time.Duration) change {
+	t.Helper()
+
+	select {
+	case c := <-changes:
+		return c
+	case <-time.After(timeout):
+		require.FailNow(t, "timed out waiting for the interface state change")
+		return change{}
+	}
+}
+
+// fakeWireGuard is the wireguard client that reports the device with a pair
+// of peers (one of which never completes the handshake).
+type fakeWireGuard struct {
+	mx        sync.Mutex
+	handshake time.Time
+	err       error
+	closed    bool
+}
+
+func (f *fakeWireGuard) setHandshake(ts time.Time) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.handshake = ts
+}
+
+func (f *fakeWireGuard) setErr(err error) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.err = err
+}
+
+func (f *fakeWireGuard) Device(name string) (*wgtypes.Device, error) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &wgtypes.Device{
+		Name: name,
+		Peers: []wgtypes.Peer{
+			{LastHandshakeTime: time.Time{}},
+			{LastHandshakeTime: f.handshake},
+		},
+	}, nil
+}
+
+func (f *fakeWireGuard) Close() error {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.closed = true
+	return nil
+}
+
+func TestWireGuardHandshakeStaleness(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeWireGuard{}
+	onChange, changes := recordChanges()
+
+	w, err := watcher.NewWireGuardWithClient("wg0", f, 10*time.Millisecond, 200*time.Millisecond, onChange)
+	require.NoError(t, err)
+
+	failureSink := make(chan error, 1)
+	w.Run(ctx, failureSink)
+
+	assert.Equal(t, change{"wg0", false, "no wireguard handshake yet"}, nextChange(t, changes, time.Second))
+
+	f.setHandshake(time.Now())
+	assert.Equal(t, change{"wg0", true, ""}, nextChange(t, changes, time.Second))
+
+	// the handshake is not renewed, so it goes stale
+	c := nextChange(t, changes, time.Second)
+	assert.Equal(t, "wg0", c.ifsName)
+	assert.False(t, c.up)
+	assert.Contains(t, c.reason, "latest wireguard handshake is")
+
+	// failures to query the device do not change the state
+	f.setErr(errors.New("no such device"))
+	f.setHandshake(time.Now())
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, changes)
+
+	f.setErr(nil)
+	assert.Equal(t, change{"wg0", true, ""}, nextChange(t, changes, time.Second))
+
+	w.Stop(ctx)
+	assert.True(t, f.closed)
+	assert.Empty(t, failureSink)
+}
@@ -0,0 +1,83 @@
+package witness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Client is the bridge's side of the conversation with the witness.
+type Client struct {
+	url    *url.URL
+	bridge string
+	holder string
+
+	http *http.Client
+}
+
+var (
+	errWitnessResponseIsUnexpected = errors.New("unexpected witness response")
+	errWitnessURLIsInvalid         = errors.New("witness url is invalid")
+)
+
+func NewClient(witnessURL, bridge, holder string, timeout time.Duration) (*Client, error) {
+	_url, err := url.Parse(witnessURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w",
+			errWitnessURLIsInvalid, err,
+		)
+	}
+
+	return &Client{
+		url:    _url.JoinPath(pathLease),
+		bridge: bridge,
+		holder: holder,
+
+		http: &http.Client{
+			Timeout: timeout,
+		},
+	}, nil
+}
+
+// Request reports to the witness that we are alive, and (optionally) asks
+// for the active lease.
+func (c *Client) Request(ctx context.Context, acquire bool) (*Response, error) {
+	body, err := json.Marshal(&Request{
+		Bridge:  c.bridge,
+		Holder:  c.holder,
+		Acquire: acquire,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("content-type", "application/json")
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s",
+			errWitnessResponseIsUnexpected, res.Status,
+		)
+	}
+
+	lease := &Response{}
+	if err := json.NewDecoder(res.Body).Decode(lease); err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
@@ -0,0 +1,95 @@
+package witness
+
+import (
+	"sync"
+	"time"
+)
+
+// Request is what the bridge sends to the witness on every tick.
+type Request struct {
+	// Bridge is the name of the bridge (same at both partners).
+	Bridge string `json:"bridge"`
+
+	// Holder identifies the partner (i.e. its role).
+	Holder string `json:"holder"`
+
+	// Acquire is true when the partner wants to acquire (or to renew) the
+	// active lease, and false when it merely reports that it's alive (which
+	// also releases the lease if the partner was holding it).
+	Acquire bool `json:"acquire"`
+}
+
+// Response is what the witness tells the bridge.
+type Response struct {
+	// Granted indicates whether the bridge holds the active lease.
+	Granted bool `json:"granted"`
+
+	// Holder is the current holder of the active lease (if any).
+	Holder string `json:"holder"`
+
+	// Expires is when the active lease expires (unless renewed).
+	Expires time.Time `json:"expires"`
+
+	// PartnerSeen indicates whether the witness heard from the partner within
+	// the lease duration.
+	PartnerSeen bool `json:"partner_seen"`
+}
+
+// Leases grants one active lease per bridge at a time.
+type Leases struct {
+	duration time.Duration
+
+	mx      sync.Mutex
+	bridges map[string]*lease
+}
+
+type lease struct {
+	holder  string
+	expires time.Time
+	seen    map[string]time.Time
+}
+
+func NewLeases(duration time.Duration) *Leases {
+	return &Leases{
+		duration: duration,
+		bridges:  make(map[string]*lease),
+	}
+}
+
+// Process handles the request of the bridge.
+func (l *Leases) Process(req *Request, now time.Time) *Response {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	b, ok := l.bridges[req.Bridge]
+	if !ok {
+		b = &lease{seen: make(map[string]time.Time, 2)}
+		l.bridges[req.Bridge] = b
+	}
+	b.seen[req.Holder] = now
+
+	if !now.Before(b.expires) {
+		b.holder, b.expires = "", time.Time{}
+	}
+
+	switch {
+	case req.Acquire && (b.holder == "" || b.holder == req.Holder):
+		b.holder, b.expires = req.Holder, now.Add(l.duration)
+	case !req.Acquire && b.holder == req.Holder:
+		b.holder, b.expires = "", time.Time{}
+	}
+
+	partnerSeen := false
+	for holder, ts := range b.seen {
+		if holder != req.Holder && now.Sub(ts) < l.duration {
+			partnerSeen = true
+		}
+	}
+
+	return &Response{
+		Granted:     b.holder == req.Holder,
+		Holder:      b.holder,
+		Expires:     b.expires,
+		PartnerSeen: partnerSeen,
+	}
+}
@@ -0,0 +1,73 @@
+package witness_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flashbots/vpnham/witness"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeases(t *testing.T) {
+	leases := witness.NewLeases(10 * time.Second)
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	req := func(holder string, acquire bool, after time.Duration) *witness.Response {
+		return leases.Process(&witness.Request{
+			Bridge:  "vpnham-dev",
+			Holder:  holder,
+			Acquire: acquire,
+		}, ts.Add(after))
+	}
+
+	{ // active acquires the lease, standby just reports in
+		res := req("active", true, 0)
+		assert.True(t, res.Granted)
+		assert.False(t, res.PartnerSeen)
+
+		res = req("standby", false, time.Second)
+		assert.False(t, res.Granted)
+		assert.Equal(t, "active", res.Holder)
+		assert.True(t, res.PartnerSeen)
+	}
+
+	{ // partition between the partners: active keeps on renewing the lease
+		assert.True(t, req("active", true, 5*time.Second).Granted)
+
+		res := req("standby", true, 6*time.Second)
+		assert.False(t, res.Granted, "lease is held by the partner")
+		assert.True(t, res.PartnerSeen, "partner is alive")
+	}
+
+	{ // active is gone: its lease expires, and standby gets it
+		res := req("standby", true, 14*time.Second)
+		assert.False(t, res.Granted)
+		assert.True(t, res.PartnerSeen)
+
+		res = req("standby", true, 16*time.Second)
+		assert.True(t, res.Granted)
+		assert.False(t, res.PartnerSeen)
+	}
+
+	{ // active is back, and can't get the lease until standby releases it
+		res := req("active", true, 17*time.Second)
+		assert.False(t, res.Granted)
+		assert.Equal(t, "standby", res.Holder)
+
+		res = req("standby", false, 18*time.Second)
+		assert.False(t, res.Granted)
+		assert.Equal(t, "", res.Holder)
+
+		assert.True(t, req("active", true, 19*time.Second).Granted)
+	}
+
+	{ // bridges are independent
+		res := leases.Process(&witness.Request{
+			Bridge:  "vpnham-prod",
+			Holder:  "standby",
+			Acquire: true,
+		}, ts.Add(20*time.Second))
+		assert.True(t, res.Granted)
+		assert.False(t, res.PartnerSeen)
+	}
+}
@@ -0,0 +1,115 @@
+package witness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/flashbots/vpnham/httplogger"
+	"github.com/flashbots/vpnham/logutils"
+	"go.uber.org/zap"
+)
+
+// Server is the witness that arbitrates between the partner bridges (so that
+// only one of them self-promotes to active when they can't see each other).
+type Server struct {
+	leases *Leases
+	server *http.Server
+}
+
+const (
+	pathLease = "lease"
+)
+
+func NewServer(ctx context.Context, listenAddr string, leaseDuration time.Duration) *Server {
+	l := logutils.LoggerFromContext(ctx)
+
+	s := &Server{
+		leases: NewLeases(leaseDuration),
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.HandlerFunc(s.healthcheck))
+	mux.Handle("/"+pathLease, http.HandlerFunc(s.handleLease))
+	handler := httplogger.Middleware(l, mux)
+
+	s.server = &http.Server{
+		Addr:              listenAddr,
+		ErrorLog:          logutils.NewHttpServerErrorLogger(l),
+		Handler:           handler,
+		MaxHeaderBytes:    1024,
+		ReadHeaderTimeout: 30 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+
+	return s
+}
+
+func (s *Server) Run(ctx context.Context, failureSink chan<- error) {
+	l := logutils.LoggerFromContext(ctx)
+
+	go func() {
+		l.Info("VPN HA-monitor witness is going up...",
+			zap.String("witness_listen_address", s.server.Addr),
+		)
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			failureSink <- err
+		}
+		l.Info("VPN HA-monitor witness is down")
+	}()
+}
+
+func (s *Server) Stop(ctx context.Context) {
+	l := logutils.LoggerFromContext(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		l.Error("VPN HA-monitor witness shutdown failed",
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *Server) handleLease(w http.ResponseWriter, r *http.Request) {
+	l := logutils.LoggerFromRequest(r)
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	req := &Request{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(req); err != nil || req.Bridge == "" || req.Holder == "" {
+		l.Warn("Invalid lease request",
+			zap.Error(err),
+		)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	res := s.leases.Process(req, time.Now())
+
+	l.Debug("Processed lease request",
+		zap.String("bridge_name", req.Bridge),
+		zap.String("lease_requester", req.Holder),
+		zap.Bool("lease_acquire", req.Acquire),
+		zap.Bool("lease_granted", res.Granted),
+		zap.String("lease_holder", res.Holder),
+	)
+
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		l.Error("Failed to encode and send response body",
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *Server) healthcheck(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}